@@ -0,0 +1,91 @@
+// Command admin manages user roles and exchange rates from the command line,
+// e.g. to create the first administrator.
+//
+// Usage:
+//
+//	go run ./cmd/admin promote john@example.com
+//	go run ./cmd/admin demote john@example.com
+//	go run ./cmd/admin import-rates eurofxref-hist.xml
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/jedi116/kaizen-api/config"
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/exchange"
+	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/joho/godotenv"
+	"gorm.io/gorm"
+)
+
+func main() {
+	if len(os.Args) != 3 || (os.Args[1] != "promote" && os.Args[1] != "demote" && os.Args[1] != "import-rates") {
+		fmt.Fprintln(os.Stderr, "usage: admin promote|demote <email>")
+		fmt.Fprintln(os.Stderr, "       admin import-rates <file.csv|file.xml>")
+		os.Exit(2)
+	}
+	command, arg := os.Args[1], os.Args[2]
+
+	if os.Getenv("ENV") != "production" {
+		godotenv.Load()
+	}
+
+	if err := config.ConnectToDataBase(); err != nil {
+		log.Fatal("Failed to connect to database:", err)
+	}
+	db := config.GetDB()
+
+	if command == "import-rates" {
+		importRates(db, arg)
+		return
+	}
+	setRole(db, command, arg)
+}
+
+func setRole(db *gorm.DB, command, email string) {
+	role := models.RoleAdmin
+	if command == "demote" {
+		role = models.RoleUser
+	}
+
+	var user models.User
+	if err := db.Where("email = ?", email).First(&user).Error; err != nil {
+		log.Fatalf("User %s not found", email)
+	}
+
+	if err := db.Model(&user).Update("role", role).Error; err != nil {
+		log.Fatal("Failed to update role:", err)
+	}
+
+	// Existing tokens still carry the old role
+	if err := auth.RevokeAllUserTokens(user.ID, db); err != nil {
+		log.Fatal("Failed to revoke tokens:", err)
+	}
+
+	log.Printf("%s is now %s\n", email, role)
+}
+
+func importRates(db *gorm.DB, path string) {
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatal("Failed to open rates file:", err)
+	}
+	defer file.Close()
+
+	rates, err := exchange.Parse(file, filepath.Base(path))
+	if err != nil {
+		log.Fatalf("Invalid rates file %s: %v", path, err)
+	}
+
+	imported, err := exchange.Import(context.Background(), db, rates)
+	if err != nil {
+		log.Fatal("Failed to import exchange rates:", err)
+	}
+
+	log.Printf("Imported %d exchange rates from %s\n", imported, path)
+}
@@ -0,0 +1,43 @@
+// Command mockoidc runs a local OpenID Connect issuer for trying out OIDC sign-in.
+// It approves every authorization request as the configured user.
+//
+// Point Kaizen at it with:
+//
+//	OIDC_PROVIDERS=mock
+//	OIDC_MOCK_ISSUER=http://localhost:9000
+//	OIDC_MOCK_CLIENT_ID=kaizen
+//	OIDC_MOCK_CLIENT_SECRET=secret
+//	OIDC_MOCK_REDIRECT_URL=http://localhost:8080/api/auth/oidc/mock/callback
+package main
+
+import (
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/jedi116/kaizen-api/internal/oidc/oidctest"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:9000", "listen address")
+	issuerURL := flag.String("issuer", "http://localhost:9000", "issuer URL as seen by clients")
+	clientID := flag.String("client-id", "kaizen", "accepted client ID")
+	clientSecret := flag.String("client-secret", "secret", "accepted client secret")
+	subject := flag.String("sub", "mock-user-1", "subject of the signed-in user")
+	email := flag.String("email", "mock.user@example.com", "email of the signed-in user")
+	emailVerified := flag.Bool("email-verified", true, "whether the email is reported as verified")
+	flag.Parse()
+
+	issuer, err := oidctest.NewIssuer(*issuerURL, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatal("Failed to create issuer:", err)
+	}
+	issuer.User.Subject = *subject
+	issuer.User.Email = *email
+	issuer.User.EmailVerified = *emailVerified
+
+	log.Printf("Mock OIDC issuer %s listening on %s\n", issuer.URL, *addr)
+	if err := http.ListenAndServe(*addr, issuer.Handler()); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -25,12 +25,21 @@
 package main
 
 import (
+	"context"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/jedi116/kaizen-api/config"
+	"github.com/jedi116/kaizen-api/internal/audit"
+	"github.com/jedi116/kaizen-api/internal/auth"
 	"github.com/jedi116/kaizen-api/internal/http"
+	"github.com/jedi116/kaizen-api/internal/mail"
+	"github.com/jedi116/kaizen-api/internal/oidc"
+	"github.com/jedi116/kaizen-api/internal/scheduler"
 	"github.com/joho/godotenv"
 )
 
@@ -41,6 +50,45 @@ func main() {
 		}
 	}
 
+	// Stop on Ctrl+C or when the platform asks the process to exit
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Load JWT signing keys
+	if err := auth.LoadKeysFromEnv(); err != nil {
+		log.Fatal("Failed to load JWT signing keys:", err)
+	}
+
+	// Configure encryption of two-factor secrets
+	if err := auth.LoadTOTPKeyFromEnv(); err != nil {
+		log.Fatal("Invalid two-factor settings:", err)
+	}
+
+	// Configure login lockout
+	if err := auth.LoadThrottlePoliciesFromEnv(); err != nil {
+		log.Fatal("Invalid login lockout settings:", err)
+	}
+
+	// Configure password requirements
+	if err := auth.LoadPasswordPolicyFromEnv(); err != nil {
+		log.Fatal("Invalid password policy settings:", err)
+	}
+
+	// Configure how long the audit log is kept
+	if err := audit.LoadRetentionFromEnv(); err != nil {
+		log.Fatal("Invalid audit log settings:", err)
+	}
+
+	// Configure API key rotation and usage recording
+	if err := auth.LoadAPIKeySettingsFromEnv(); err != nil {
+		log.Fatal("Invalid API key settings:", err)
+	}
+
+	// Configure the validated token cache
+	if err := auth.LoadTokenCacheFromEnv(); err != nil {
+		log.Fatal("Invalid token cache settings:", err)
+	}
+
 	// Connect to database
 	if err := config.ConnectToDataBase(); err != nil {
 		log.Fatal("Failed to connect to database:", err)
@@ -49,10 +97,96 @@ func main() {
 	// Get database instance
 	db := config.GetDB()
 
+	// Share token revocations with other instances
+	if os.Getenv("TOKEN_REVOCATION_STORE") == "postgres" {
+		auth.UseRevocationStore(ctx, auth.NewPostgresRevocationStore(db))
+	}
+
+	// Record API key usage in batches
+	auth.RunAPIKeyUsageFlusher(ctx, db)
+
+	// Configure outgoing email
+	mailer, err := mail.NewFromEnv()
+	if err != nil {
+		log.Fatal("Failed to configure mailer:", err)
+	}
+
+	// Configure external sign-in providers
+	oidcProviders, err := oidc.LoadProvidersFromEnv()
+	if err != nil {
+		log.Fatal("Invalid OIDC provider settings:", err)
+	}
+
+	// Background maintenance jobs
+	jobs := scheduler.New(db)
+	if os.Getenv("SCHEDULER_ENABLED") != "false" {
+		cleanupSchedule := os.Getenv("TOKEN_CLEANUP_SCHEDULE")
+		if cleanupSchedule == "" {
+			cleanupSchedule = "0 * * * *"
+		}
+		err := jobs.Add(scheduler.Job{
+			Name:     "cleanup_expired_tokens",
+			Schedule: cleanupSchedule,
+			Jitter:   time.Minute,
+			Run: func(ctx context.Context) error {
+				return auth.CleanupExpiredTokens(db.WithContext(ctx))
+			},
+		})
+		if err != nil {
+			log.Fatal("Invalid scheduler settings:", err)
+		}
+		oneTimeCleanupSchedule := os.Getenv("ONE_TIME_TOKEN_CLEANUP_SCHEDULE")
+		if oneTimeCleanupSchedule == "" {
+			oneTimeCleanupSchedule = "0 * * * *"
+		}
+		err = jobs.Add(scheduler.Job{
+			Name:     "cleanup_one_time_tokens",
+			Schedule: oneTimeCleanupSchedule,
+			Jitter:   time.Minute,
+			Run: func(ctx context.Context) error {
+				return auth.CleanupOneTimeTokens(db.WithContext(ctx))
+			},
+		})
+		if err != nil {
+			log.Fatal("Invalid scheduler settings:", err)
+		}
+		err = jobs.Add(scheduler.Job{
+			Name:     "cleanup_login_throttles",
+			Schedule: "@hourly",
+			Jitter:   time.Minute,
+			Run: func(ctx context.Context) error {
+				return auth.CleanupLoginThrottles(db.WithContext(ctx))
+			},
+		})
+		if err != nil {
+			log.Fatal("Invalid scheduler settings:", err)
+		}
+		err = jobs.Add(scheduler.Job{
+			Name:     "prune_audit_events",
+			Schedule: "@daily",
+			Jitter:   10 * time.Minute,
+			Run: func(ctx context.Context) error {
+				return audit.Prune(ctx, db)
+			},
+		})
+		if err != nil {
+			log.Fatal("Invalid scheduler settings:", err)
+		}
+		jobs.Start(ctx)
+	}
+
+	// Only trust forwarded client IPs from known proxies
+	engine := gin.Default()
+	if err := http.ConfigureTrustedProxies(engine); err != nil {
+		log.Fatal("Invalid trusted proxy settings:", err)
+	}
+
 	// Create server with database connection
 	server := http.KaizenServer{
-		GinEngine: gin.Default(),
-		DB:        db,
+		GinEngine:     engine,
+		DB:            db,
+		Mailer:        mailer,
+		OIDCProviders: oidcProviders,
 	}
 
 	server.RegisterRoutes()
@@ -63,7 +197,16 @@ func main() {
 	}
 	log.Printf("🚀 Server starting on port %s\n", port)
 
-	if err := server.Start(); err != nil {
+	if err := server.Start(ctx); err != nil {
 		log.Fatal("Could not start the server: ", err)
 	}
+
+	// Let running jobs finish and write the last API key usage before closing the database
+	jobs.Stop()
+	if err := auth.FlushAPIKeyUsage(context.Background(), db); err != nil {
+		log.Println("Failed to record API key usage:", err)
+	}
+	if err := config.Close(); err != nil {
+		log.Println("Failed to close database:", err)
+	}
 }
@@ -0,0 +1,112 @@
+// internal/audit/audit.go
+package audit
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+// Event types
+const (
+	EventRegister             = "register"
+	EventLogin                = "login"
+	EventTwoFactorVerify      = "two_factor_verify"
+	EventRefresh              = "refresh"
+	EventRefreshTokenReuse    = "refresh_token_reuse"
+	EventLogout               = "logout"
+	EventLogoutAll            = "logout_all"
+	EventSessionRevoked       = "session_revoked"
+	EventEmailVerified        = "email_verified"
+	EventEmailChangeRequested = "email_change_requested"
+	EventEmailChanged         = "email_changed"
+	EventPasswordChanged      = "password_changed"
+	EventPasswordResetRequest = "password_reset_requested"
+	EventMagicLinkRequested   = "magic_link_requested"
+	EventPasswordReset        = "password_reset"
+	EventTwoFactorEnabled     = "two_factor_enabled"
+	EventTwoFactorDisabled    = "two_factor_disabled"
+	EventIdentityLinked       = "identity_linked"
+	EventProfileUpdated       = "profile_updated"
+	EventAPIKeyCreated        = "api_key_created"
+	EventAPIKeyDeleted        = "api_key_deleted"
+	EventAPIKeyRotated        = "api_key_rotated"
+	EventAPIKeyUpdated        = "api_key_updated"
+	EventAccountDisabled      = "account_disabled"
+	EventAccountEnabled       = "account_enabled"
+	EventAccountUnlocked      = "account_unlocked"
+	EventRoleChanged          = "role_changed"
+	EventAccountDeleted       = "account_deleted"
+)
+
+// Retention is how long events are kept before Prune deletes them
+var Retention = 365 * 24 * time.Hour
+
+// Event describes something that happened to an account
+type Event struct {
+	Type    string
+	Failed  bool   // Recorded with a "failure" outcome
+	UserID  uint   // Account the event concerns, 0 if there is none (e.g. a login for an unknown email)
+	ActorID uint   // Who did it; defaults to the request's authenticated user
+	Email   string // Email the request named, for events without a known account
+	Details string
+}
+
+// Record stores event with the request's IP address, user agent and
+// authenticated user. The request carries on if the event can't be stored.
+func Record(c *gin.Context, db *gorm.DB, event Event) {
+	meta := auth.SessionMetaFromRequest(c)
+	record := models.AuditEvent{
+		Email:      event.Email,
+		Type:       event.Type,
+		Outcome:    models.AuditOutcomeSuccess,
+		IPAddress:  meta.IPAddress,
+		UserAgent:  meta.UserAgent,
+		Details:    event.Details,
+		AuthMethod: c.GetString("auth_method"),
+	}
+	if event.Failed {
+		record.Outcome = models.AuditOutcomeFailure
+	}
+	if event.UserID != 0 {
+		record.UserID = &event.UserID
+	}
+
+	actorID := event.ActorID
+	if actorID == 0 {
+		actorID, _ = auth.GetUserID(c)
+	}
+	if actorID != 0 {
+		record.ActorID = &actorID
+	}
+
+	if err := db.Create(&record).Error; err != nil {
+		log.Printf("failed to record %s audit event: %v", event.Type, err)
+	}
+}
+
+// LoadRetentionFromEnv overrides Retention from AUDIT_RETENTION_DAYS
+func LoadRetentionFromEnv() error {
+	if v := os.Getenv("AUDIT_RETENTION_DAYS"); v != "" {
+		days, err := strconv.Atoi(v)
+		if err != nil || days < 1 {
+			return errors.New("AUDIT_RETENTION_DAYS must be a positive integer")
+		}
+		Retention = time.Duration(days) * 24 * time.Hour
+	}
+	return nil
+}
+
+// Prune deletes events older than Retention
+func Prune(ctx context.Context, db *gorm.DB) error {
+	return db.WithContext(ctx).Where("created_at < ?", time.Now().Add(-Retention)).Delete(&models.AuditEvent{}).Error
+}
@@ -0,0 +1,65 @@
+// internal/auth/action_token.go
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Purposes for action tokens
+const (
+	PurposeEmailVerification = "email_verification"
+)
+
+var (
+	EmailVerificationDuration = 24 * time.Hour
+)
+
+// ActionClaims are carried by short-lived, single-purpose tokens such as
+// email verification links. They are signed but never stored.
+type ActionClaims struct {
+	UserID  uint   `json:"user_id"`
+	Email   string `json:"email"`
+	Purpose string `json:"purpose"`
+	jwt.RegisteredClaims
+}
+
+// GenerateActionToken signs a token that is only accepted for purpose
+func GenerateActionToken(userID uint, email, purpose string, duration time.Duration) (string, error) {
+	claims := &ActionClaims{
+		UserID:  userID,
+		Email:   email,
+		Purpose: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    TokenIssuer,
+			Audience:  jwt.ClaimStrings{AudienceAction},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	return signToken(claims)
+}
+
+// ValidateActionToken parses an action token and checks it was issued for purpose
+func ValidateActionToken(tokenString, purpose string) (*ActionClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &ActionClaims{}, verificationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*ActionClaims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	if err := checkAudience(claims.Audience, AudienceAction); err != nil {
+		return nil, err
+	}
+	if claims.Purpose != purpose {
+		return nil, fmt.Errorf("token was not issued for %s", purpose)
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,174 @@
+// internal/auth/api_key.go
+package auth
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+var (
+	// APIKeyRotationGrace is how long a rotated key's old secret keeps working by default
+	APIKeyRotationGrace = 24 * time.Hour
+	// MaxAPIKeyRotationGrace caps the grace period a rotation can ask for
+	MaxAPIKeyRotationGrace = 7 * 24 * time.Hour
+	// APIKeyUsageFlushInterval is how often buffered API key usage is written to the database
+	APIKeyUsageFlushInterval = 10 * time.Second
+)
+
+// LoadAPIKeySettingsFromEnv overrides the default rotation grace period from
+// API_KEY_ROTATION_GRACE and the usage flush interval from API_KEY_USAGE_FLUSH_INTERVAL
+func LoadAPIKeySettingsFromEnv() error {
+	if v := os.Getenv("API_KEY_ROTATION_GRACE"); v != "" {
+		grace, err := time.ParseDuration(v)
+		if err != nil || grace < 0 || grace > MaxAPIKeyRotationGrace {
+			return errors.New("API_KEY_ROTATION_GRACE must be a duration between 0 and 168h")
+		}
+		APIKeyRotationGrace = grace
+	}
+	if v := os.Getenv("API_KEY_USAGE_FLUSH_INTERVAL"); v != "" {
+		interval, err := time.ParseDuration(v)
+		if err != nil || interval < time.Second {
+			return errors.New("API_KEY_USAGE_FLUSH_INTERVAL must be a duration of at least 1s")
+		}
+		APIKeyUsageFlushInterval = interval
+	}
+	return nil
+}
+
+// apiKeyUsage buffers API key requests so each request costs no database
+// write; the counts are flushed in batches
+var apiKeyUsage = &usageBuffer{counts: make(map[usageBucket]int64), lastUsed: make(map[uint]time.Time)}
+
+type usageBucket struct {
+	keyID uint
+	day   time.Time
+}
+
+type usageBuffer struct {
+	mu       sync.Mutex
+	counts   map[usageBucket]int64
+	lastUsed map[uint]time.Time
+}
+
+func (b *usageBuffer) record(keyID uint, at time.Time) {
+	day := at.UTC().Truncate(24 * time.Hour)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.counts[usageBucket{keyID, day}]++
+	if at.After(b.lastUsed[keyID]) {
+		b.lastUsed[keyID] = at
+	}
+}
+
+// take empties the buffer, returning what it held
+func (b *usageBuffer) take() (map[usageBucket]int64, map[uint]time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	counts, lastUsed := b.counts, b.lastUsed
+	b.counts = make(map[usageBucket]int64)
+	b.lastUsed = make(map[uint]time.Time)
+	return counts, lastUsed
+}
+
+// restore puts back usage that could not be written, so the next flush retries it
+func (b *usageBuffer) restore(counts map[usageBucket]int64, lastUsed map[uint]time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for bucket, count := range counts {
+		b.counts[bucket] += count
+	}
+	for keyID, at := range lastUsed {
+		if at.After(b.lastUsed[keyID]) {
+			b.lastUsed[keyID] = at
+		}
+	}
+}
+
+// RunAPIKeyUsageFlusher writes buffered API key usage every
+// APIKeyUsageFlushInterval until ctx is done. Call FlushAPIKeyUsage once more
+// after the server has stopped, so the last requests are counted too.
+func RunAPIKeyUsageFlusher(ctx context.Context, db *gorm.DB) {
+	go func() {
+		ticker := time.NewTicker(APIKeyUsageFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := FlushAPIKeyUsage(ctx, db); err != nil {
+					log.Printf("failed to record API key usage, will retry: %v", err)
+				}
+			}
+		}
+	}()
+}
+
+// FlushAPIKeyUsage adds buffered request counts to the daily totals and
+// advances each key's last_used_at. On failure the usage stays buffered.
+func FlushAPIKeyUsage(ctx context.Context, db *gorm.DB) error {
+	counts, lastUsed := apiKeyUsage.take()
+	if len(counts) == 0 {
+		return nil
+	}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Keys deleted along with their user since being used are skipped, or the batch would never succeed
+		keyIDs := make([]uint, 0, len(lastUsed))
+		for keyID := range lastUsed {
+			keyIDs = append(keyIDs, keyID)
+		}
+		var existing []uint
+		if err := tx.Model(&models.APIKey{}).Unscoped().Where("id IN ?", keyIDs).Pluck("id", &existing).Error; err != nil {
+			return err
+		}
+		exists := make(map[uint]bool, len(existing))
+		for _, keyID := range existing {
+			exists[keyID] = true
+		}
+
+		usages := make([]models.APIKeyUsage, 0, len(counts))
+		for bucket, count := range counts {
+			if exists[bucket.keyID] {
+				usages = append(usages, models.APIKeyUsage{APIKeyID: bucket.keyID, Day: bucket.day, RequestCount: count})
+			}
+		}
+		if len(usages) == 0 {
+			return nil
+		}
+
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "api_key_id"}, {Name: "day"}},
+			DoUpdates: clause.Assignments(map[string]interface{}{
+				"request_count": gorm.Expr("api_key_usages.request_count + excluded.request_count"),
+			}),
+		}).Create(&usages).Error
+		if err != nil {
+			return err
+		}
+
+		for keyID, at := range lastUsed {
+			err := tx.Model(&models.APIKey{}).
+				Where("id = ? AND (last_used_at IS NULL OR last_used_at < ?)", keyID, at).
+				Update("last_used_at", at).Error
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		apiKeyUsage.restore(counts, lastUsed)
+	}
+	return err
+}
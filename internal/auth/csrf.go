@@ -0,0 +1,68 @@
+// internal/auth/csrf.go
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Double-submit CSRF protection: the csrf_token cookie is readable by the web
+// client, which echoes it in the X-CSRF-Token header. Another site can make the
+// browser send the cookie, but can't read it to set the header.
+const (
+	CSRFCookieName = "csrf_token"
+	CSRFHeaderName = "X-CSRF-Token"
+)
+
+// NewCSRFToken returns a random token for the csrf_token cookie
+func NewCSRFToken() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// CheckCSRF reports whether a request authenticated by cookie may proceed:
+// safe methods always may, anything else needs an X-CSRF-Token header matching
+// the csrf_token cookie
+func CheckCSRF(c *gin.Context) bool {
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+
+	cookie, err := c.Cookie(CSRFCookieName)
+	if err != nil || cookie == "" {
+		return false
+	}
+	header := c.GetHeader(CSRFHeaderName)
+	return subtle.ConstantTimeCompare([]byte(cookie), []byte(header)) == 1
+}
+
+// CheckOrigin reports whether the request's Origin header names the API itself
+// or one of ALLOWED_ORIGINS. Browsers send Origin with every cross-site POST, so
+// a forged request can't pass; a request without the header doesn't either.
+func CheckOrigin(c *gin.Context) bool {
+	origin := c.GetHeader("Origin")
+	u, err := url.Parse(origin)
+	if origin == "" || err != nil || u.Host == "" {
+		return false
+	}
+	if u.Host == c.Request.Host {
+		return true
+	}
+	for _, allowed := range strings.Split(os.Getenv("ALLOWED_ORIGINS"), ",") {
+		if strings.TrimSpace(allowed) == origin {
+			return true
+		}
+	}
+	return false
+}
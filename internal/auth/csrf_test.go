@@ -0,0 +1,71 @@
+// internal/auth/csrf_test.go
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func csrfTestContext(method string, headers map[string]string, cookies ...*http.Cookie) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(method, "http://api.example.com/api/auth/refresh", nil)
+	for name, value := range headers {
+		c.Request.Header.Set(name, value)
+	}
+	for _, cookie := range cookies {
+		c.Request.AddCookie(cookie)
+	}
+	return c
+}
+
+func TestCheckCSRF(t *testing.T) {
+	cookie := &http.Cookie{Name: CSRFCookieName, Value: "token"}
+	tests := []struct {
+		name    string
+		method  string
+		header  string
+		cookies []*http.Cookie
+		want    bool
+	}{
+		{"safe method", http.MethodGet, "", nil, true},
+		{"matching header", http.MethodPost, "token", []*http.Cookie{cookie}, true},
+		{"wrong header", http.MethodPost, "other", []*http.Cookie{cookie}, false},
+		{"no header", http.MethodPost, "", []*http.Cookie{cookie}, false},
+		{"no cookie", http.MethodPost, "token", nil, false},
+	}
+	for _, tt := range tests {
+		c := csrfTestContext(tt.method, map[string]string{CSRFHeaderName: tt.header}, tt.cookies...)
+		if got := CheckCSRF(c); got != tt.want {
+			t.Errorf("%s: CheckCSRF = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCheckOrigin(t *testing.T) {
+	t.Setenv("ALLOWED_ORIGINS", "http://localhost:3000, https://app.example.com")
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"http://api.example.com", true},
+		{"https://api.example.com", true},
+		{"https://app.example.com", true},
+		{"http://localhost:3000", true},
+		{"http://localhost:3001", false},
+		{"https://evil.example", false},
+		{"https://app.example.com.evil.example", false},
+		{"null", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		c := csrfTestContext(http.MethodPost, map[string]string{"Origin": tt.origin})
+		if got := CheckOrigin(c); got != tt.want {
+			t.Errorf("CheckOrigin with Origin %q = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
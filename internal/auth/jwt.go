@@ -1,8 +1,11 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
-	"os"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -13,12 +16,30 @@ import (
 var (
 	AccessTokenDuration  = 15 * time.Minute
 	RefreshTokenDuration = 7 * 24 * time.Hour
-	jwtSecret            = []byte(os.Getenv("JWT_SECRET"))
+)
+
+// Every token is signed with the same published keys, so its audience says
+// what it is for. Services verifying tokens against the JWKS must only accept
+// AudienceAPI.
+const (
+	TokenIssuer       = "kaizen"
+	AudienceAPI       = "kaizen-api"        // Access tokens
+	AudienceRefresh   = "kaizen-refresh"    // Refresh tokens
+	AudienceAction    = "kaizen-action"     // Single-purpose tokens such as email verification links
+	AudienceOIDCState = "kaizen-oidc-state" // OpenID Connect authorization request state
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid refresh token")
+	ErrRefreshTokenReused  = errors.New("refresh token reuse detected")
+	ErrAccountDisabled     = errors.New("account is disabled")
 )
 
 type Claims struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
+	UserID   uint   `json:"user_id"`
+	Email    string `json:"email"`
+	Role     string `json:"role,omitempty"`
+	FamilyID string `json:"fid,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -27,22 +48,49 @@ type TokenPair struct {
 	RefreshToken string `json:"refresh_token"`
 }
 
-// GenerateTokenPair creates both access and refresh tokens
-func GenerateTokenPair(userID uint, email string, db *gorm.DB) (*TokenPair, error) {
+// GenerateTokenPair creates both access and refresh tokens for a new session.
+// The session ID doubles as the token family ID.
+func GenerateTokenPair(user *models.User, meta SessionMeta, db *gorm.DB) (*TokenPair, error) {
+	familyID, err := newFamilyID()
+	if err != nil {
+		return nil, err
+	}
+
+	var tokens *TokenPair
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := startSession(user.ID, familyID, meta, tx); err != nil {
+			return err
+		}
+		tokens, err = generateTokenPair(user, familyID, tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+func generateTokenPair(user *models.User, familyID string, db *gorm.DB) (*TokenPair, error) {
+	userID := user.ID
+
 	// Access Token
 	accessJTI := fmt.Sprintf("access_%d_%d", userID, time.Now().UnixNano())
 	accessClaims := &Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:   userID,
+		Email:    user.Email,
+		Role:     user.Role,
+		FamilyID: familyID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    TokenIssuer,
+			Audience:  jwt.ClaimStrings{AudienceAPI},
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(AccessTokenDuration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ID:        accessJTI,
 		},
 	}
 
-	accessToken := jwt.NewWithClaims(jwt.SigningMethodHS256, accessClaims)
-	accessTokenString, err := accessToken.SignedString(jwtSecret)
+	accessTokenString, err := signToken(accessClaims)
 	if err != nil {
 		return nil, err
 	}
@@ -50,17 +98,20 @@ func GenerateTokenPair(userID uint, email string, db *gorm.DB) (*TokenPair, erro
 	// Refresh Token
 	refreshJTI := fmt.Sprintf("refresh_%d_%d", userID, time.Now().UnixNano())
 	refreshClaims := &Claims{
-		UserID: userID,
-		Email:  email,
+		UserID:   userID,
+		Email:    user.Email,
+		Role:     user.Role,
+		FamilyID: familyID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    TokenIssuer,
+			Audience:  jwt.ClaimStrings{AudienceRefresh},
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(RefreshTokenDuration)),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			ID:        refreshJTI,
 		},
 	}
 
-	refreshToken := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshClaims)
-	refreshTokenString, err := refreshToken.SignedString(jwtSecret)
+	refreshTokenString, err := signToken(refreshClaims)
 	if err != nil {
 		return nil, err
 	}
@@ -70,12 +121,14 @@ func GenerateTokenPair(userID uint, email string, db *gorm.DB) (*TokenPair, erro
 		{
 			ID:        accessJTI,
 			UserID:    userID,
+			FamilyID:  familyID,
 			Type:      "access",
 			ExpiresAt: time.Now().Add(AccessTokenDuration),
 		},
 		{
 			ID:        refreshJTI,
 			UserID:    userID,
+			FamilyID:  familyID,
 			Type:      "refresh",
 			ExpiresAt: time.Now().Add(RefreshTokenDuration),
 		},
@@ -91,23 +144,18 @@ func GenerateTokenPair(userID uint, email string, db *gorm.DB) (*TokenPair, erro
 	}, nil
 }
 
-// ValidateToken validates and parses a JWT token
+// ValidateToken validates and parses a JWT token. Tokens recently found in the
+// database are trusted for up to TokenCacheTTL without another query.
 func ValidateToken(tokenString string, db *gorm.DB) (*Claims, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return jwtSecret, nil
-	})
-
+	claims, err := parseToken(tokenString, AudienceAPI)
 	if err != nil {
 		return nil, err
 	}
 
-	claims, ok := token.Claims.(*Claims)
-	if !ok || !token.Valid {
-		return nil, fmt.Errorf("invalid token")
+	if tokens.valid(claims.ID) {
+		return claims, nil
 	}
+	generation := tokens.currentGeneration()
 
 	// Check if token exists and is not expired in database
 	var dbToken models.Token
@@ -119,17 +167,141 @@ func ValidateToken(tokenString string, db *gorm.DB) (*Claims, error) {
 		return nil, fmt.Errorf("token has expired")
 	}
 
+	// The stored type has to agree with the audience
+	if dbToken.Type != "access" {
+		return nil, fmt.Errorf("not an access token")
+	}
+
+	if dbToken.RotatedAt != nil {
+		return nil, fmt.Errorf("token has been rotated")
+	}
+
+	tokens.add(dbToken.ID, dbToken.UserID, dbToken.FamilyID, dbToken.ExpiresAt, generation)
+
 	return claims, nil
 }
 
+// RotateRefreshToken exchanges a refresh token for a new pair in the same family.
+// The presented token is marked as rotated and the family's access tokens are revoked.
+// If the token was already rotated, it has been replayed: the whole family is
+// revoked and ErrRefreshTokenReused is returned along with the token's claims.
+// The new tokens carry the user's current email and role. Tokens that can't be
+// refreshed return an error wrapping ErrInvalidRefreshToken; any other error
+// is a database failure.
+func RotateRefreshToken(tokenString string, meta SessionMeta, db *gorm.DB) (*TokenPair, *Claims, error) {
+	claims, err := parseToken(tokenString, AudienceRefresh)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidRefreshToken, err)
+	}
+
+	var dbToken models.Token
+	if err := db.Where("id = ? AND type = ?", claims.ID, "refresh").First(&dbToken).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil, fmt.Errorf("%w: token not found or revoked", ErrInvalidRefreshToken)
+	} else if err != nil {
+		return nil, nil, err
+	}
+
+	if dbToken.IsExpired() {
+		return nil, nil, fmt.Errorf("%w: token has expired", ErrInvalidRefreshToken)
+	}
+
+	// Everything up to the new pair is one transaction, so a failure part way
+	// leaves the presented token unrotated and the client can retry with it
+	var tokens *TokenPair
+	err = db.Transaction(func(tx *gorm.DB) error {
+		// Conditional update, so two concurrent refreshes with one token count as reuse
+		result := tx.Model(&models.Token{}).
+			Where("id = ? AND rotated_at IS NULL", dbToken.ID).
+			Update("rotated_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrRefreshTokenReused
+		}
+
+		// The access token issued alongside the old refresh token is no longer needed
+		if err := tx.Where("family_id = ? AND type = ?", dbToken.FamilyID, "access").Delete(&models.Token{}).Error; err != nil {
+			return err
+		}
+
+		var user models.User
+		if err := tx.First(&user, claims.UserID).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("%w: user not found", ErrInvalidRefreshToken)
+		} else if err != nil {
+			return err
+		}
+		if user.IsDisabled() {
+			return ErrAccountDisabled
+		}
+
+		tokens, err = generateTokenPair(&user, dbToken.FamilyID, tx)
+		if err != nil {
+			return err
+		}
+		return touchSession(dbToken.FamilyID, meta, tx)
+	})
+	if errors.Is(err, ErrRefreshTokenReused) {
+		if err := RevokeTokenFamily(dbToken.FamilyID, db); err != nil {
+			return nil, nil, err
+		}
+		return nil, claims, ErrRefreshTokenReused
+	}
+	if errors.Is(err, ErrAccountDisabled) {
+		if err := RevokeTokenFamily(dbToken.FamilyID, db); err != nil {
+			return nil, nil, err
+		}
+		return nil, nil, ErrAccountDisabled
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	revoked(Revocation{FamilyID: dbToken.FamilyID})
+
+	return tokens, claims, nil
+}
+
+// parseToken verifies a token's signature (using the key named by its kid),
+// expiry and audience without checking the database
+func parseToken(tokenString, audience string) (*Claims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, verificationKey)
+
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*Claims)
+	if !ok || !token.Valid {
+		return nil, fmt.Errorf("invalid token")
+	}
+	if err := checkAudience(claims.Audience, audience); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
+
+// checkAudience rejects tokens issued for another audience or for none
+func checkAudience(tokenAudience jwt.ClaimStrings, audience string) error {
+	if slices.Contains(tokenAudience, audience) {
+		return nil
+	}
+	return fmt.Errorf("token was not issued for %s", audience)
+}
+
 // RevokeToken revokes a token by deleting it from database
 func RevokeToken(tokenString string, db *gorm.DB) error {
-	token, _ := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+	// Expired tokens can still be revoked, so only the signature has to be valid
+	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, verificationKey, jwt.WithoutClaimsValidation())
+	if err != nil {
+		return nil
+	}
 
 	if claims, ok := token.Claims.(*Claims); ok {
-		return db.Where("id = ?", claims.ID).Delete(&models.Token{}).Error
+		if err := db.Where("id = ?", claims.ID).Delete(&models.Token{}).Error; err != nil {
+			return err
+		}
+		revoked(Revocation{TokenID: claims.ID})
 	}
 
 	return nil
@@ -137,10 +309,48 @@ func RevokeToken(tokenString string, db *gorm.DB) error {
 
 // RevokeAllUserTokens revokes all tokens for a user (logout from all devices)
 func RevokeAllUserTokens(userID uint, db *gorm.DB) error {
-	return db.Where("user_id = ?", userID).Delete(&models.Token{}).Error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&models.Token{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ?", userID).Delete(&models.Session{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	revoked(Revocation{UserID: userID})
+	return nil
 }
 
-// CleanupExpiredTokens removes expired tokens (run this periodically)
+// RevokeTokenFamily revokes every token descended from the same login and ends its session
+func RevokeTokenFamily(familyID string, db *gorm.DB) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("family_id = ?", familyID).Delete(&models.Token{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", familyID).Delete(&models.Session{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	revoked(Revocation{FamilyID: familyID})
+	return nil
+}
+
+// CleanupExpiredTokens removes expired tokens and sessions (run this periodically)
 func CleanupExpiredTokens(db *gorm.DB) error {
-	return db.Where("expires_at < ?", time.Now()).Delete(&models.Token{}).Error
+	if err := db.Where("expires_at < ?", time.Now()).Delete(&models.Token{}).Error; err != nil {
+		return err
+	}
+	return db.Where("expires_at < ?", time.Now()).Delete(&models.Session{}).Error
+}
+
+func newFamilyID() (string, error) {
+	bytes := make([]byte, 16)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(bytes), nil
 }
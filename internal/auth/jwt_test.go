@@ -0,0 +1,226 @@
+// internal/auth/jwt_test.go
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+func configureTestKeys(t *testing.T) {
+	t.Helper()
+	key, err := GenerateEd25519Key("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ks, err := NewKeySet([]*JWTKey{key}, key.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := keySet
+	ConfigureKeys(ks)
+	t.Cleanup(func() { keySet = previous })
+}
+
+func signTestClaims(t *testing.T, audience ...string) string {
+	t.Helper()
+	token, err := signToken(&Claims{
+		UserID: 1,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  audience,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			ID:        "test",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestParseTokenChecksAudience(t *testing.T) {
+	configureTestKeys(t)
+
+	for _, tc := range []struct {
+		name     string
+		token    string
+		audience string
+		ok       bool
+	}{
+		{"access as access", signTestClaims(t, AudienceAPI), AudienceAPI, true},
+		{"refresh as refresh", signTestClaims(t, AudienceRefresh), AudienceRefresh, true},
+		{"refresh as access", signTestClaims(t, AudienceRefresh), AudienceAPI, false},
+		{"access as refresh", signTestClaims(t, AudienceAPI), AudienceRefresh, false},
+		{"action as access", signTestClaims(t, AudienceAction), AudienceAPI, false},
+		{"oidc state as access", signTestClaims(t, AudienceOIDCState), AudienceAPI, false},
+		{"no audience", signTestClaims(t), AudienceAPI, false},
+		{"no audience as refresh", signTestClaims(t), AudienceRefresh, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseToken(tc.token, tc.audience)
+			if ok := err == nil; ok != tc.ok {
+				t.Errorf("parseToken(%s) error = %v, want ok %v", tc.audience, err, tc.ok)
+			}
+		})
+	}
+}
+
+func TestActionTokensAreNotAccessTokens(t *testing.T) {
+	configureTestKeys(t)
+
+	token, err := GenerateActionToken(1, "john@example.com", PurposeEmailVerification, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateActionToken(token, PurposeEmailVerification); err != nil {
+		t.Fatalf("ValidateActionToken: %v", err)
+	}
+	if _, err := parseToken(token, AudienceAPI); err == nil {
+		t.Fatal("action token accepted as an access token")
+	}
+
+	// Access tokens aren't accepted as action tokens either, whatever their claims
+	if _, err := ValidateActionToken(signTestClaims(t, AudienceAPI), PurposeEmailVerification); err == nil {
+		t.Fatal("access token accepted as an action token")
+	}
+
+	// Nor are tokens without an audience, even with the right purpose
+	unscoped, err := signToken(&ActionClaims{
+		UserID:  1,
+		Purpose: PurposeEmailVerification,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ValidateActionToken(unscoped, PurposeEmailVerification); err == nil {
+		t.Fatal("token without an audience accepted as an action token")
+	}
+}
+
+// testDB opens TEST_DATABASE_URL (a migrated database). Unlike the handler
+// tests, these commit, so concurrent transactions really race each other.
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// testUser creates a user that is deleted, along with its tokens and sessions,
+// when the test ends
+func testUser(t *testing.T, db *gorm.DB) *models.User {
+	t.Helper()
+	user := models.User{Name: "Test", Email: fmt.Sprintf("auth-%d@example.com", time.Now().UnixNano()), Password: "x"}
+	if err := db.Create(&user).Error; err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Unscoped().Delete(&models.User{}, user.ID) })
+	return &user
+}
+
+func TestRotateRefreshTokenReplayRevokesFamily(t *testing.T) {
+	configureTestKeys(t)
+	db := testDB(t)
+	user := testUser(t, db)
+
+	first, err := GenerateTokenPair(user, SessionMeta{}, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, _, err := RotateRefreshToken(first.RefreshToken, SessionMeta{}, db)
+	if err != nil {
+		t.Fatalf("RotateRefreshToken: %v", err)
+	}
+	if _, err := ValidateToken(second.AccessToken, db); err != nil {
+		t.Fatalf("rotated access token rejected: %v", err)
+	}
+
+	_, claims, err := RotateRefreshToken(first.RefreshToken, SessionMeta{}, db)
+	if !errors.Is(err, ErrRefreshTokenReused) {
+		t.Fatalf("replaying a refresh token returned %v, want ErrRefreshTokenReused", err)
+	}
+	if claims == nil || claims.UserID != user.ID {
+		t.Errorf("replay returned claims %+v, want the token's", claims)
+	}
+
+	// Whoever holds the newer pair is signed out too
+	if _, err := ValidateToken(second.AccessToken, db); err == nil {
+		t.Error("access token of a revoked family still valid")
+	}
+	if _, _, err := RotateRefreshToken(second.RefreshToken, SessionMeta{}, db); !errors.Is(err, ErrInvalidRefreshToken) {
+		t.Errorf("refresh token of a revoked family returned %v, want ErrInvalidRefreshToken", err)
+	}
+	var sessions int64
+	db.Model(&models.Session{}).Where("user_id = ?", user.ID).Count(&sessions)
+	if sessions != 0 {
+		t.Errorf("%d sessions left after reuse, want 0", sessions)
+	}
+}
+
+func TestRotateRefreshTokenConcurrently(t *testing.T) {
+	configureTestKeys(t)
+	db := testDB(t)
+	user := testUser(t, db)
+
+	pair, err := GenerateTokenPair(user, SessionMeta{}, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		wg      sync.WaitGroup
+		start   = make(chan struct{})
+		rotated [2]*TokenPair
+		errs    [2]error
+	)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			<-start
+			rotated[i], _, errs[i] = RotateRefreshToken(pair.RefreshToken, SessionMeta{}, db)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	// One refresh wins; the other counts as reuse and revokes the winner's pair
+	var winner *TokenPair
+	reused := 0
+	for i, err := range errs {
+		switch {
+		case err == nil:
+			winner = rotated[i]
+		case errors.Is(err, ErrRefreshTokenReused):
+			reused++
+		default:
+			t.Fatalf("RotateRefreshToken: %v", err)
+		}
+	}
+	if winner == nil || reused != 1 {
+		t.Fatalf("got errors %v, want one success and one ErrRefreshTokenReused", errs)
+	}
+	if _, err := ValidateToken(winner.AccessToken, db); err == nil {
+		t.Error("access token from the winning refresh still valid after reuse")
+	}
+}
@@ -0,0 +1,267 @@
+// internal/auth/keys.go
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const minRSAKeyBits = 2048
+
+// JWTKey is a key tokens are signed or verified with, identified by its kid.
+// Retired keys only have a public key and are kept to verify older tokens.
+type JWTKey struct {
+	ID         string
+	Method     jwt.SigningMethod
+	PrivateKey crypto.PrivateKey // nil for verification-only keys
+	PublicKey  crypto.PublicKey
+}
+
+// KeySet holds every key tokens may be verified with and the one new tokens are signed with
+type KeySet struct {
+	active *JWTKey
+	keys   map[string]*JWTKey
+}
+
+// JWK is a public key in JSON Web Key format (RFC 7517)
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+// JWKSet is the document served at /.well-known/jwks.json
+type JWKSet struct {
+	Keys []JWK `json:"keys"`
+}
+
+var keySet *KeySet
+
+// NewKeySet builds a key set that signs with the key identified by activeID
+func NewKeySet(keys []*JWTKey, activeID string) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*JWTKey, len(keys))}
+	for _, key := range keys {
+		if _, exists := ks.keys[key.ID]; exists {
+			return nil, fmt.Errorf("duplicate key id %q", key.ID)
+		}
+		ks.keys[key.ID] = key
+	}
+
+	active, ok := ks.keys[activeID]
+	if !ok {
+		return nil, fmt.Errorf("active key %q not found", activeID)
+	}
+	if active.PrivateKey == nil {
+		return nil, fmt.Errorf("active key %q has no private key", activeID)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+// Active returns the key new tokens are signed with
+func (ks *KeySet) Active() *JWTKey {
+	return ks.active
+}
+
+// Lookup returns the key with the given kid
+func (ks *KeySet) Lookup(kid string) (*JWTKey, bool) {
+	key, ok := ks.keys[kid]
+	return key, ok
+}
+
+// JWKS returns the public half of every key, sorted by kid
+func (ks *KeySet) JWKS() JWKSet {
+	set := JWKSet{Keys: make([]JWK, 0, len(ks.keys))}
+	for _, key := range ks.keys {
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+		switch pub := key.PublicKey.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+	sort.Slice(set.Keys, func(i, j int) bool { return set.Keys[i].Kid < set.Keys[j].Kid })
+	return set
+}
+
+// ConfigureKeys sets the key set used to sign and verify tokens
+func ConfigureKeys(ks *KeySet) {
+	keySet = ks
+}
+
+// PublicJWKS returns the configured public keys for the JWKS endpoint
+func PublicJWKS() JWKSet {
+	if keySet == nil {
+		return JWKSet{Keys: []JWK{}}
+	}
+	return keySet.JWKS()
+}
+
+// LoadKeysFromEnv configures signing keys from JWT_KEYS_DIR, signing with JWT_ACTIVE_KID.
+// Outside production, if no directory is set, an ephemeral Ed25519 key is generated;
+// tokens signed with it stop validating when the process restarts.
+func LoadKeysFromEnv() error {
+	dir := os.Getenv("JWT_KEYS_DIR")
+	if dir == "" {
+		if os.Getenv("ENV") == "production" {
+			return fmt.Errorf("JWT_KEYS_DIR environment variable not set")
+		}
+
+		key, err := GenerateEd25519Key("dev")
+		if err != nil {
+			return err
+		}
+		log.Println("⚠️  JWT_KEYS_DIR not set, signing tokens with an ephemeral key")
+		ks, err := NewKeySet([]*JWTKey{key}, key.ID)
+		if err != nil {
+			return err
+		}
+		ConfigureKeys(ks)
+		return nil
+	}
+
+	keys, err := LoadKeysFromDir(dir)
+	if err != nil {
+		return err
+	}
+	ks, err := NewKeySet(keys, os.Getenv("JWT_ACTIVE_KID"))
+	if err != nil {
+		return err
+	}
+	ConfigureKeys(ks)
+	return nil
+}
+
+// LoadKeysFromDir reads every <kid>.pem file in dir. Files may hold an RSA or
+// Ed25519 private key (PKCS#8, or PKCS#1 for RSA) or, for retired keys, a public key.
+func LoadKeysFromDir(dir string) ([]*JWTKey, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no .pem keys found in %s", dir)
+	}
+
+	keys := make([]*JWTKey, 0, len(paths))
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		key, err := ParseJWTKey(strings.TrimSuffix(filepath.Base(path), ".pem"), data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// ParseJWTKey parses a PEM encoded private or public key
+func ParseJWTKey(id string, data []byte) (*JWTKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found")
+	}
+
+	var parsed interface{}
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &JWTKey{ID: id}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.PrivateKey, key.PublicKey = jwt.SigningMethodRS256, k, &k.PublicKey
+	case *rsa.PublicKey:
+		key.Method, key.PublicKey = jwt.SigningMethodRS256, k
+	case ed25519.PrivateKey:
+		key.Method, key.PrivateKey, key.PublicKey = jwt.SigningMethodEdDSA, k, k.Public()
+	case ed25519.PublicKey:
+		key.Method, key.PublicKey = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T, use RSA or Ed25519", parsed)
+	}
+
+	if pub, ok := key.PublicKey.(*rsa.PublicKey); ok && pub.N.BitLen() < minRSAKeyBits {
+		return nil, fmt.Errorf("RSA keys must be at least %d bits", minRSAKeyBits)
+	}
+
+	return key, nil
+}
+
+// GenerateEd25519Key creates a new random Ed25519 signing key
+func GenerateEd25519Key(id string) (*JWTKey, error) {
+	pub, priv, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return &JWTKey{ID: id, Method: jwt.SigningMethodEdDSA, PrivateKey: priv, PublicKey: pub}, nil
+}
+
+// signToken signs claims with the active key and sets its kid header
+func signToken(claims jwt.Claims) (string, error) {
+	if keySet == nil {
+		return "", fmt.Errorf("signing keys not configured")
+	}
+
+	key := keySet.Active()
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.PrivateKey)
+}
+
+// verificationKey picks the key to verify a token with from its kid header
+func verificationKey(token *jwt.Token) (interface{}, error) {
+	if keySet == nil {
+		return nil, fmt.Errorf("signing keys not configured")
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := keySet.Lookup(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.Method.Alg() {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	return key.PublicKey, nil
+}
@@ -0,0 +1,57 @@
+// internal/auth/magic_link.go
+package auth
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+// Magic links are one-time tokens bound to the device that asked for them.
+// The device gets a random nonce when it requests the link, and only the hash
+// of that nonce is kept with the token. A link opened without the nonce, such
+// as one forwarded or intercepted from the email, is not accepted.
+
+// NewMagicLinkNonce returns a random nonce for the requesting device
+func NewMagicLinkNonce() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes), nil
+}
+
+// IssueMagicLinkToken creates a sign-in token for the user, bound to nonce.
+// Earlier unused links keep working, up to MaxMagicLinks in all.
+func IssueMagicLinkToken(userID uint, nonce string, db *gorm.DB) (string, error) {
+	return issueOneTimeToken(userID, PurposeMagicLink, hashOneTimeToken(nonce), MagicLinkDuration, MaxMagicLinks-1, db)
+}
+
+// ConsumeMagicLinkToken marks a sign-in token as used and returns it. A token
+// presented with the wrong nonce is left unused, so the link still works on
+// the device that requested it. The user's other links are discarded.
+func ConsumeMagicLinkToken(token, nonce string, db *gorm.DB) (*models.OneTimeToken, error) {
+	var consumed models.OneTimeToken
+	result := db.Model(&consumed).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND purpose = ? AND payload = ? AND used_at IS NULL AND expires_at > ?",
+			hashOneTimeToken(token), PurposeMagicLink, hashOneTimeToken(nonce), time.Now()).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInvalidOneTimeToken
+	}
+
+	if err := db.Where("user_id = ? AND purpose = ? AND used_at IS NULL", consumed.UserID, PurposeMagicLink).
+		Delete(&models.OneTimeToken{}).Error; err != nil {
+		return nil, err
+	}
+	return &consumed, nil
+}
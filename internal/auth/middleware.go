@@ -12,10 +12,12 @@ import (
 	"github.com/jedi116/kaizen-api/internal/models"
 )
 
-// JWTAuthMiddleware validates JWT tokens (PostgreSQL version)
+// JWTAuthMiddleware validates JWT tokens (PostgreSQL version).
+// Requests authenticated by the access_token cookie must also pass CheckCSRF.
 func JWTAuthMiddleware(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var tokenString string
+		fromCookie := false
 
 		// Try to get token from Authorization header first
 		authHeader := c.GetHeader("Authorization")
@@ -29,6 +31,7 @@ func JWTAuthMiddleware(db *gorm.DB) gin.HandlerFunc {
 		// If not in header, try cookie (for web clients)
 		if tokenString == "" {
 			tokenString, _ = c.Cookie("access_token")
+			fromCookie = tokenString != ""
 		}
 
 		if tokenString == "" {
@@ -45,15 +48,25 @@ func JWTAuthMiddleware(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
+		// Browsers attach cookies to cross-site requests too
+		if fromCookie && !CheckCSRF(c) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Missing or invalid CSRF token"})
+			c.Abort()
+			return
+		}
+
 		// Store user info in context
 		c.Set("user_id", claims.UserID)
 		c.Set("email", claims.Email)
+		c.Set("role", claims.Role)
+		c.Set("auth_method", "jwt")
+		c.Set("session_id", claims.FamilyID)
 
 		c.Next()
 	}
 }
 
-// APIKeyMiddleware validates API keys (unchanged)
+// APIKeyMiddleware validates API keys
 func APIKeyMiddleware(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		apiKey := c.GetHeader("X-API-Key")
@@ -63,8 +76,21 @@ func APIKeyMiddleware(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
+		prefix, ok := models.APIKeyPrefix(apiKey)
+		if !ok {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
+			c.Abort()
+			return
+		}
+
+		// Look the key up by prefix (or the prefix it had before a rotation still in
+		// its grace period), then compare the hash of the full key.
+		// Keys stop working while their owner's account is disabled.
 		var key models.APIKey
-		if err := db.Where("key = ? AND is_active = ?", apiKey, true).First(&key).Error; err != nil {
+		if err := db.Joins("JOIN users ON users.id = api_keys.user_id AND users.disabled_at IS NULL AND users.deleted_at IS NULL").
+			Where("api_keys.is_active = ? AND (api_keys.prefix = ? OR (api_keys.previous_prefix = ? AND api_keys.previous_expires_at > ?))",
+				true, prefix, prefix, time.Now()).
+			First(&key).Error; err != nil || !key.MatchesKey(apiKey) {
 			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid API key"})
 			c.Abort()
 			return
@@ -76,38 +102,34 @@ func APIKeyMiddleware(db *gorm.DB) gin.HandlerFunc {
 			return
 		}
 
-		// Update last used time
-		go func() {
-			now := time.Now()
-			db.Model(&key).Update("last_used_at", now)
-		}()
+		if !key.AllowsIP(c.ClientIP()) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "API key is not allowed from this IP address"})
+			c.Abort()
+			return
+		}
+
+		// Counted in memory and written in batches by RunAPIKeyUsageFlusher
+		apiKeyUsage.record(key.ID, time.Now())
 
 		c.Set("user_id", key.UserID)
 		c.Set("auth_method", "api_key")
+		c.Set("api_key_id", key.ID)
+		c.Set("api_key_scopes", key.ScopeList())
 
 		c.Next()
 	}
 }
 
-// OptionalAuth tries JWT first, then API key
+// OptionalAuth accepts either a JWT (header or cookie) or an API key.
+// An Authorization header always wins; X-API-Key is used when it is the only credential.
 func OptionalAuth(db *gorm.DB) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Try JWT first
-		authHeader := c.GetHeader("Authorization")
-		if authHeader != "" {
-			JWTAuthMiddleware(db)(c)
-			return
-		}
-
-		// Try API key
-		apiKey := c.GetHeader("X-API-Key")
-		if apiKey != "" {
+		if c.GetHeader("Authorization") == "" && c.GetHeader("X-API-Key") != "" {
 			APIKeyMiddleware(db)(c)
 			return
 		}
 
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "No authentication provided"})
-		c.Abort()
+		JWTAuthMiddleware(db)(c)
 	}
 }
 
@@ -118,3 +140,64 @@ func GetUserID(c *gin.Context) (uint, bool) {
 	}
 	return userID.(uint), true
 }
+
+// GetRole returns the role of a JWT-authenticated request, or "" if there is none
+func GetRole(c *gin.Context) string {
+	return c.GetString("role")
+}
+
+// GetSessionID returns the session of a JWT-authenticated request, or "" if there is none
+func GetSessionID(c *gin.Context) string {
+	return c.GetString("session_id")
+}
+
+// RequireVerifiedEmail blocks users who have not verified their email address.
+// Must run after an authentication middleware.
+func RequireVerifiedEmail(db *gorm.DB) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := GetUserID(c)
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			c.Abort()
+			return
+		}
+
+		var user models.User
+		if err := db.Select("id", "email_verified").First(&user, userID).Error; err != nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not found"})
+			c.Abort()
+			return
+		}
+
+		if !user.EmailVerified {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Email address not verified"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireRole only lets JWT-authenticated users with one of roles through.
+// API keys are always refused. Must run after JWTAuthMiddleware.
+func RequireRole(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("auth_method") != "jwt" {
+			c.JSON(http.StatusForbidden, gin.H{"error": "This endpoint requires a user session"})
+			c.Abort()
+			return
+		}
+
+		role := GetRole(c)
+		for _, allowed := range roles {
+			if role == allowed {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Insufficient permissions"})
+		c.Abort()
+	}
+}
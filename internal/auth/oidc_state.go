@@ -0,0 +1,58 @@
+// internal/auth/oidc_state.go
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const purposeOIDCState = "oidc_state"
+
+// OIDCStateDuration bounds how long a user may take at the provider's login page
+var OIDCStateDuration = 10 * time.Minute
+
+// OIDCStateClaims carry the per-login secrets of an OpenID Connect authorization
+// request between the redirect and the callback. They live in a signed HttpOnly
+// cookie, so the callback can only be completed by the browser that started it.
+type OIDCStateClaims struct {
+	Purpose      string `json:"purpose"`
+	Provider     string `json:"provider"`
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"code_verifier"`
+	LinkUserID   uint   `json:"link_user_id,omitempty"` // Set when a signed-in user is linking an identity
+	jwt.RegisteredClaims
+}
+
+// GenerateOIDCState signs the state of an authorization request
+func GenerateOIDCState(claims OIDCStateClaims) (string, error) {
+	claims.Purpose = purposeOIDCState
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    TokenIssuer,
+		Audience:  jwt.ClaimStrings{AudienceOIDCState},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(OIDCStateDuration)),
+		IssuedAt:  jwt.NewNumericDate(time.Now()),
+	}
+
+	return signToken(&claims)
+}
+
+// ValidateOIDCState parses a signed authorization request state
+func ValidateOIDCState(tokenString string) (*OIDCStateClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &OIDCStateClaims{}, verificationKey)
+	if err != nil {
+		return nil, err
+	}
+
+	claims, ok := token.Claims.(*OIDCStateClaims)
+	if !ok || !token.Valid || claims.Purpose != purposeOIDCState {
+		return nil, fmt.Errorf("invalid token")
+	}
+	if err := checkAudience(claims.Audience, AudienceOIDCState); err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,157 @@
+// internal/auth/one_time_token.go
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+// Purposes for one-time tokens
+const (
+	PurposePasswordReset = "password_reset"
+	PurposeEmailChange   = "email_change"
+	PurposeMagicLink     = "magic_link"
+	PurposeTwoFactor     = "2fa_challenge"
+)
+
+var (
+	PasswordResetDuration = 30 * time.Minute
+	EmailChangeDuration   = 24 * time.Hour
+	MagicLinkDuration     = 15 * time.Minute
+	TwoFactorDuration     = 5 * time.Minute
+)
+
+// MaxTwoFactorAttempts is how many codes may be tried against one two-factor challenge
+const MaxTwoFactorAttempts = 5
+
+// MaxMagicLinks is how many unused sign-in links a user may have. Each only
+// works on the device that asked for it, so several can be valid at once, and
+// requests by someone else (limited by MagicLinkPolicy) can't cancel the link a
+// user is waiting for.
+const MaxMagicLinks = 5
+
+var ErrInvalidOneTimeToken = errors.New("invalid, expired or already used token")
+
+// IssueOneTimeToken creates a single-use token for purpose and returns its plaintext.
+// Any earlier unused token for the same user and purpose is discarded.
+func IssueOneTimeToken(userID uint, purpose string, duration time.Duration, db *gorm.DB) (string, error) {
+	return issueOneTimeToken(userID, purpose, "", duration, 0, db)
+}
+
+// IssueEmailChangeToken creates a token that confirms newEmail as the user's
+// address. The new address is kept with the token until it is consumed.
+func IssueEmailChangeToken(userID uint, newEmail string, db *gorm.DB) (string, error) {
+	return issueOneTimeToken(userID, PurposeEmailChange, newEmail, EmailChangeDuration, 0, db)
+}
+
+// issueOneTimeToken discards earlier unused tokens for the same user and
+// purpose, except the newest keep of them
+func issueOneTimeToken(userID uint, purpose, payload string, duration time.Duration, keep int, db *gorm.DB) (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(secret)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		discard := tx.Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose)
+		if keep > 0 {
+			newest := tx.Model(&models.OneTimeToken{}).Select("id").
+				Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+				Order("id DESC").Limit(keep)
+			discard = discard.Where("id NOT IN (?)", newest)
+		}
+		if err := discard.Delete(&models.OneTimeToken{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Create(&models.OneTimeToken{
+			UserID:    userID,
+			Purpose:   purpose,
+			TokenHash: hashOneTimeToken(token),
+			Payload:   payload,
+			ExpiresAt: time.Now().Add(duration),
+		}).Error
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// ConsumeOneTimeToken marks a token as used and returns it.
+// The update is conditional, so concurrent requests cannot both consume the same token.
+func ConsumeOneTimeToken(token, purpose string, db *gorm.DB) (*models.OneTimeToken, error) {
+	var consumed models.OneTimeToken
+	result := db.Model(&consumed).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ?", hashOneTimeToken(token), purpose, time.Now()).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInvalidOneTimeToken
+	}
+
+	return &consumed, nil
+}
+
+// IssueTwoFactorChallenge creates the token a user with two-factor
+// authentication exchanges, together with a code, for a token pair
+func IssueTwoFactorChallenge(userID uint, db *gorm.DB) (string, error) {
+	return IssueOneTimeToken(userID, PurposeTwoFactor, TwoFactorDuration, db)
+}
+
+// AttemptTwoFactorChallenge counts one code attempt against a challenge and
+// returns it. Once MaxTwoFactorAttempts codes have been tried the challenge is
+// rejected, so a challenge token that leaks only allows a few guesses.
+func AttemptTwoFactorChallenge(token string, db *gorm.DB) (*models.OneTimeToken, error) {
+	var challenge models.OneTimeToken
+	result := db.Model(&challenge).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND purpose = ? AND used_at IS NULL AND expires_at > ? AND attempts < ?",
+			hashOneTimeToken(token), PurposeTwoFactor, time.Now(), MaxTwoFactorAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, ErrInvalidOneTimeToken
+	}
+
+	return &challenge, nil
+}
+
+// CompleteTwoFactorChallenge marks a challenge as used once its code was accepted
+func CompleteTwoFactorChallenge(challenge *models.OneTimeToken, db *gorm.DB) error {
+	result := db.Model(&models.OneTimeToken{}).
+		Where("id = ? AND used_at IS NULL", challenge.ID).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInvalidOneTimeToken
+	}
+	return nil
+}
+
+// CleanupOneTimeTokens deletes tokens that were used or have expired; neither can be consumed again
+func CleanupOneTimeTokens(db *gorm.DB) error {
+	return db.Where("used_at IS NOT NULL OR expires_at < ?", time.Now()).Delete(&models.OneTimeToken{}).Error
+}
+
+func hashOneTimeToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
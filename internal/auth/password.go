@@ -2,9 +2,143 @@
 package auth
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
+const (
+	MinPasswordLength = 8
+	MaxPasswordBytes  = 72 // bcrypt ignores everything after the first 72 bytes
+)
+
+// Password violation codes, stable for clients to match on
+const (
+	PasswordTooShort        = "too_short"
+	PasswordTooLong         = "too_long"
+	PasswordTooWeak         = "too_weak"
+	PasswordBreached        = "breached"
+	PasswordHasPersonalInfo = "personal_info"
+)
+
+var ErrPasswordUnchanged = errors.New("new password must be different from the current password")
+
+// PasswordPolicy describes what a new password must satisfy
+type PasswordPolicy struct {
+	MinLength int // Minimum length in characters
+	MinScore  int // Minimum strength score, 0 (too guessable) to 4 (very unguessable)
+}
+
+// UserPasswordPolicy applies whenever a user chooses a password
+var UserPasswordPolicy = PasswordPolicy{
+	MinLength: MinPasswordLength,
+	MinScore:  2,
+}
+
+// PasswordViolation is one reason a password was rejected
+type PasswordViolation struct {
+	Code    string
+	Message string
+}
+
+// PasswordPolicyError lists every rule a password breaks
+type PasswordPolicyError struct {
+	Violations []PasswordViolation
+}
+
+func (e *PasswordPolicyError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// LoadPasswordPolicyFromEnv overrides the password policy from PASSWORD_MIN_LENGTH
+// and PASSWORD_MIN_SCORE, and loads the breached password list named by
+// BREACHED_PASSWORDS_FILE
+func LoadPasswordPolicyFromEnv() error {
+	if v := os.Getenv("PASSWORD_MIN_LENGTH"); v != "" {
+		length, err := strconv.Atoi(v)
+		if err != nil || length < 1 || length > MaxPasswordBytes {
+			return fmt.Errorf("PASSWORD_MIN_LENGTH must be between 1 and %d", MaxPasswordBytes)
+		}
+		UserPasswordPolicy.MinLength = length
+	}
+	if v := os.Getenv("PASSWORD_MIN_SCORE"); v != "" {
+		score, err := strconv.Atoi(v)
+		if err != nil || score < 0 || score > 4 {
+			return errors.New("PASSWORD_MIN_SCORE must be between 0 and 4")
+		}
+		UserPasswordPolicy.MinScore = score
+	}
+	if path := os.Getenv("BREACHED_PASSWORDS_FILE"); path != "" {
+		list, err := LoadBreachedPasswords(path)
+		if err != nil {
+			return fmt.Errorf("BREACHED_PASSWORDS_FILE: %w", err)
+		}
+		BreachedPasswords = list
+	}
+	return nil
+}
+
+// ValidatePassword checks a new password against UserPasswordPolicy. userInputs
+// are the user's own details, such as name and email, which make a password
+// easier to guess. The error is a *PasswordPolicyError.
+func ValidatePassword(password string, userInputs ...string) error {
+	return UserPasswordPolicy.Validate(password, userInputs...)
+}
+
+// Validate checks a password against the policy, returning a *PasswordPolicyError
+// that lists every broken rule
+func (p PasswordPolicy) Validate(password string, userInputs ...string) error {
+	var violations []PasswordViolation
+
+	if len([]rune(password)) < p.MinLength {
+		violations = append(violations, PasswordViolation{
+			Code:    PasswordTooShort,
+			Message: fmt.Sprintf("Password must be at least %d characters", p.MinLength),
+		})
+	}
+	if len(password) > MaxPasswordBytes {
+		// Longer passwords would be silently truncated by bcrypt
+		violations = append(violations, PasswordViolation{
+			Code:    PasswordTooLong,
+			Message: fmt.Sprintf("Password must be at most %d bytes", MaxPasswordBytes),
+		})
+		return &PasswordPolicyError{Violations: violations}
+	}
+
+	if BreachedPasswords.Contains(password) {
+		violations = append(violations, PasswordViolation{
+			Code:    PasswordBreached,
+			Message: "Password has appeared in a data breach; choose a different one",
+		})
+	}
+
+	strength := PasswordStrength(password, userInputs...)
+	if strength.Score < p.MinScore {
+		code := PasswordTooWeak
+		if strength.pattern == patternUserInput {
+			code = PasswordHasPersonalInfo
+		}
+		message := "Password is too easy to guess"
+		if strength.Warning != "" {
+			message += ": " + strength.Warning
+		}
+		violations = append(violations, PasswordViolation{Code: code, Message: message})
+	}
+
+	if len(violations) > 0 {
+		return &PasswordPolicyError{Violations: violations}
+	}
+	return nil
+}
+
 // HashPassword hashes a plain text password
 func HashPassword(password string) (string, error) {
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
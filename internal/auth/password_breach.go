@@ -0,0 +1,82 @@
+// internal/auth/password_breach.go
+package auth
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+)
+
+// BreachedPasswords is checked by every password policy. It is empty unless a
+// list is loaded, e.g. through BREACHED_PASSWORDS_FILE.
+var BreachedPasswords = &BreachedPasswordList{}
+
+// BreachedPasswordList is a set of SHA-1 password hashes, such as an export of
+// the most common entries in Have I Been Pwned. Hashes are kept sorted in a flat
+// slice so millions of entries cost little more than 20 bytes each.
+type BreachedPasswordList struct {
+	hashes [][sha1.Size]byte
+}
+
+// LoadBreachedPasswords reads a file with one hex SHA-1 hash per line. A
+// ":count" suffix, as in Have I Been Pwned downloads, is ignored, as are blank
+// lines and lines starting with #.
+func LoadBreachedPasswords(path string) (*BreachedPasswordList, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var hashes [][sha1.Size]byte
+	scanner := bufio.NewScanner(file)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		line, _, _ = strings.Cut(line, ":")
+
+		var hash [sha1.Size]byte
+		if len(line) != hex.EncodedLen(sha1.Size) {
+			return nil, fmt.Errorf("line %d: not a SHA-1 hash", lineNumber)
+		}
+		if _, err := hex.Decode(hash[:], []byte(line)); err != nil {
+			return nil, fmt.Errorf("line %d: not a SHA-1 hash", lineNumber)
+		}
+		hashes = append(hashes, hash)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(hashes, func(a, b [sha1.Size]byte) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return &BreachedPasswordList{hashes: slices.Compact(hashes)}, nil
+}
+
+// Contains reports whether the password's hash is in the list
+func (l *BreachedPasswordList) Contains(password string) bool {
+	if l == nil || len(l.hashes) == 0 {
+		return false
+	}
+	hash := sha1.Sum([]byte(password))
+	_, found := slices.BinarySearchFunc(l.hashes, hash, func(a, b [sha1.Size]byte) int {
+		return bytes.Compare(a[:], b[:])
+	})
+	return found
+}
+
+// Len returns the number of hashes in the list
+func (l *BreachedPasswordList) Len() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.hashes)
+}
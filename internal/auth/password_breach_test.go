@@ -0,0 +1,90 @@
+// internal/auth/password_breach_test.go
+package auth
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func sha1Hex(password string) string {
+	sum := sha1.Sum([]byte(password))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
+
+func writeBreachedPasswords(t *testing.T, lines ...string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadBreachedPasswords(t *testing.T) {
+	path := writeBreachedPasswords(t,
+		"# Most common passwords",
+		sha1Hex("hunter2")+":17",
+		"",
+		strings.ToLower(sha1Hex("correcthorse")),
+		sha1Hex("hunter2")+":17",
+	)
+	list, err := LoadBreachedPasswords(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if list.Len() != 2 {
+		t.Errorf("loaded %d hashes, want 2", list.Len())
+	}
+
+	tests := []struct {
+		password string
+		want     bool
+	}{
+		{"hunter2", true},
+		{"correcthorse", true},
+		{"Hunter2", false},
+		{"staple-orbit-lantern-42", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := list.Contains(tt.password); got != tt.want {
+			t.Errorf("Contains(%q) = %v, want %v", tt.password, got, tt.want)
+		}
+	}
+}
+
+func TestLoadBreachedPasswordsRejectsInvalidLines(t *testing.T) {
+	for _, line := range []string{"hunter2", sha1Hex("hunter2")[:39], strings.Repeat("Z", 40)} {
+		if _, err := LoadBreachedPasswords(writeBreachedPasswords(t, sha1Hex("a"), line)); err == nil {
+			t.Errorf("loading %q succeeded, want an error", line)
+		}
+	}
+}
+
+func TestBreachedPasswordsRejectPassword(t *testing.T) {
+	list, err := LoadBreachedPasswords(writeBreachedPasswords(t, sha1Hex("staple-orbit-lantern-42")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := BreachedPasswords
+	BreachedPasswords = list
+	t.Cleanup(func() { BreachedPasswords = previous })
+
+	err = ValidatePassword("staple-orbit-lantern-42")
+	policyErr, ok := err.(*PasswordPolicyError)
+	if !ok || len(policyErr.Violations) != 1 || policyErr.Violations[0].Code != PasswordBreached {
+		t.Errorf("ValidatePassword of a breached password = %v, want only %q", err, PasswordBreached)
+	}
+	if err := ValidatePassword("lantern-orbit-staple-42"); err != nil {
+		t.Errorf("ValidatePassword of another password = %v", err)
+	}
+
+	var empty *BreachedPasswordList
+	if empty.Contains("staple-orbit-lantern-42") || empty.Len() != 0 {
+		t.Error("nil list contains a password")
+	}
+}
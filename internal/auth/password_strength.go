@@ -0,0 +1,538 @@
+// internal/auth/password_strength.go
+package auth
+
+import (
+	"math"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Strength estimates how hard a password is to guess, in the style of zxcvbn: the
+// password is split into the cheapest sequence of recognisable patterns (common
+// words, sequences, repeats, keyboard walks, years) and brute-forced gaps, and
+// the number of guesses an attacker needs is estimated from that split.
+type Strength struct {
+	Score   int     // 0 (too guessable) to 4 (very unguessable)
+	Guesses float64 // log10 of the estimated number of guesses
+	Warning string  // Why the password is weak, if a pattern made it so
+
+	pattern string // Pattern the warning is about
+}
+
+const (
+	patternBruteforce = "bruteforce"
+	patternDictionary = "dictionary"
+	patternReversed   = "reversed"
+	patternUserInput  = "user_input"
+	patternSequence   = "sequence"
+	patternRepeat     = "repeat"
+	patternSpatial    = "spatial"
+	patternYear       = "year"
+)
+
+var patternWarnings = map[string]string{
+	patternDictionary: "common words and passwords are easy to guess",
+	patternReversed:   "reversed words are easy to guess",
+	patternUserInput:  "avoid using your name or email address",
+	patternSequence:   "sequences like abc or 6543 are easy to guess",
+	patternRepeat:     "repeats like aaa or abcabc are easy to guess",
+	patternSpatial:    "keyboard patterns like qwerty are easy to guess",
+	patternYear:       "recent years are easy to guess",
+}
+
+// Guess counts (log10) for parts of a password that don't cover all of it, so a
+// short pattern can't make a password look weaker than guessing it blindly
+const (
+	minSingleCharGuesses = 1   // 10 guesses
+	minMultiCharGuesses  = 1.7 // 50 guesses
+	// Extra guesses (log10) per additional pattern, for not knowing how they combine
+	patternJoinGuesses = 4
+)
+
+// maxStrengthLength caps the runes that are scored, bounding the work for long
+// input. Anything longer already fails the MaxPasswordBytes limit.
+const maxStrengthLength = MaxPasswordBytes
+
+// PasswordStrength estimates the strength of password. userInputs, such as the
+// user's name and email, are treated as the most guessable words of all.
+func PasswordStrength(password string, userInputs ...string) Strength {
+	runes := []rune(password)
+	if len(runes) == 0 {
+		return Strength{}
+	}
+	if len(runes) > maxStrengthLength {
+		runes = runes[:maxStrengthLength]
+	}
+
+	estimator := guessEstimator{userWords: userDictionary(userInputs), repeatedBlocks: make(map[string]float64)}
+	guesses, weakest := estimator.estimateGuesses(runes)
+	strength := Strength{Score: scoreForGuesses(guesses), Guesses: guesses}
+	if weakest != nil && strength.Score < 3 {
+		strength.pattern = weakest.pattern
+		strength.Warning = patternWarnings[weakest.pattern]
+	}
+	return strength
+}
+
+func scoreForGuesses(guesses float64) int {
+	switch {
+	case guesses < 3:
+		return 0
+	case guesses < 6:
+		return 1
+	case guesses < 8:
+		return 2
+	case guesses < 10:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// passwordMatch is a pattern found in runes i through j
+type passwordMatch struct {
+	i, j    int
+	guesses float64 // log10
+	pattern string
+}
+
+// guessEstimator scores one password. Repeated blocks are scored recursively,
+// and the same block recurs at many offsets and counts (think "abab...ab"), so
+// each block's guesses are remembered for the rest of the password.
+type guessEstimator struct {
+	userWords      map[string]int
+	repeatedBlocks map[string]float64 // log10 guesses by repeated block
+}
+
+// estimateGuesses finds the split of runes into patterns needing the fewest
+// guesses, returning that count (log10) and the longest pattern in the split
+func (e guessEstimator) estimateGuesses(runes []rune) (float64, *passwordMatch) {
+	n := len(runes)
+	lower := make([]rune, n)
+	for i, r := range runes {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	byEnd := make([][]passwordMatch, n)
+	add := func(m passwordMatch) {
+		if m.j-m.i+1 < n {
+			floor := minMultiCharGuesses
+			if m.i == m.j {
+				floor = minSingleCharGuesses
+			}
+			m.guesses = math.Max(m.guesses, floor)
+		}
+		byEnd[m.j] = append(byEnd[m.j], m)
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			add(passwordMatch{i: i, j: j, guesses: float64(j - i + 1), pattern: patternBruteforce})
+		}
+	}
+	for _, m := range dictionaryMatches(runes, lower, e.userWords) {
+		add(m)
+	}
+	for _, m := range sequenceMatches(lower) {
+		add(m)
+	}
+	for _, m := range e.repeatMatches(lower) {
+		add(m)
+	}
+	for _, m := range spatialMatches(runes) {
+		add(m)
+	}
+	for _, m := range yearMatches(lower) {
+		add(m)
+	}
+
+	// best[k][l] is the cheapest way to cover the first k runes with l patterns
+	type step struct {
+		guesses float64
+		match   *passwordMatch
+	}
+	best := make([][]*step, n+1)
+	for k := range best {
+		best[k] = make([]*step, n+1)
+	}
+	best[0][0] = &step{}
+	for k := 1; k <= n; k++ {
+		for mi := range byEnd[k-1] {
+			m := &byEnd[k-1][mi]
+			for l := 1; l <= k; l++ {
+				prev := best[m.i][l-1]
+				if prev == nil {
+					continue
+				}
+				guesses := prev.guesses + m.guesses
+				if best[k][l] == nil || guesses < best[k][l].guesses {
+					best[k][l] = &step{guesses: guesses, match: m}
+				}
+			}
+		}
+	}
+
+	// Trying l patterns in any order costs l! times their product
+	total, count := math.Inf(1), 0
+	for l := 1; l <= n; l++ {
+		if best[n][l] == nil {
+			continue
+		}
+		lgamma, _ := math.Lgamma(float64(l + 1))
+		guesses := logAdd(lgamma/math.Ln10+best[n][l].guesses, patternJoinGuesses*float64(l-1))
+		if guesses < total {
+			total, count = guesses, l
+		}
+	}
+
+	var weakest *passwordMatch
+	for k, l := n, count; l > 0; l-- {
+		m := best[k][l].match
+		if m.pattern != patternBruteforce && (weakest == nil || m.j-m.i > weakest.j-weakest.i) {
+			weakest = m
+		}
+		k = m.i
+	}
+	return total, weakest
+}
+
+// logAdd returns log10(10^a + 10^b)
+func logAdd(a, b float64) float64 {
+	if a < b {
+		a, b = b, a
+	}
+	return a + math.Log10(1+math.Pow(10, b-a))
+}
+
+func userDictionary(userInputs []string) map[string]int {
+	words := make(map[string]int)
+	for _, input := range userInputs {
+		input = strings.ToLower(input)
+		if len([]rune(input)) >= 3 {
+			words[input] = 1
+		}
+		for _, word := range strings.FieldsFunc(input, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		}) {
+			if len([]rune(word)) >= 3 {
+				words[word] = 1
+			}
+		}
+	}
+	return words
+}
+
+// l33tSubstitutions maps characters commonly swapped for letters back to them
+var l33tSubstitutions = map[rune]rune{
+	'4': 'a', '@': 'a', '8': 'b', '(': 'c', '3': 'e', '6': 'g', '1': 'i', '!': 'i',
+	'|': 'i', '0': 'o', '$': 's', '5': 's', '7': 't', '+': 't', '2': 'z', '%': 'x',
+}
+
+const maxDictionaryWordLength = 24
+
+func dictionaryMatches(runes, lower []rune, userWords map[string]int) []passwordMatch {
+	var matches []passwordMatch
+	lookup := func(word string) (int, string) {
+		if rank, ok := userWords[word]; ok {
+			return rank, patternUserInput
+		}
+		if rank, ok := commonPasswordRanks[word]; ok {
+			return rank, patternDictionary
+		}
+		return 0, ""
+	}
+
+	for i := range lower {
+		for j := i + 2; j < len(lower) && j-i < maxDictionaryWordLength; j++ {
+			token := lower[i : j+1]
+			uppercase := uppercaseVariations(runes[i : j+1])
+
+			if rank, pattern := lookup(string(token)); rank > 0 {
+				matches = append(matches, passwordMatch{i: i, j: j, guesses: math.Log10(float64(rank)) + uppercase, pattern: pattern})
+			}
+
+			reversed := make([]rune, len(token))
+			for k, r := range token {
+				reversed[len(token)-1-k] = r
+			}
+			if rank, pattern := lookup(string(reversed)); rank > 0 {
+				if pattern == patternDictionary {
+					pattern = patternReversed
+				}
+				matches = append(matches, passwordMatch{i: i, j: j, guesses: math.Log10(float64(rank)) + uppercase + math.Log10(2), pattern: pattern})
+			}
+
+			unsubstituted := make([]rune, len(token))
+			substitutions := 0
+			for k, r := range token {
+				if letter, ok := l33tSubstitutions[r]; ok {
+					unsubstituted[k] = letter
+					substitutions++
+				} else {
+					unsubstituted[k] = r
+				}
+			}
+			if substitutions > 0 {
+				if rank, pattern := lookup(string(unsubstituted)); rank > 0 {
+					matches = append(matches, passwordMatch{i: i, j: j, guesses: math.Log10(float64(rank)) + uppercase + float64(substitutions)*math.Log10(2), pattern: pattern})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// uppercaseVariations returns log10 of the ways the word's capitalisation could
+// have been chosen, counting the usual first- or all-caps styles as cheap
+func uppercaseVariations(word []rune) float64 {
+	upper, lower := 0, 0
+	for _, r := range word {
+		if unicode.IsUpper(r) {
+			upper++
+		} else if unicode.IsLower(r) {
+			lower++
+		}
+	}
+	if upper == 0 {
+		return 0
+	}
+	if lower == 0 || (upper == 1 && (unicode.IsUpper(word[0]) || unicode.IsUpper(word[len(word)-1]))) {
+		return math.Log10(2)
+	}
+
+	variations := 0.0
+	for k := 1; k <= min(upper, lower); k++ {
+		variations += binomial(upper+lower, k)
+	}
+	return math.Log10(variations)
+}
+
+func binomial(n, k int) float64 {
+	result := 1.0
+	for i := 1; i <= k; i++ {
+		result = result * float64(n-k+i) / float64(i)
+	}
+	return result
+}
+
+// sequenceMatches finds runs like abc, 9876 or xyz
+func sequenceMatches(lower []rune) []passwordMatch {
+	var matches []passwordMatch
+	for i := 0; i+1 < len(lower); {
+		delta := lower[i+1] - lower[i]
+		if (delta != 1 && delta != -1) || charClass(lower[i]) != charClass(lower[i+1]) || charClass(lower[i]) == 0 {
+			i++
+			continue
+		}
+
+		j := i + 1
+		for j+1 < len(lower) && lower[j+1]-lower[j] == delta && charClass(lower[j+1]) == charClass(lower[i]) {
+			j++
+		}
+		if j-i+1 >= 3 {
+			var base float64
+			switch {
+			case strings.ContainsRune("az019", lower[i]):
+				base = 4 // Obvious starting points
+			case unicode.IsDigit(lower[i]):
+				base = 10
+			default:
+				base = 26
+			}
+			if delta < 0 {
+				base *= 2
+			}
+			matches = append(matches, passwordMatch{i: i, j: j, guesses: math.Log10(base * float64(j-i+1)), pattern: patternSequence})
+		}
+		i = j
+	}
+	return matches
+}
+
+// charClass is 1 for lowercase letters, 2 for digits and 0 for anything else
+func charClass(r rune) int {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return 1
+	case r >= '0' && r <= '9':
+		return 2
+	default:
+		return 0
+	}
+}
+
+// repeatMatches finds a character or block repeated back to back, like aaa or abcabc
+func (e guessEstimator) repeatMatches(lower []rune) []passwordMatch {
+	var matches []passwordMatch
+	for i := range lower {
+		for size := 1; i+2*size <= len(lower); size++ {
+			block := lower[i : i+size]
+			count := 1
+			for start := i + size; start+size <= len(lower) && string(lower[start:start+size]) == string(block); start += size {
+				count++
+			}
+			if count < 2 || (size == 1 && count < 3) {
+				continue
+			}
+
+			var blockGuesses float64
+			if size == 1 {
+				blockGuesses = math.Log10(charCardinality(block[0]))
+			} else if cached, ok := e.repeatedBlocks[string(block)]; ok {
+				blockGuesses = cached
+			} else {
+				blockGuesses, _ = e.estimateGuesses(block)
+				e.repeatedBlocks[string(block)] = blockGuesses
+			}
+			matches = append(matches, passwordMatch{
+				i:       i,
+				j:       i + size*count - 1,
+				guesses: blockGuesses + math.Log10(float64(count)),
+				pattern: patternRepeat,
+			})
+		}
+	}
+	return matches
+}
+
+func charCardinality(r rune) float64 {
+	switch charClass(r) {
+	case 1:
+		return 26
+	case 2:
+		return 10
+	default:
+		return 33
+	}
+}
+
+// US QWERTY layout, unshifted and shifted, with each row's horizontal offset in keys
+var (
+	keyboardRows        = []string{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./"}
+	keyboardShiftedRows = []string{"~!@#$%^&*()_+", "QWERTYUIOP{}|", "ASDFGHJKL:\"", "ZXCVBNM<>?"}
+	keyboardRowOffsets  = []float64{0, 1.5, 1.75, 2.25}
+)
+
+type keyPosition struct {
+	x       float64
+	row     int
+	shifted bool
+}
+
+var keyboardPositions = func() map[rune]keyPosition {
+	positions := make(map[rune]keyPosition)
+	for row, keys := range keyboardRows {
+		for col, key := range keys {
+			positions[key] = keyPosition{x: keyboardRowOffsets[row] + float64(col), row: row}
+		}
+	}
+	for row, keys := range keyboardShiftedRows {
+		for col, key := range keys {
+			positions[key] = keyPosition{x: keyboardRowOffsets[row] + float64(col), row: row, shifted: true}
+		}
+	}
+	return positions
+}()
+
+const (
+	keyboardStartingPositions = 47
+	keyboardAverageDegree     = 4.6
+)
+
+// spatialMatches finds walks across neighbouring keys, like qwerty or 1qaz
+func spatialMatches(runes []rune) []passwordMatch {
+	var matches []passwordMatch
+	for i := 0; i < len(runes); {
+		j, turns, shifted := i, 0, false
+		lastDirection := [2]int{}
+		for j+1 < len(runes) {
+			from, okFrom := keyboardPositions[runes[j]]
+			to, okTo := keyboardPositions[runes[j+1]]
+			dy := to.row - from.row
+			dx := to.x - from.x
+			adjacent := okFrom && okTo && ((dy == 0 && math.Abs(dx) == 1) || ((dy == 1 || dy == -1) && math.Abs(dx) < 1))
+			if !adjacent {
+				break
+			}
+
+			direction := [2]int{dy, int(math.Copysign(1, dx))}
+			if j > i && direction != lastDirection {
+				turns++
+			}
+			lastDirection = direction
+			shifted = shifted || from.shifted || to.shifted
+			j++
+		}
+
+		if j-i+1 >= 3 {
+			length := float64(j - i + 1)
+			guesses := math.Log10(keyboardStartingPositions*length) + float64(turns)*math.Log10(keyboardAverageDegree)
+			if shifted {
+				guesses += math.Log10(2)
+			}
+			matches = append(matches, passwordMatch{i: i, j: j, guesses: guesses, pattern: patternSpatial})
+		}
+		if j > i {
+			i = j
+		} else {
+			i++
+		}
+	}
+	return matches
+}
+
+const minYearSpace = 20
+
+// yearMatches finds four-digit years from 1900 to 2099
+func yearMatches(lower []rune) []passwordMatch {
+	var matches []passwordMatch
+	for i := 0; i+4 <= len(lower); i++ {
+		year := 0
+		for _, r := range lower[i : i+4] {
+			if charClass(r) != 2 {
+				year = -1
+				break
+			}
+			year = year*10 + int(r-'0')
+		}
+		if year < 1900 || year > 2099 {
+			continue
+		}
+
+		space := max(math.Abs(float64(year-time.Now().Year())), minYearSpace)
+		matches = append(matches, passwordMatch{i: i, j: i + 3, guesses: math.Log10(space), pattern: patternYear})
+	}
+	return matches
+}
+
+// commonPasswordRanks ranks some of the most used passwords and password words,
+// most common first. Anything longer belongs in the breached password list.
+var commonPasswordRanks = func() map[string]int {
+	ranks := make(map[string]int, len(commonPasswords))
+	for i, word := range commonPasswords {
+		if _, exists := ranks[word]; !exists {
+			ranks[word] = i + 1
+		}
+	}
+	return ranks
+}()
+
+var commonPasswords = []string{
+	"password", "qwerty", "iloveyou", "admin", "welcome", "monkey", "login", "abc", "dragon", "secret123",
+	"master", "hello", "letmein", "football", "baseball", "sunshine", "princess", "shadow", "superman", "trustno",
+	"michael", "jennifer", "freedom", "whatever", "starwars", "computer", "secret", "summer", "winter", "spring",
+	"autumn", "love", "pass", "money", "charlie", "jordan", "hunter", "ranger", "buster", "soccer",
+	"hockey", "killer", "george", "andrew", "thomas", "robert", "daniel", "jessica", "ashley", "pepper",
+	"ginger", "cheese", "orange", "banana", "apple", "chocolate", "cookie", "flower", "tigger", "mustang",
+	"access", "batman", "matrix", "internet", "samsung", "google", "changeme", "default", "test", "guest",
+	"user", "root", "secure", "private", "mother", "father", "family", "friend", "forever", "angel",
+	"baby", "lovely", "sweet", "happy", "lucky", "magic", "pokemon", "naruto", "liverpool", "arsenal",
+	"chelsea", "yankees", "cowboys", "eagles", "tiger", "lion", "bear", "wolf", "eagle", "falcon",
+	"harley", "corvette", "ferrari", "porsche", "mercedes", "nissan", "toyota", "honda", "silver", "golden",
+	"diamond", "purple", "yellow", "black", "white", "blue", "green", "red", "pink", "soleil",
+	"qazwsx", "zaq", "asdf", "zxcv", "hallo", "passwort", "contraseña", "motdepasse", "azerty", "qwertz",
+	"kaizen", "finance", "budget", "journal", "savings", "account", "bank", "wallet", "crypto", "bitcoin",
+	"january", "february", "march", "april", "may", "june", "july", "august", "september", "october",
+	"november", "december", "monday", "friday", "sunday", "weekend", "holiday", "christmas", "birthday", "summer",
+}
@@ -0,0 +1,110 @@
+// internal/auth/password_strength_test.go
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPasswordStrength(t *testing.T) {
+	userInputs := []string{"John Smith", "john.smith@example.com"}
+
+	weak := []struct {
+		password string
+		pattern  string
+	}{
+		{"password", patternDictionary},
+		{"Password1", patternDictionary},
+		{"p@ssw0rd", patternDictionary},
+		{"kaizen2026", patternDictionary},
+		{"drowssap", patternReversed},
+		{"johnsmith", patternUserInput},
+		{"john.smith@example.com", patternUserInput},
+		{"abcdefgh", patternSequence},
+		{"98765432", patternSequence},
+		{"aaaaaaaa", patternRepeat},
+		{"abcabcabc", patternRepeat},
+		{"qwertyuiop", patternSpatial},
+		{"1qaz2wsx", patternSpatial},
+		{"zxcvbnm,./", patternSpatial},
+	}
+	for _, tt := range weak {
+		strength := PasswordStrength(tt.password, userInputs...)
+		if strength.Score >= UserPasswordPolicy.MinScore {
+			t.Errorf("%q scored %d, want below %d", tt.password, strength.Score, UserPasswordPolicy.MinScore)
+		}
+		if strength.pattern != tt.pattern || strength.Warning == "" {
+			t.Errorf("%q warned about %q (%q), want %q", tt.password, strength.pattern, strength.Warning, tt.pattern)
+		}
+	}
+
+	strong := []string{
+		"staple-orbit-lantern-42",
+		"correct horse battery staple",
+		"x7#Qv!2mL9@pRt",
+		"Tr0ub4dor&3",
+	}
+	for _, password := range strong {
+		strength := PasswordStrength(password, userInputs...)
+		if strength.Score < 3 || strength.Warning != "" {
+			t.Errorf("%q scored %d (%q), want at least 3 without a warning", password, strength.Score, strength.Warning)
+		}
+	}
+
+	if strength := PasswordStrength(""); strength.Score != 0 {
+		t.Errorf("empty password scored %d, want 0", strength.Score)
+	}
+}
+
+// Repeated blocks are scored recursively; long repeats used to take seconds
+func TestPasswordStrengthOfLongRepeats(t *testing.T) {
+	for _, password := range []string{
+		strings.Repeat("a", MaxPasswordBytes),
+		strings.Repeat("ab", MaxPasswordBytes/2),
+		strings.Repeat("abc", MaxPasswordBytes/3),
+		strings.Repeat("aab", MaxPasswordBytes/3),
+		strings.Repeat("a", 10000),
+	} {
+		start := time.Now()
+		strength := PasswordStrength(password)
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("scoring %d runes of %q took %v", len(password), password[:3], elapsed)
+		}
+		if strength.Score >= UserPasswordPolicy.MinScore {
+			t.Errorf("%d runes of %q scored %d, want below %d", len(password), password[:3], strength.Score, UserPasswordPolicy.MinScore)
+		}
+	}
+}
+
+func TestValidatePassword(t *testing.T) {
+	tests := []struct {
+		password string
+		codes    []string
+	}{
+		{"staple-orbit-lantern-42", nil},
+		{"Qv!2mL", []string{PasswordTooShort}},
+		{"password", []string{PasswordTooWeak}},
+		{"smith", []string{PasswordTooShort, PasswordHasPersonalInfo}},
+		{strings.Repeat("staple-orbit-", 6), []string{PasswordTooLong}},
+	}
+	for _, tt := range tests {
+		err := ValidatePassword(tt.password, "John Smith", "john.smith@example.com")
+		var codes []string
+		if err != nil {
+			for _, v := range err.(*PasswordPolicyError).Violations {
+				codes = append(codes, v.Code)
+			}
+		}
+		if strings.Join(codes, ",") != strings.Join(tt.codes, ",") {
+			t.Errorf("ValidatePassword(%q) = %v, want %v", tt.password, codes, tt.codes)
+		}
+	}
+}
+
+func BenchmarkPasswordStrength(b *testing.B) {
+	password := strings.Repeat("ab", MaxPasswordBytes/2)
+	for b.Loop() {
+		PasswordStrength(password)
+	}
+}
@@ -0,0 +1,201 @@
+// internal/auth/revocation.go
+package auth
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// TokenCacheTTL bounds how long a validated token is trusted without asking the
+// database again, and so how long a revocation made by another instance can go
+// unnoticed when no RevocationStore is configured. Zero disables the cache.
+var TokenCacheTTL = 30 * time.Second
+
+// TokenCacheMaxEntries caps the memory used by the token cache
+var TokenCacheMaxEntries = 100000
+
+// Revocation names tokens that were revoked: a single token, a token family, or
+// every token of a user. Exactly one field is set.
+type Revocation struct {
+	TokenID  string `json:"jti,omitempty"`
+	FamilyID string `json:"fid,omitempty"`
+	UserID   uint   `json:"uid,omitempty"`
+}
+
+// RevocationStore shares revocations between API instances, so tokens cached by
+// other instances are dropped right away instead of when their cache entry expires.
+type RevocationStore interface {
+	// Publish announces a revocation to every subscribed instance
+	Publish(ctx context.Context, revocation Revocation) error
+
+	// Subscribe calls handle for every published revocation until ctx is done.
+	// onGap is called whenever revocations may have been missed, e.g. after a reconnect.
+	Subscribe(ctx context.Context, handle func(Revocation), onGap func()) error
+}
+
+var (
+	tokens          = newTokenCache()
+	revocationStore RevocationStore
+)
+
+// LoadTokenCacheFromEnv reads TOKEN_CACHE_TTL (e.g. "30s", "0" to disable)
+func LoadTokenCacheFromEnv() error {
+	if value := os.Getenv("TOKEN_CACHE_TTL"); value != "" {
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl < 0 {
+			return fmt.Errorf("TOKEN_CACHE_TTL must be a non-negative duration, got %q", value)
+		}
+		TokenCacheTTL = ttl
+	}
+	return nil
+}
+
+// UseRevocationStore publishes this instance's revocations to store and applies
+// revocations from other instances until ctx is done
+func UseRevocationStore(ctx context.Context, store RevocationStore) {
+	revocationStore = store
+
+	go func() {
+		for ctx.Err() == nil {
+			err := store.Subscribe(ctx, tokens.invalidate, tokens.clear)
+			if ctx.Err() != nil {
+				return
+			}
+
+			// Revocations may be missed while disconnected, so forget everything cached
+			log.Printf("token revocation subscription failed, retrying: %v", err)
+			tokens.clear()
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}()
+}
+
+// revoked drops revoked tokens from this instance's cache and tells other
+// instances. Call it after the revocation is committed to the database.
+func revoked(revocation Revocation) {
+	tokens.invalidate(revocation)
+
+	if revocationStore != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		if err := revocationStore.Publish(ctx, revocation); err != nil {
+			// Other instances still stop trusting the token within TokenCacheTTL
+			log.Printf("failed to publish token revocation: %v", err)
+		}
+	}
+}
+
+// tokenCache remembers tokens recently found valid in the database
+type tokenCache struct {
+	mu      sync.RWMutex
+	entries map[string]cachedToken
+
+	// generation changes on every invalidation. A lookup that started before an
+	// invalidation must not cache its (possibly stale) result.
+	generation uint64
+}
+
+type cachedToken struct {
+	userID     uint
+	familyID   string
+	validUntil time.Time
+}
+
+func newTokenCache() *tokenCache {
+	return &tokenCache{entries: make(map[string]cachedToken)}
+}
+
+// valid reports whether the token was recently found valid
+func (tc *tokenCache) valid(tokenID string) bool {
+	if TokenCacheTTL <= 0 {
+		return false
+	}
+
+	tc.mu.RLock()
+	entry, ok := tc.entries[tokenID]
+	tc.mu.RUnlock()
+
+	return ok && time.Now().Before(entry.validUntil)
+}
+
+// currentGeneration must be read before looking a token up in the database
+func (tc *tokenCache) currentGeneration() uint64 {
+	tc.mu.RLock()
+	defer tc.mu.RUnlock()
+	return tc.generation
+}
+
+// add caches a token found valid, unless something was invalidated since generation was read
+func (tc *tokenCache) add(tokenID string, userID uint, familyID string, expiresAt time.Time, generation uint64) {
+	if TokenCacheTTL <= 0 {
+		return
+	}
+
+	validUntil := time.Now().Add(TokenCacheTTL)
+	if expiresAt.Before(validUntil) {
+		validUntil = expiresAt
+	}
+
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	if tc.generation != generation {
+		return
+	}
+
+	if len(tc.entries) >= TokenCacheMaxEntries {
+		tc.evict()
+	}
+	tc.entries[tokenID] = cachedToken{userID: userID, familyID: familyID, validUntil: validUntil}
+}
+
+// evict removes expired entries, or an arbitrary one if none have expired. Called with mu held.
+func (tc *tokenCache) evict() {
+	now := time.Now()
+	for id, entry := range tc.entries {
+		if !now.Before(entry.validUntil) {
+			delete(tc.entries, id)
+		}
+	}
+	for id := range tc.entries {
+		if len(tc.entries) < TokenCacheMaxEntries {
+			break
+		}
+		delete(tc.entries, id)
+	}
+}
+
+// invalidate drops every cached token matched by revocation
+func (tc *tokenCache) invalidate(revocation Revocation) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.generation++
+
+	if revocation.TokenID != "" {
+		delete(tc.entries, revocation.TokenID)
+		return
+	}
+	for id, entry := range tc.entries {
+		if (revocation.FamilyID != "" && entry.familyID == revocation.FamilyID) ||
+			(revocation.UserID != 0 && entry.userID == revocation.UserID) {
+			delete(tc.entries, id)
+		}
+	}
+}
+
+// clear drops every cached token
+func (tc *tokenCache) clear() {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+
+	tc.generation++
+	tc.entries = make(map[string]cachedToken)
+}
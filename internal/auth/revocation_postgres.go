@@ -0,0 +1,82 @@
+// internal/auth/revocation_postgres.go
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+// PostgresRevocationStore shares revocations through Postgres LISTEN/NOTIFY, so
+// it works for any number of instances using the same database
+type PostgresRevocationStore struct {
+	DB      *gorm.DB
+	Channel string
+}
+
+// NewPostgresRevocationStore creates a store using the "token_revocations" channel
+func NewPostgresRevocationStore(db *gorm.DB) *PostgresRevocationStore {
+	return &PostgresRevocationStore{DB: db, Channel: "token_revocations"}
+}
+
+// Publish sends a revocation to every listening instance, including this one
+func (s *PostgresRevocationStore) Publish(ctx context.Context, revocation Revocation) error {
+	payload, err := json.Marshal(revocation)
+	if err != nil {
+		return err
+	}
+	return s.DB.WithContext(ctx).Exec("SELECT pg_notify(?, ?)", s.Channel, string(payload)).Error
+}
+
+// Subscribe listens on a dedicated connection until ctx is done or the connection fails
+func (s *PostgresRevocationStore) Subscribe(ctx context.Context, handle func(Revocation), onGap func()) error {
+	sqlDB, err := s.DB.DB()
+	if err != nil {
+		return err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("revocation store needs the pgx driver, got %T", driverConn)
+		}
+		pgConn := stdlibConn.Conn()
+
+		channel := pgx.Identifier{s.Channel}.Sanitize()
+		if _, err := pgConn.Exec(ctx, "LISTEN "+channel); err != nil {
+			return err
+		}
+		// The connection goes back to the pool afterwards, so stop listening on it
+		defer func() {
+			unlistenCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			pgConn.Exec(unlistenCtx, "UNLISTEN "+channel)
+		}()
+		// Anything revoked before LISTEN took effect was missed
+		onGap()
+
+		for {
+			notification, err := pgConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+
+			var revocation Revocation
+			if err := json.Unmarshal([]byte(notification.Payload), &revocation); err != nil {
+				onGap()
+				continue
+			}
+			handle(revocation)
+		}
+	})
+}
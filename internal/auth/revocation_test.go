@@ -0,0 +1,253 @@
+// internal/auth/revocation_test.go
+package auth
+
+import (
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+// benchmarkDB opens TEST_DATABASE_URL (a migrated database) and returns a
+// transaction that is rolled back when the benchmark ends
+func benchmarkDB(b *testing.B) *gorm.DB {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		b.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		b.Fatal(err)
+	}
+	tx := db.Begin()
+	b.Cleanup(func() { tx.Rollback() })
+	return tx
+}
+
+func benchmarkToken(b *testing.B, db *gorm.DB) string {
+	key, err := GenerateEd25519Key("bench")
+	if err != nil {
+		b.Fatal(err)
+	}
+	ks, err := NewKeySet([]*JWTKey{key}, key.ID)
+	if err != nil {
+		b.Fatal(err)
+	}
+	ConfigureKeys(ks)
+
+	user := models.User{Name: "Bench", Email: fmt.Sprintf("bench-%d@example.com", time.Now().UnixNano()), Password: "x"}
+	if err := db.Create(&user).Error; err != nil {
+		b.Fatal(err)
+	}
+	tokens, err := GenerateTokenPair(&user, SessionMeta{}, db)
+	if err != nil {
+		b.Fatal(err)
+	}
+	return tokens.AccessToken
+}
+
+// useTokenCache turns the token cache on, empty, for the rest of the test
+func useTokenCache(t *testing.T) {
+	previous := TokenCacheTTL
+	TokenCacheTTL = time.Minute
+	tokens.clear()
+	t.Cleanup(func() {
+		TokenCacheTTL = previous
+		tokens.clear()
+	})
+}
+
+// cachedAccessToken signs user in and validates the access token, so it is cached
+func cachedAccessToken(t *testing.T, user *models.User, db *gorm.DB) (*TokenPair, *Claims) {
+	t.Helper()
+	pair, err := GenerateTokenPair(user, SessionMeta{}, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := ValidateToken(pair.AccessToken, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !tokens.valid(claims.ID) {
+		t.Fatal("validated token was not cached")
+	}
+	return pair, claims
+}
+
+func TestRevocationsDropCachedTokens(t *testing.T) {
+	configureTestKeys(t)
+	useTokenCache(t)
+	db := testDB(t)
+
+	for _, tc := range []struct {
+		name   string
+		revoke func(user *models.User, pair *TokenPair, claims *Claims) error
+	}{
+		{"token", func(_ *models.User, pair *TokenPair, _ *Claims) error {
+			return RevokeToken(pair.AccessToken, db)
+		}},
+		{"family", func(_ *models.User, _ *TokenPair, claims *Claims) error {
+			return RevokeTokenFamily(claims.FamilyID, db)
+		}},
+		{"user", func(user *models.User, _ *TokenPair, _ *Claims) error {
+			return RevokeAllUserTokens(user.ID, db)
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			user := testUser(t, db)
+			pair, claims := cachedAccessToken(t, user, db)
+			other, otherClaims := cachedAccessToken(t, testUser(t, db), db)
+
+			if err := tc.revoke(user, pair, claims); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ValidateToken(pair.AccessToken, db); err == nil {
+				t.Error("revoked token still valid")
+			}
+
+			// Other users' tokens stay cached
+			if !tokens.valid(otherClaims.ID) {
+				t.Error("revocation dropped an unrelated token from the cache")
+			}
+			if _, err := ValidateToken(other.AccessToken, db); err != nil {
+				t.Errorf("unrelated token rejected: %v", err)
+			}
+		})
+	}
+}
+
+func TestRevocationDuringLookupIsNotCached(t *testing.T) {
+	configureTestKeys(t)
+	useTokenCache(t)
+	db := testDB(t)
+	user := testUser(t, db)
+
+	pair, err := GenerateTokenPair(user, SessionMeta{}, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := parseToken(pair.AccessToken, AudienceAPI)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Revoke the token right after ValidateToken has read it from the database,
+	// before it caches the result
+	var once sync.Once
+	err = db.Callback().Query().After("gorm:query").Register("test:revoke_during_lookup", func(tx *gorm.DB) {
+		if tx.Statement.Table == "tokens" {
+			once.Do(func() {
+				if err := RevokeToken(pair.AccessToken, db); err != nil {
+					t.Error(err)
+				}
+			})
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The lookup in flight may still succeed, but must not be trusted afterwards
+	ValidateToken(pair.AccessToken, db)
+	if tokens.valid(claims.ID) {
+		t.Fatal("token revoked during its lookup was cached")
+	}
+	if _, err := ValidateToken(pair.AccessToken, db); err == nil {
+		t.Fatal("token revoked during its lookup still valid")
+	}
+}
+
+func TestTokenCacheInvalidate(t *testing.T) {
+	defer func(ttl time.Duration) { TokenCacheTTL = ttl }(TokenCacheTTL)
+	TokenCacheTTL = time.Minute
+
+	cache := newTokenCache()
+	expires := time.Now().Add(time.Hour)
+	add := func(id string, userID uint, familyID string) {
+		cache.add(id, userID, familyID, expires, cache.currentGeneration())
+	}
+	add("a1", 1, "fa")
+	add("a2", 1, "fa")
+	add("b1", 1, "fb")
+	add("c1", 2, "fc")
+
+	cache.invalidate(Revocation{TokenID: "a1"})
+	cache.invalidate(Revocation{FamilyID: "fa"})
+	for id, want := range map[string]bool{"a1": false, "a2": false, "b1": true, "c1": true} {
+		if got := cache.valid(id); got != want {
+			t.Errorf("after revoking a1 and family fa, valid(%s) = %v, want %v", id, got, want)
+		}
+	}
+
+	cache.invalidate(Revocation{UserID: 1})
+	if cache.valid("b1") || !cache.valid("c1") {
+		t.Error("revoking user 1 didn't drop exactly its tokens")
+	}
+
+	// A lookup that began before an invalidation isn't cached
+	generation := cache.currentGeneration()
+	cache.invalidate(Revocation{TokenID: "d1"})
+	cache.add("d1", 3, "fd", expires, generation)
+	if cache.valid("d1") {
+		t.Error("token looked up before its revocation was cached")
+	}
+}
+
+// BenchmarkValidateToken compares validating every request against the database
+// with the token cache. Run with TEST_DATABASE_URL set, e.g.
+//
+//	TEST_DATABASE_URL=postgres://... go test -run '^$' -bench ValidateToken ./internal/auth
+func BenchmarkValidateToken(b *testing.B) {
+	for _, bc := range []struct {
+		name string
+		ttl  time.Duration
+	}{
+		{"database", 0},
+		{"cached", 30 * time.Second},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			db := benchmarkDB(b)
+			token := benchmarkToken(b, db)
+
+			defer func(ttl time.Duration) { TokenCacheTTL = ttl }(TokenCacheTTL)
+			TokenCacheTTL = bc.ttl
+			tokens.clear()
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if _, err := ValidateToken(token, db); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkTokenCache measures the cached path alone, with many concurrent readers
+func BenchmarkTokenCache(b *testing.B) {
+	cache := newTokenCache()
+	ids := make([]string, 10000)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("access_%d", i)
+		cache.add(ids[i], uint(i%100), fmt.Sprintf("family_%d", i), time.Now().Add(time.Hour), 0)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		i := 0
+		for pb.Next() {
+			if !cache.valid(ids[i%len(ids)]) {
+				b.Fatal("expected cached token")
+			}
+			i++
+		}
+	})
+}
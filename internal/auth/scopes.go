@@ -0,0 +1,45 @@
+// internal/auth/scopes.go
+package auth
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Scopes that can be granted to an API key
+const (
+	ScopeJournalsRead    = "journals:read"
+	ScopeJournalsWrite   = "journals:write"
+	ScopeCategoriesRead  = "categories:read"
+	ScopeCategoriesWrite = "categories:write"
+	ScopeAccountsRead    = "accounts:read"
+	ScopeAccountsWrite   = "accounts:write"
+	ScopeBudgetsRead     = "budgets:read"
+	ScopeBudgetsWrite    = "budgets:write"
+)
+
+// RequireScope rejects API key requests whose key was not granted scope.
+// Requests authenticated with a JWT act as the user and are always allowed.
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if method, _ := c.Get("auth_method"); method != "api_key" {
+			c.Next()
+			return
+		}
+
+		scopes, _ := c.Get("api_key_scopes")
+		granted, _ := scopes.([]string)
+		if !slices.Contains(granted, scope) {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":         "API key is missing required scope: " + scope,
+				"missing_scope": scope,
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
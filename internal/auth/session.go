@@ -0,0 +1,117 @@
+// internal/auth/session.go
+package auth
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+const maxUserAgentLength = 255 // Characters, the size of the user_agent columns
+
+// ReauthWindow is how long after signing in a user without a password may make
+// changes that would otherwise ask for it
+var ReauthWindow = 10 * time.Minute
+
+// SessionMeta describes the device a session belongs to
+type SessionMeta struct {
+	UserAgent string
+	IPAddress string
+}
+
+// SessionMetaFromRequest reads the device details of the current request
+func SessionMetaFromRequest(c *gin.Context) SessionMeta {
+	return SessionMeta{
+		UserAgent: truncateUserAgent(c.Request.UserAgent()),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+// truncateUserAgent cuts a user agent to maxUserAgentLength characters without
+// splitting one. Bytes that aren't valid UTF-8 are replaced, since Postgres
+// rejects them.
+func truncateUserAgent(userAgent string) string {
+	userAgent = strings.ToValidUTF8(userAgent, "\uFFFD")
+	if utf8.RuneCountInString(userAgent) <= maxUserAgentLength {
+		return userAgent
+	}
+	return string([]rune(userAgent)[:maxUserAgentLength])
+}
+
+// ListSessions returns a user's active sessions, most recently used first
+func ListSessions(userID uint, db *gorm.DB) ([]models.Session, error) {
+	var sessions []models.Session
+	err := db.Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+// SessionStartedWithin reports whether the user's session sessionID began less
+// than d ago. Refreshing tokens keeps a session's start time, so only a fresh
+// sign-in counts.
+func SessionStartedWithin(userID uint, sessionID string, d time.Duration, db *gorm.DB) (bool, error) {
+	if sessionID == "" {
+		return false, nil
+	}
+	var count int64
+	err := db.Model(&models.Session{}).
+		Where("id = ? AND user_id = ? AND created_at > ?", sessionID, userID, time.Now().Add(-d)).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// RevokeSession signs one of a user's devices out by revoking its access and
+// refresh tokens. Returns gorm.ErrRecordNotFound if the session isn't the user's.
+func RevokeSession(userID uint, sessionID string, db *gorm.DB) error {
+	var session models.Session
+	if err := db.Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil {
+		return err
+	}
+	return RevokeTokenFamily(session.ID, db)
+}
+
+// RevokeOtherSessions signs out every device except the session identified by keepSessionID
+func RevokeOtherSessions(userID uint, keepSessionID string, db *gorm.DB) error {
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ? AND family_id <> ?", userID, keepSessionID).Delete(&models.Token{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("user_id = ? AND id <> ?", userID, keepSessionID).Delete(&models.Session{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	// The kept session's tokens are dropped from caches too; they are simply looked up again
+	revoked(Revocation{UserID: userID})
+	return nil
+}
+
+func startSession(userID uint, sessionID string, meta SessionMeta, db *gorm.DB) error {
+	now := time.Now()
+	return db.Create(&models.Session{
+		ID:         sessionID,
+		UserID:     userID,
+		UserAgent:  meta.UserAgent,
+		IPAddress:  meta.IPAddress,
+		LastSeenAt: now,
+		ExpiresAt:  now.Add(RefreshTokenDuration),
+	}).Error
+}
+
+// touchSession records a token refresh on the session
+func touchSession(sessionID string, meta SessionMeta, db *gorm.DB) error {
+	now := time.Now()
+	return db.Model(&models.Session{}).Where("id = ?", sessionID).Updates(map[string]interface{}{
+		"user_agent":   meta.UserAgent,
+		"ip_address":   meta.IPAddress,
+		"last_seen_at": now,
+		"expires_at":   now.Add(RefreshTokenDuration),
+	}).Error
+}
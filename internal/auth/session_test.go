@@ -0,0 +1,34 @@
+// internal/auth/session_test.go
+package auth
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateUserAgent(t *testing.T) {
+	for _, tc := range []struct {
+		name      string
+		userAgent string
+		want      string
+	}{
+		{"short", "Mozilla/5.0", "Mozilla/5.0"},
+		{"ascii at limit", strings.Repeat("a", 255), strings.Repeat("a", 255)},
+		{"ascii over limit", strings.Repeat("a", 300), strings.Repeat("a", 255)},
+		// 254 ASCII bytes followed by a 3-byte character straddle byte 255
+		{"multi-byte at boundary", strings.Repeat("a", 254) + "€€", strings.Repeat("a", 254) + "€"},
+		{"multi-byte only", strings.Repeat("ü", 300), strings.Repeat("ü", 255)},
+		{"invalid utf-8", "agent\xff\xfe", "agent�"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got := truncateUserAgent(tc.userAgent)
+			if got != tc.want {
+				t.Errorf("truncateUserAgent() = %q, want %q", got, tc.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("truncateUserAgent() returned invalid UTF-8 %q", got)
+			}
+		})
+	}
+}
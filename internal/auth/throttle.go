@@ -0,0 +1,321 @@
+// internal/auth/throttle.go
+package auth
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+// ThrottlePolicy controls how failed attempts for one key slow down further attempts
+type ThrottlePolicy struct {
+	BackoffAfter    int           // Failures allowed before backoff starts (0 disables backoff)
+	BackoffBase     time.Duration // Delay after the first failure past BackoffAfter, doubled for each one after
+	BackoffMax      time.Duration
+	LockoutAfter    int // Failures that lock the key
+	LockoutDuration time.Duration
+	Window          time.Duration // Failures older than this are forgotten
+}
+
+var (
+	// AccountLoginPolicy applies to failed logins for one email address
+	AccountLoginPolicy = ThrottlePolicy{
+		BackoffAfter:    3,
+		BackoffBase:     time.Second,
+		BackoffMax:      time.Minute,
+		LockoutAfter:    10,
+		LockoutDuration: 15 * time.Minute,
+		Window:          time.Hour,
+	}
+
+	// IPLoginPolicy applies to failed logins from one client IP. It has no
+	// backoff and a higher threshold, since many users may share an address.
+	IPLoginPolicy = ThrottlePolicy{
+		LockoutAfter:    100,
+		LockoutDuration: 15 * time.Minute,
+		Window:          time.Hour,
+	}
+
+	// MagicLinkPolicy limits how often sign-in links are emailed to one address.
+	// Every request counts, whether or not the address has an account.
+	MagicLinkPolicy = ThrottlePolicy{
+		BackoffAfter:    1,
+		BackoffBase:     time.Minute,
+		BackoffMax:      15 * time.Minute,
+		LockoutAfter:    5,
+		LockoutDuration: time.Hour,
+		Window:          time.Hour,
+	}
+
+	// PasswordResetPolicy limits how often reset links are emailed to one
+	// address, and PasswordResetIPPolicy how many one client may ask for.
+	// Every request counts, whether or not the address has an account.
+	PasswordResetPolicy = ThrottlePolicy{
+		BackoffAfter:    1,
+		BackoffBase:     time.Minute,
+		BackoffMax:      15 * time.Minute,
+		LockoutAfter:    5,
+		LockoutDuration: time.Hour,
+		Window:          time.Hour,
+	}
+	PasswordResetIPPolicy = ThrottlePolicy{
+		LockoutAfter:    20,
+		LockoutDuration: time.Hour,
+		Window:          time.Hour,
+	}
+)
+
+// LoadThrottlePoliciesFromEnv overrides the lockout settings from
+// LOGIN_LOCKOUT_THRESHOLD, LOGIN_LOCKOUT_DURATION and LOGIN_IP_LOCKOUT_THRESHOLD
+func LoadThrottlePoliciesFromEnv() error {
+	if v := os.Getenv("LOGIN_LOCKOUT_THRESHOLD"); v != "" {
+		threshold, err := strconv.Atoi(v)
+		if err != nil || threshold < 1 {
+			return errors.New("LOGIN_LOCKOUT_THRESHOLD must be a positive integer")
+		}
+		AccountLoginPolicy.LockoutAfter = threshold
+	}
+	if v := os.Getenv("LOGIN_LOCKOUT_DURATION"); v != "" {
+		duration, err := time.ParseDuration(v)
+		if err != nil || duration <= 0 {
+			return errors.New("LOGIN_LOCKOUT_DURATION must be a positive duration, e.g. 15m")
+		}
+		AccountLoginPolicy.LockoutDuration = duration
+		IPLoginPolicy.LockoutDuration = duration
+	}
+	if v := os.Getenv("LOGIN_IP_LOCKOUT_THRESHOLD"); v != "" {
+		threshold, err := strconv.Atoi(v)
+		if err != nil || threshold < 1 {
+			return errors.New("LOGIN_IP_LOCKOUT_THRESHOLD must be a positive integer")
+		}
+		IPLoginPolicy.LockoutAfter = threshold
+	}
+	return nil
+}
+
+// ReserveLoginAttempt counts a login attempt against both the account and the
+// client IP before the credentials are checked, so parallel guesses can't all
+// get in under the limit. It returns how long to wait if either must back off
+// or is locked, in which case nothing is counted. A successful login gives its
+// attempt back with UnlockAccount and ReleaseLoginAttempt.
+func ReserveLoginAttempt(email, ip string, db *gorm.DB) (time.Duration, error) {
+	return reserveAttempts(db,
+		throttleKey{accountThrottleKey(email), AccountLoginPolicy},
+		throttleKey{ipThrottleKey(ip), IPLoginPolicy},
+	)
+}
+
+// ReservePasswordReset counts a password reset request against email and the
+// client IP, returning how long to wait if either has asked too often
+func ReservePasswordReset(email, ip string, db *gorm.DB) (time.Duration, error) {
+	return reserveAttempts(db,
+		throttleKey{passwordResetThrottleKey(email), PasswordResetPolicy},
+		throttleKey{passwordResetIPThrottleKey(ip), PasswordResetIPPolicy},
+	)
+}
+
+// ReleaseLoginAttempt gives back the client IP's share of a reserved attempt
+// whose credentials were right, so many users behind one address aren't
+// locked out by signing in. The account's share is cleared by UnlockAccount.
+func ReleaseLoginAttempt(ip string, db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var throttle models.LoginThrottle
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", ipThrottleKey(ip)).First(&throttle).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if throttle.Failures > 0 {
+			throttle.Failures--
+		}
+		// A lockout this attempt set no longer applies
+		if throttle.Failures < IPLoginPolicy.LockoutAfter {
+			throttle.LockedUntil = nil
+		}
+		return tx.Save(&throttle).Error
+	})
+}
+
+// UnlockAccount clears failed logins and any lockout for an account.
+// Called after a successful login or password reset.
+func UnlockAccount(email string, db *gorm.DB) error {
+	return db.Where("key = ?", accountThrottleKey(email)).Delete(&models.LoginThrottle{}).Error
+}
+
+// CleanupLoginThrottles deletes throttles whose failures have all left the
+// window of every policy and that are no longer locked, so they limit nothing
+func CleanupLoginThrottles(db *gorm.DB) error {
+	window := AccountLoginPolicy.Window
+	for _, policy := range []ThrottlePolicy{IPLoginPolicy, MagicLinkPolicy, PasswordResetPolicy, PasswordResetIPPolicy} {
+		if policy.Window > window {
+			window = policy.Window
+		}
+	}
+
+	now := time.Now()
+	return db.Where("last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?)", now.Add(-window), now).
+		Delete(&models.LoginThrottle{}).Error
+}
+
+type throttleKey struct {
+	key    string
+	policy ThrottlePolicy
+}
+
+// errThrottled rolls back a reservation that one of its keys refused
+var errThrottled = errors.New("throttled")
+
+// reserveAttempts counts an attempt against every key in one transaction. If
+// any must wait, it returns the longest wait and counts nothing.
+func reserveAttempts(db *gorm.DB, keys ...throttleKey) (time.Duration, error) {
+	var wait time.Duration
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, key := range keys {
+			w, err := reserveAttempt(key.key, key.policy, tx)
+			if err != nil {
+				return err
+			}
+			if w > wait {
+				wait = w
+			}
+		}
+		if wait > 0 {
+			return errThrottled
+		}
+		return nil
+	})
+	if errors.Is(err, errThrottled) {
+		return wait, nil
+	}
+	return 0, err
+}
+
+// reserveAttempt locks the throttle for key and, unless it must wait, counts
+// an attempt against it. Concurrent reservations of one key queue on the lock,
+// so each sees the attempts counted before it.
+func reserveAttempt(key string, policy ThrottlePolicy, tx *gorm.DB) (time.Duration, error) {
+	throttle, err := lockThrottle(key, tx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	if wait := policy.retryAfter(throttle, now); wait > 0 {
+		return wait, nil
+	}
+	policy.count(throttle, now)
+	return 0, tx.Save(throttle).Error
+}
+
+// recordAttempt counts an attempt against key whether or not it must wait
+func recordAttempt(key string, policy ThrottlePolicy, db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		throttle, err := lockThrottle(key, tx)
+		if err != nil {
+			return err
+		}
+		policy.count(throttle, time.Now())
+		return tx.Save(throttle).Error
+	})
+}
+
+// lockThrottle loads the throttle for key for update, creating it if needed
+func lockThrottle(key string, tx *gorm.DB) (*models.LoginThrottle, error) {
+	created := models.LoginThrottle{Key: key, LastFailureAt: time.Now()}
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&created).Error; err != nil {
+		return nil, err
+	}
+
+	var throttle models.LoginThrottle
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("key = ?", key).First(&throttle).Error; err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+// count adds an attempt to throttle, forgetting attempts outside the window
+// first, and locks it once the attempts reach the threshold
+func (p ThrottlePolicy) count(throttle *models.LoginThrottle, now time.Time) {
+	if throttle.LastFailureAt.Before(now.Add(-p.Window)) {
+		throttle.Failures = 0
+	}
+	throttle.Failures++
+	throttle.LastFailureAt = now
+	if throttle.Failures >= p.LockoutAfter {
+		lockedUntil := now.Add(p.LockoutDuration)
+		throttle.LockedUntil = &lockedUntil
+	}
+}
+
+// retryAfter returns how long the key must wait, from a lockout or from backoff
+func (p ThrottlePolicy) retryAfter(throttle *models.LoginThrottle, now time.Time) time.Duration {
+	if throttle.LockedUntil != nil && throttle.LockedUntil.After(now) {
+		return throttle.LockedUntil.Sub(now)
+	}
+
+	if p.BackoffAfter == 0 || throttle.Failures < p.BackoffAfter || throttle.LastFailureAt.Before(now.Add(-p.Window)) {
+		return 0
+	}
+
+	delay := p.BackoffBase
+	for i := p.BackoffAfter; i < throttle.Failures && delay < p.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > p.BackoffMax {
+		delay = p.BackoffMax
+	}
+
+	if next := throttle.LastFailureAt.Add(delay); next.After(now) {
+		return next.Sub(now)
+	}
+	return 0
+}
+
+// MagicLinkRetryAfter returns how long before another sign-in link may be sent to email,
+// or 0 if one may be sent now
+func MagicLinkRetryAfter(email string, db *gorm.DB) (time.Duration, error) {
+	var throttle models.LoginThrottle
+	err := db.Where("key = ?", magicLinkThrottleKey(email)).First(&throttle).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	return MagicLinkPolicy.retryAfter(&throttle, time.Now()), nil
+}
+
+// RecordMagicLinkRequest counts a sign-in link request against email
+func RecordMagicLinkRequest(email string, db *gorm.DB) error {
+	return recordAttempt(magicLinkThrottleKey(email), MagicLinkPolicy, db)
+}
+
+func accountThrottleKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+func magicLinkThrottleKey(email string) string {
+	return "magic_link:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func passwordResetThrottleKey(email string) string {
+	return "password_reset:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func passwordResetIPThrottleKey(ip string) string {
+	return "password_reset_ip:" + ip
+}
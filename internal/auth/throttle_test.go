@@ -0,0 +1,187 @@
+// internal/auth/throttle_test.go
+package auth
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+// withPolicy replaces a throttle policy for the rest of the test
+func withPolicy(t *testing.T, policy *ThrottlePolicy, replacement ThrottlePolicy) {
+	previous := *policy
+	*policy = replacement
+	t.Cleanup(func() { *policy = previous })
+}
+
+// throttleKeys returns an email and IP no other test uses, whose throttles are
+// deleted when the test ends
+func throttleKeys(t *testing.T, db *gorm.DB) (string, string) {
+	n := time.Now().UnixNano()
+	email, ip := fmt.Sprintf("throttle-%d@example.com", n), fmt.Sprintf("test-%d", n)
+	t.Cleanup(func() {
+		db.Where("key IN ?", []string{accountThrottleKey(email), ipThrottleKey(ip)}).Delete(&models.LoginThrottle{})
+	})
+	return email, ip
+}
+
+func reserve(t *testing.T, email, ip string, db *gorm.DB) time.Duration {
+	t.Helper()
+	wait, err := ReserveLoginAttempt(email, ip, db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return wait
+}
+
+func TestLoginLockout(t *testing.T) {
+	db := testDB(t)
+	withPolicy(t, &AccountLoginPolicy, ThrottlePolicy{LockoutAfter: 3, LockoutDuration: time.Hour, Window: time.Hour})
+	email, ip := throttleKeys(t, db)
+
+	for i := 0; i < 3; i++ {
+		if wait := reserve(t, email, ip, db); wait != 0 {
+			t.Fatalf("attempt %d must wait %v, want none", i+1, wait)
+		}
+	}
+	if wait := reserve(t, email, ip, db); wait <= 59*time.Minute {
+		t.Fatalf("attempt after the lockout must wait %v, want about an hour", wait)
+	}
+
+	// Refused attempts aren't counted, so the IP isn't charged for them
+	var throttle models.LoginThrottle
+	if err := db.First(&throttle, "key = ?", ipThrottleKey(ip)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if throttle.Failures != 3 {
+		t.Errorf("IP counted %d attempts, want 3", throttle.Failures)
+	}
+
+	if err := UnlockAccount(email, db); err != nil {
+		t.Fatal(err)
+	}
+	if wait := reserve(t, email, ip, db); wait != 0 {
+		t.Errorf("attempt after UnlockAccount must wait %v, want none", wait)
+	}
+}
+
+func TestLoginSuccessGivesAttemptsBack(t *testing.T) {
+	db := testDB(t)
+	withPolicy(t, &AccountLoginPolicy, ThrottlePolicy{LockoutAfter: 3, LockoutDuration: time.Hour, Window: time.Hour})
+	withPolicy(t, &IPLoginPolicy, ThrottlePolicy{LockoutAfter: 3, LockoutDuration: time.Hour, Window: time.Hour})
+	email, ip := throttleKeys(t, db)
+
+	// Two failures, then a success that clears the account and gives the IP its attempt back
+	reserve(t, email, ip, db)
+	reserve(t, email, ip, db)
+	reserve(t, email, ip, db)
+	if err := ReleaseLoginAttempt(ip, db); err != nil {
+		t.Fatal(err)
+	}
+	if err := UnlockAccount(email, db); err != nil {
+		t.Fatal(err)
+	}
+
+	var count int64
+	db.Model(&models.LoginThrottle{}).Where("key = ?", accountThrottleKey(email)).Count(&count)
+	if count != 0 {
+		t.Error("account throttle left after a successful login")
+	}
+	var throttle models.LoginThrottle
+	if err := db.First(&throttle, "key = ?", ipThrottleKey(ip)).Error; err != nil {
+		t.Fatal(err)
+	}
+	if throttle.Failures != 2 || throttle.LockedUntil != nil {
+		t.Errorf("IP throttle has %d attempts, locked until %v; want 2 and unlocked", throttle.Failures, throttle.LockedUntil)
+	}
+
+	// Users behind one address can keep signing in
+	for i := 0; i < 5; i++ {
+		if wait := reserve(t, email, ip, db); wait != 0 {
+			t.Fatalf("successful login %d must wait %v, want none", i+1, wait)
+		}
+		if err := ReleaseLoginAttempt(ip, db); err != nil {
+			t.Fatal(err)
+		}
+		if err := UnlockAccount(email, db); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestParallelLoginAttemptsRespectLockout(t *testing.T) {
+	db := testDB(t)
+	withPolicy(t, &AccountLoginPolicy, ThrottlePolicy{LockoutAfter: 5, LockoutDuration: time.Hour, Window: time.Hour})
+	email, ip := throttleKeys(t, db)
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		allowed int
+	)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			wait, err := ReserveLoginAttempt(email, ip, db)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if wait == 0 {
+				mu.Lock()
+				allowed++
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if allowed != 5 {
+		t.Errorf("%d parallel attempts got through, want 5", allowed)
+	}
+}
+
+func TestThrottleBackoff(t *testing.T) {
+	policy := ThrottlePolicy{
+		BackoffAfter:    2,
+		BackoffBase:     time.Second,
+		BackoffMax:      5 * time.Second,
+		LockoutAfter:    10,
+		LockoutDuration: time.Hour,
+		Window:          time.Hour,
+	}
+	now := time.Now()
+
+	for _, tc := range []struct {
+		failures int
+		since    time.Duration
+		want     time.Duration
+	}{
+		{1, 0, 0},
+		{2, 0, time.Second},
+		{3, 0, 2 * time.Second},
+		{4, 0, 4 * time.Second},
+		{6, 0, 5 * time.Second},
+		{3, time.Second, time.Second},
+		{3, 2 * time.Hour, 0},
+	} {
+		throttle := models.LoginThrottle{Failures: tc.failures, LastFailureAt: now.Add(-tc.since)}
+		if got := policy.retryAfter(&throttle, now); got != tc.want {
+			t.Errorf("retryAfter(%d failures, %v ago) = %v, want %v", tc.failures, tc.since, got, tc.want)
+		}
+	}
+
+	var throttle models.LoginThrottle
+	for i := 0; i < 10; i++ {
+		policy.count(&throttle, now)
+	}
+	if throttle.LockedUntil == nil || !throttle.LockedUntil.Equal(now.Add(time.Hour)) {
+		t.Errorf("after 10 attempts locked until %v, want %v", throttle.LockedUntil, now.Add(time.Hour))
+	}
+}
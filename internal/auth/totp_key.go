@@ -0,0 +1,96 @@
+// internal/auth/totp_key.go
+package auth
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+)
+
+// sealedTOTPPrefix marks an encrypted TOTP secret. Base32 never contains a
+// colon, so secrets stored before encryption was added are told apart by it.
+const sealedTOTPPrefix = "enc:v1:"
+
+// totpCipher encrypts TOTP secrets at rest; nil stores them unencrypted
+var totpCipher cipher.AEAD
+
+// LoadTOTPKeyFromEnv configures the key TOTP secrets are encrypted with from
+// TOTP_ENCRYPTION_KEY, 32 base64 encoded bytes. Outside production the key
+// may be left unset, in which case secrets are stored unencrypted.
+func LoadTOTPKeyFromEnv() error {
+	v := os.Getenv("TOTP_ENCRYPTION_KEY")
+	if v == "" {
+		if os.Getenv("ENV") == "production" {
+			return errors.New("TOTP_ENCRYPTION_KEY environment variable not set")
+		}
+		log.Println("⚠️  TOTP_ENCRYPTION_KEY not set, storing two-factor secrets unencrypted")
+		return nil
+	}
+
+	key, err := base64.StdEncoding.DecodeString(v)
+	if err != nil || len(key) != 32 {
+		return errors.New("TOTP_ENCRYPTION_KEY must be 32 bytes, base64 encoded (e.g. openssl rand -base64 32)")
+	}
+	return ConfigureTOTPKey(key)
+}
+
+// ConfigureTOTPKey sets the AES-256 key TOTP secrets are encrypted with
+func ConfigureTOTPKey(key []byte) error {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return err
+	}
+	totpCipher = aead
+	return nil
+}
+
+// SealTOTPSecret encrypts a TOTP secret for storage
+func SealTOTPSecret(secret string) (string, error) {
+	if totpCipher == nil {
+		return secret, nil
+	}
+
+	nonce := make([]byte, totpCipher.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := totpCipher.Seal(nonce, nonce, []byte(secret), nil)
+	return sealedTOTPPrefix + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// OpenTOTPSecret decrypts a stored TOTP secret. Secrets stored unencrypted
+// are returned as they are.
+func OpenTOTPSecret(stored string) (string, error) {
+	if !IsSealedTOTPSecret(stored) {
+		return stored, nil
+	}
+	if totpCipher == nil {
+		return "", errors.New("two-factor secret is encrypted but TOTP_ENCRYPTION_KEY is not set")
+	}
+
+	sealed, err := base64.RawStdEncoding.DecodeString(strings.TrimPrefix(stored, sealedTOTPPrefix))
+	if err != nil || len(sealed) < totpCipher.NonceSize() {
+		return "", fmt.Errorf("malformed two-factor secret")
+	}
+	nonce, ciphertext := sealed[:totpCipher.NonceSize()], sealed[totpCipher.NonceSize():]
+	secret, err := totpCipher.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt two-factor secret: %w", err)
+	}
+	return string(secret), nil
+}
+
+// IsSealedTOTPSecret reports whether a stored TOTP secret is encrypted
+func IsSealedTOTPSecret(stored string) bool {
+	return strings.HasPrefix(stored, sealedTOTPPrefix)
+}
@@ -0,0 +1,48 @@
+// internal/auth/totp_key_test.go
+package auth
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestTOTPSecretSealing(t *testing.T) {
+	defer func() { totpCipher = nil }()
+	if err := ConfigureTOTPKey(bytes.Repeat([]byte{7}, 32)); err != nil {
+		t.Fatal(err)
+	}
+
+	secret, err := GenerateTOTPSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := SealTOTPSecret(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !IsSealedTOTPSecret(sealed) || strings.Contains(sealed, secret) {
+		t.Fatalf("secret stored in the clear: %q", sealed)
+	}
+	if len(sealed) > 255 {
+		t.Fatalf("sealed secret is %d characters, the column holds 255", len(sealed))
+	}
+
+	opened, err := OpenTOTPSecret(sealed)
+	if err != nil || opened != secret {
+		t.Fatalf("OpenTOTPSecret = %q, %v; want %q", opened, err, secret)
+	}
+
+	// Secrets stored before encryption was configured still work
+	if opened, err := OpenTOTPSecret(secret); err != nil || opened != secret {
+		t.Fatalf("OpenTOTPSecret(plaintext) = %q, %v", opened, err)
+	}
+
+	// A secret sealed with another key is rejected rather than misread
+	if err := ConfigureTOTPKey(bytes.Repeat([]byte{8}, 32)); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := OpenTOTPSecret(sealed); err == nil {
+		t.Fatal("secret sealed with another key was opened")
+	}
+}
@@ -0,0 +1,161 @@
+// internal/exchange/converter.go
+package exchange
+
+import (
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+// MaxRateAge is how far back a rate is looked for when none was published on
+// a transaction's date, e.g. over weekends and bank holidays
+var MaxRateAge = 7 * 24 * time.Hour
+
+type pair struct {
+	base, quote string
+}
+
+type cacheKey struct {
+	from string
+	date time.Time
+}
+
+// Converter converts amounts into one currency using the rate on each
+// transaction's date. Rates for the period are loaded once, so a report can
+// convert many entries without further queries.
+//
+// A rate is found directly (USD→EUR), inverted (EUR→USD stored) or through a
+// currency both are quoted against (GBP→USD through EUR, as in ECB files).
+type Converter struct {
+	to         string
+	rates      map[pair][]models.ExchangeRate // Ascending by date
+	currencies []string                       // Every currency in rates, for cross rates
+	cache      map[cacheKey]money.Rate
+}
+
+// NewConverter loads the rates needed to convert amounts in the from
+// currencies to the to currency for transactions between start and end
+func NewConverter(db *gorm.DB, to string, from []string, start, end time.Time) (*Converter, error) {
+	needed := []string{to}
+	for _, currency := range from {
+		if currency != to {
+			needed = append(needed, currency)
+		}
+	}
+	if len(needed) == 1 {
+		return newConverter(to, nil), nil
+	}
+
+	var rates []models.ExchangeRate
+	err := db.Where("date >= ? AND date <= ? AND (base IN ? OR quote IN ?)", start.Add(-MaxRateAge), end, needed, needed).
+		Order("date").
+		Find(&rates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return newConverter(to, rates), nil
+}
+
+// newConverter indexes rates, which must be in ascending date order
+func newConverter(to string, rates []models.ExchangeRate) *Converter {
+	c := &Converter{
+		to:    to,
+		rates: map[pair][]models.ExchangeRate{},
+		cache: map[cacheKey]money.Rate{},
+	}
+
+	seen := map[string]bool{}
+	for _, rate := range rates {
+		key := pair{rate.Base, rate.Quote}
+		c.rates[key] = append(c.rates[key], rate)
+		for _, currency := range []string{rate.Base, rate.Quote} {
+			if !seen[currency] {
+				seen[currency] = true
+				c.currencies = append(c.currencies, currency)
+			}
+		}
+	}
+	sort.Strings(c.currencies)
+
+	return c
+}
+
+// Currency returns the currency amounts are converted to
+func (c *Converter) Currency() string {
+	return c.to
+}
+
+// Rate returns the rate from the from currency on date, and false if there is none
+func (c *Converter) Rate(from string, date time.Time) (money.Rate, bool) {
+	if from == c.to {
+		return money.One, true
+	}
+
+	key := cacheKey{from, date}
+	if rate, ok := c.cache[key]; ok {
+		return rate, rate.IsValid()
+	}
+
+	rate, ok := c.leg(from, c.to, date)
+	if !ok {
+		for _, via := range c.currencies {
+			if via == from || via == c.to {
+				continue
+			}
+			first, ok1 := c.leg(from, via, date)
+			second, ok2 := c.leg(via, c.to, date)
+			if ok1 && ok2 {
+				rate, ok = first.Mul(second), true
+				break
+			}
+		}
+	}
+
+	c.cache[key] = rate
+	return rate, ok
+}
+
+// Convert converts amount from the from currency on date, and returns false
+// if there is no rate for it or the converted amount is out of range
+func (c *Converter) Convert(amount money.Money, from string, date time.Time) (money.Money, bool) {
+	rate, ok := c.Rate(from, date)
+	if !ok {
+		return 0, false
+	}
+	converted, err := rate.Convert(amount)
+	if err != nil {
+		return 0, false
+	}
+	return converted, true
+}
+
+// leg finds a stored rate between two currencies in either direction
+func (c *Converter) leg(from, to string, date time.Time) (money.Rate, bool) {
+	if rate, ok := c.latest(pair{from, to}, date); ok {
+		return rate, true
+	}
+	if rate, ok := c.latest(pair{to, from}, date); ok {
+		return rate.Inverse(), true
+	}
+	return money.Rate{}, false
+}
+
+// latest returns the pair's most recent rate on or before date, if it is no
+// older than MaxRateAge
+func (c *Converter) latest(key pair, date time.Time) (money.Rate, bool) {
+	rates := c.rates[key]
+	i := sort.Search(len(rates), func(i int) bool { return rates[i].Date.After(date) })
+	if i == 0 {
+		return money.Rate{}, false
+	}
+	rate := rates[i-1]
+	if date.Sub(rate.Date) > MaxRateAge {
+		return money.Rate{}, false
+	}
+	return rate.Rate, true
+}
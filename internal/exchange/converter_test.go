@@ -0,0 +1,81 @@
+// internal/exchange/converter_test.go
+package exchange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+func day(d int) time.Time {
+	return time.Date(2025, time.January, d, 0, 0, 0, 0, time.UTC)
+}
+
+func testRate(t *testing.T, date time.Time, base, quote, rate string) models.ExchangeRate {
+	t.Helper()
+	parsed, err := money.ParseRate(rate)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return models.ExchangeRate{Base: base, Quote: quote, Date: date, Rate: parsed}
+}
+
+// testRates are quoted against the euro, like ECB reference rates
+func testRates(t *testing.T) []models.ExchangeRate {
+	return []models.ExchangeRate{
+		testRate(t, day(15), "EUR", "USD", "1.0305"),
+		testRate(t, day(15), "EUR", "GBP", "0.844"),
+		testRate(t, day(15), "EUR", "JPY", "161.5"),
+		testRate(t, day(17), "EUR", "USD", "1.03"),
+	}
+}
+
+func TestConverterConvert(t *testing.T) {
+	tests := []struct {
+		name     string
+		to, from string
+		amount   money.Money
+		date     time.Time
+		want     money.Money
+		ok       bool
+	}{
+		{"same currency", "USD", "USD", 12345, day(1), 12345, true},
+		{"direct", "USD", "EUR", 10000, day(15), 10305, true},
+		{"inverted", "EUR", "USD", 10305, day(15), 10000, true},
+		{"cross rate", "USD", "GBP", 10000, day(15), 12210, true},      // 100 / 0.844 × 1.0305 = 122.097...
+		{"cross rate back", "GBP", "USD", 12210, day(15), 10000, true}, // 122.10 / 1.0305 × 0.844 = 100.002...
+		{"negative", "USD", "EUR", -10000, day(15), -10305, true},
+		{"latest rate on the day", "USD", "EUR", 10000, day(17), 10300, true},
+		{"looks back over a weekend", "USD", "EUR", 10000, day(19), 10300, true},
+		{"looks back at most MaxRateAge", "USD", "EUR", 10000, day(24), 10300, true},
+		{"rate too old", "USD", "EUR", 10000, day(25), 0, false},
+		{"before the first rate", "USD", "EUR", 10000, day(14), 0, false},
+		{"cross rate too old on one leg", "USD", "GBP", 10000, day(23), 0, false},
+		{"unknown currency", "USD", "CHF", 10000, day(15), 0, false},
+		{"out of range", "JPY", "EUR", money.Max, day(15), 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converter := newConverter(tt.to, testRates(t))
+			got, ok := converter.Convert(tt.amount, tt.from, tt.date)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Convert(%v %s on %s) = %v, %v; want %v, %v",
+					tt.amount, tt.from, tt.date.Format("2006-01-02"), got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestConverterCachesMissingRates(t *testing.T) {
+	converter := newConverter("USD", testRates(t))
+	for i := 0; i < 2; i++ {
+		if _, ok := converter.Rate("CHF", day(15)); ok {
+			t.Fatal("found a rate for a currency without one")
+		}
+	}
+	if rate, ok := converter.Rate("EUR", day(15)); !ok || rate.String() != "1.0305" {
+		t.Errorf("Rate(EUR) = %v, %v; want 1.0305", rate, ok)
+	}
+}
@@ -0,0 +1,208 @@
+// internal/exchange/import.go
+package exchange
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+// ECBBase is the base currency of the European Central Bank's reference rates
+const ECBBase = "EUR"
+
+const importBatchSize = 500
+
+// Parse reads rates from a CSV or ECB-style XML file, detected from the first
+// character. source is stored with each rate, usually the file name.
+func Parse(r io.Reader, source string) ([]models.ExchangeRate, error) {
+	br := bufio.NewReader(r)
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil, errors.New("file is empty")
+			}
+			return nil, err
+		}
+		switch b[0] {
+		case ' ', '\t', '\r', '\n', 0xEF, 0xBB, 0xBF: // Whitespace and a UTF-8 byte order mark
+			br.ReadByte()
+			continue
+		case '<':
+			return ParseECBXML(br, source)
+		default:
+			return ParseCSV(br, source)
+		}
+	}
+}
+
+// ParseCSV reads rates from a CSV file with a header row naming the columns
+// date, base, quote and rate, in any order, e.g.
+//
+//	date,base,quote,rate
+//	2025-01-15,EUR,USD,1.0305
+func ParseCSV(r io.Reader, source string) ([]models.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("reading header: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range []string{"date", "base", "quote", "rate"} {
+		if _, ok := columns[name]; !ok {
+			return nil, fmt.Errorf("header has no %q column", name)
+		}
+	}
+
+	var rates []models.ExchangeRate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		field := func(name string) string {
+			if i := columns[name]; i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		rate, err := newRate(field("date"), field("base"), field("quote"), field("rate"), source)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		rates = append(rates, rate)
+	}
+	return rates, nil
+}
+
+// ecbEnvelope is the layout of the ECB's eurofxref daily and historical files:
+// a Cube per day, holding a Cube per currency
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// ParseECBXML reads an ECB reference rate file. All of its rates are against the euro.
+func ParseECBXML(r io.Reader, source string) ([]models.ExchangeRate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("reading XML: %w", err)
+	}
+
+	var rates []models.ExchangeRate
+	for _, day := range envelope.Days {
+		for _, entry := range day.Rates {
+			rate, err := newRate(day.Time, ECBBase, entry.Currency, entry.Rate, source)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", day.Time, entry.Currency, err)
+			}
+			rates = append(rates, rate)
+		}
+	}
+	if len(rates) == 0 {
+		return nil, errors.New("no rates found; expected Cube elements with time, currency and rate attributes")
+	}
+	return rates, nil
+}
+
+func newRate(date, base, quote, rate, source string) (models.ExchangeRate, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return models.ExchangeRate{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD", date)
+	}
+	base, quote = strings.ToUpper(base), strings.ToUpper(quote)
+	if !IsCurrencyCode(base) || !IsCurrencyCode(quote) {
+		return models.ExchangeRate{}, fmt.Errorf("invalid currency pair %q/%q", base, quote)
+	}
+	if base == quote {
+		return models.ExchangeRate{}, fmt.Errorf("%s is quoted against itself", base)
+	}
+	parsed, err := money.ParseRate(rate)
+	if err != nil {
+		return models.ExchangeRate{}, err
+	}
+
+	return models.ExchangeRate{
+		Base:   base,
+		Quote:  quote,
+		Date:   day,
+		Rate:   parsed,
+		Source: source,
+	}, nil
+}
+
+// IsCurrencyCode reports whether code looks like an ISO 4217 code (three uppercase letters)
+func IsCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for i := 0; i < len(code); i++ {
+		if code[i] < 'A' || code[i] > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// Import stores rates, replacing any already stored for the same pair and day.
+// Everything is written in one transaction, so a failed import changes nothing.
+// It returns the number of rates stored.
+func Import(ctx context.Context, db *gorm.DB, rates []models.ExchangeRate) (int, error) {
+	// A pair and day may only appear once per upsert; later rows in the file win
+	type key struct {
+		base, quote string
+		date        time.Time
+	}
+	index := make(map[key]int, len(rates))
+	unique := make([]models.ExchangeRate, 0, len(rates))
+	for _, rate := range rates {
+		k := key{rate.Base, rate.Quote, rate.Date}
+		if i, ok := index[k]; ok {
+			unique[i] = rate
+			continue
+		}
+		index[k] = len(unique)
+		unique = append(unique, rate)
+	}
+	if len(unique) == 0 {
+		return 0, nil
+	}
+
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "base"}, {Name: "quote"}, {Name: "date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "source", "updated_at"}),
+		}).CreateInBatches(unique, importBatchSize).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(unique), nil
+}
@@ -0,0 +1,116 @@
+// internal/exchange/import_test.go
+package exchange
+
+import (
+	"strings"
+	"testing"
+)
+
+const ecbXML = `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<gesmes:subject>Reference rates</gesmes:subject>
+	<gesmes:Sender>
+		<gesmes:name>European Central Bank</gesmes:name>
+	</gesmes:Sender>
+	<Cube>
+		<Cube time="2025-01-16">
+			<Cube currency="USD" rate="1.0298"/>
+			<Cube currency="JPY" rate="160.72"/>
+		</Cube>
+		<Cube time="2025-01-15">
+			<Cube currency="USD" rate="1.0305"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+func TestParseCSV(t *testing.T) {
+	input := "Rate, Quote ,base,date,note\n" +
+		"1.0305,usd,eur,2025-01-15,first\n" +
+		"0.844,GBP,EUR,2025-01-15\n"
+	rates, err := Parse(strings.NewReader("\ufeff\n"+input), "rates.csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rates) != 2 {
+		t.Fatalf("parsed %d rates, want 2", len(rates))
+	}
+	got := rates[0]
+	if got.Base != "EUR" || got.Quote != "USD" || !got.Date.Equal(day(15)) || got.Rate.String() != "1.0305" || got.Source != "rates.csv" {
+		t.Errorf("parsed %+v", got)
+	}
+	if rates[1].Quote != "GBP" || rates[1].Rate.String() != "0.844" {
+		t.Errorf("parsed %+v", rates[1])
+	}
+}
+
+func TestParseCSVRejectsInvalidRows(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"date,base,quote\n2025-01-15,EUR,USD\n", `header has no "rate" column`},
+		{"date,base,quote,rate\n15/01/2025,EUR,USD,1.0305\n", "line 2: invalid date"},
+		{"date,base,quote,rate\n2025-01-15,EUR,USD,1.0305\n2025-01-15,EUR,US,1.0305\n", "line 3: invalid currency pair"},
+		{"date,base,quote,rate\n2025-01-15,EUR,EUR,1\n", "line 2: EUR is quoted against itself"},
+		{"date,base,quote,rate\n2025-01-15,EUR,USD,-1.0305\n", "line 2: rate must be"},
+		{"date,base,quote,rate\n2025-01-15,EUR,USD\n", "line 2: rate must be"},
+	}
+	for _, tt := range tests {
+		_, err := ParseCSV(strings.NewReader(tt.input), "rates.csv")
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("ParseCSV(%q) = %v, want an error containing %q", tt.input, err, tt.err)
+		}
+	}
+}
+
+func TestParseECBXML(t *testing.T) {
+	rates, err := Parse(strings.NewReader("  \n"+ecbXML), "eurofxref-hist.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		date        int
+		quote, rate string
+	}{
+		{16, "USD", "1.0298"},
+		{16, "JPY", "160.72"},
+		{15, "USD", "1.0305"},
+	}
+	if len(rates) != len(want) {
+		t.Fatalf("parsed %d rates, want %d", len(rates), len(want))
+	}
+	for i, w := range want {
+		got := rates[i]
+		if got.Base != ECBBase || got.Quote != w.quote || !got.Date.Equal(day(w.date)) || got.Rate.String() != w.rate || got.Source != "eurofxref-hist.xml" {
+			t.Errorf("rate %d = %+v, want EUR/%s %s on the %dth", i, got, w.quote, w.rate, w.date)
+		}
+	}
+}
+
+func TestParseECBXMLRejectsInvalidFiles(t *testing.T) {
+	tests := []struct {
+		input string
+		err   string
+	}{
+		{"<Envelope><Cube></Cube></Envelope>", "no rates found"},
+		{"<Envelope><Cube><Cube time=\"2025-01-15\"><Cube currency=\"USD\" rate=\"abc\"/></Cube></Cube></Envelope>", "2025-01-15 USD: rate must be"},
+		{"<Envelope><Cube>", "reading XML"},
+		{"", "file is empty"},
+		{" \n ", "file is empty"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(strings.NewReader(tt.input), "rates.xml")
+		if err == nil || !strings.Contains(err.Error(), tt.err) {
+			t.Errorf("Parse(%q) = %v, want an error containing %q", tt.input, err, tt.err)
+		}
+	}
+}
+
+func TestIsCurrencyCode(t *testing.T) {
+	for code, want := range map[string]bool{"USD": true, "EUR": true, "usd": false, "US": false, "USDT": false, "U5D": false, "": false} {
+		if got := IsCurrencyCode(code); got != want {
+			t.Errorf("IsCurrencyCode(%q) = %v, want %v", code, got, want)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+// internal/handlers/account_data_handler.go
+package handlers
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/audit"
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" example:"password123"` // Not needed by users without a password who signed in within the last 10 minutes
+}
+
+// ExportProfile is the profile.json file of a data export
+type ExportProfile struct {
+	ID               uint       `json:"id"`
+	Name             string     `json:"name"`
+	Email            string     `json:"email"`
+	EmailVerified    bool       `json:"email_verified"`
+	Role             string     `json:"role"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled"`
+	CreatedAt        time.Time  `json:"created_at"`
+	LastLoginAt      *time.Time `json:"last_login_at"`
+}
+
+// accountExport is everything a user can download about themselves
+type accountExport struct {
+	Profile    ExportProfile
+	Categories []models.FinanceCategory
+	Accounts   []models.FinanceAccount
+	Journals   []models.FinanceJournal
+	Transfers  []models.FinanceTransfer
+	Budgets    []models.FinanceBudget
+	APIKeys    []APIKeyInfo
+	Sessions   []models.Session
+	Identities []models.UserIdentity
+	Events     []models.AuditEvent
+}
+
+// ExportData godoc
+// @Summary Export personal data
+// @Description Download a ZIP archive with the user's profile, categories, finance accounts, journals (JSON and CSV), transfers, budgets, API key metadata, sessions, linked sign-in providers and security events. API key secrets and password hashes are never included.
+// @Tags Users
+// @Security BearerAuth
+// @Produce application/zip
+// @Success 200 {file} file
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/export [get]
+func (h *UserHandler) ExportData(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var user models.User
+	if err := h.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	// Load everything before writing, so a failure can still return an error response
+	export, err := h.loadAccountExport(&user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to export data"})
+		return
+	}
+
+	filename := fmt.Sprintf("kaizen-export-%s.zip", time.Now().Format("2006-01-02"))
+	c.Header("Content-Type", "application/zip")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Header("Cache-Control", "no-store")
+	c.Status(http.StatusOK)
+
+	if err := writeAccountExport(c.Writer, export); err != nil {
+		// Headers are already sent; abort so the client sees a truncated download
+		c.Error(err)
+		c.Abort()
+	}
+}
+
+// DeleteAccount godoc
+// @Summary Delete account
+// @Description Permanently delete the current user and all of their data (categories, finance accounts, journals, transfers, budgets, API keys, sessions and tokens). The audit log keeps its events, and a record of the deletion, under the account's email. Requires the current password, or for users without one, a sign-in within the last 10 minutes.
+// @Tags Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body DeleteAccountRequest true "Current password"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "No password and not signed in recently"
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me [delete]
+func (h *UserHandler) DeleteAccount(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var req DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := h.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	if err := confirmIdentity(c, h.DB, &user, req.Password); errors.Is(err, errWrongPassword) {
+		audit.Record(c, h.DB, audit.Event{Type: audit.EventAccountDeleted, Failed: true, UserID: userID, Details: "Wrong password"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Password is incorrect"})
+		return
+	} else if err != nil {
+		respondIdentityError(c, err)
+		return
+	}
+
+	// Deleting the user row cascades to every table that references it, except the
+	// audit log: its events lose the user ID but keep the email and actor
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		audit.Record(c, tx, audit.Event{Type: audit.EventAccountDeleted, UserID: user.ID, ActorID: user.ID, Email: user.Email})
+		if err := tx.Unscoped().Delete(&models.User{}, user.ID).Error; err != nil {
+			return err
+		}
+		return auth.UnlockAccount(user.Email, tx)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete account"})
+		return
+	}
+
+	// The tokens are already gone with the user; this stops every instance trusting cached copies
+	if err := auth.RevokeAllUserTokens(user.ID, h.DB); err != nil {
+		log.Printf("failed to revoke tokens of deleted user %d: %v", user.ID, err)
+	}
+
+	clearAuthCookies(c)
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Account deleted successfully"})
+}
+
+func (h *UserHandler) loadAccountExport(user *models.User) (*accountExport, error) {
+	export := &accountExport{
+		Profile: ExportProfile{
+			ID:               user.ID,
+			Name:             user.Name,
+			Email:            user.Email,
+			EmailVerified:    user.EmailVerified,
+			Role:             user.Role,
+			TwoFactorEnabled: user.TOTPEnabled,
+			CreatedAt:        user.CreatedAt,
+			LastLoginAt:      user.LastLoginAt,
+		},
+	}
+
+	if err := h.DB.Where("user_id = ?", user.ID).Order("id").Find(&export.Categories).Error; err != nil {
+		return nil, err
+	}
+	if err := h.DB.Where("user_id = ?", user.ID).Order("id").Find(&export.Accounts).Error; err != nil {
+		return nil, err
+	}
+	if err := h.DB.Where("user_id = ?", user.ID).Preload("Category").Order("date, id").Find(&export.Journals).Error; err != nil {
+		return nil, err
+	}
+	if err := h.DB.Where("user_id = ?", user.ID).Order("date, id").Find(&export.Transfers).Error; err != nil {
+		return nil, err
+	}
+	if err := h.DB.Where("user_id = ?", user.ID).Order("id").Find(&export.Budgets).Error; err != nil {
+		return nil, err
+	}
+
+	var apiKeys []models.APIKey
+	if err := h.DB.Where("user_id = ?", user.ID).Order("id").Find(&apiKeys).Error; err != nil {
+		return nil, err
+	}
+	for _, key := range apiKeys {
+		export.APIKeys = append(export.APIKeys, apiKeyInfo(&key))
+	}
+
+	if err := h.DB.Where("user_id = ?", user.ID).Order("created_at").Find(&export.Sessions).Error; err != nil {
+		return nil, err
+	}
+	if err := h.DB.Where("user_id = ?", user.ID).Order("id").Find(&export.Identities).Error; err != nil {
+		return nil, err
+	}
+	if err := h.DB.Where("user_id = ?", user.ID).Order("id").Find(&export.Events).Error; err != nil {
+		return nil, err
+	}
+
+	return export, nil
+}
+
+func writeAccountExport(w http.ResponseWriter, export *accountExport) error {
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name  string
+		write func(*zip.Writer, string) error
+	}{
+		{"profile.json", jsonFile(export.Profile)},
+		{"categories.json", jsonFile(export.Categories)},
+		{"categories.csv", csvFile(categoryRecords(export.Categories))},
+		{"accounts.json", jsonFile(export.Accounts)},
+		{"journals.json", jsonFile(export.Journals)},
+		{"journals.csv", csvFile(journalRecords(export.Journals))},
+		{"transfers.json", jsonFile(export.Transfers)},
+		{"budgets.json", jsonFile(export.Budgets)},
+		{"api_keys.json", jsonFile(export.APIKeys)},
+		{"sessions.json", jsonFile(export.Sessions)},
+		{"linked_providers.json", jsonFile(export.Identities)},
+		{"security_events.json", jsonFile(export.Events)},
+	}
+	for _, file := range files {
+		if err := file.write(archive, file.name); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+func jsonFile(v interface{}) func(*zip.Writer, string) error {
+	return func(archive *zip.Writer, name string) error {
+		f, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(f)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(v)
+	}
+}
+
+func csvFile(records [][]string) func(*zip.Writer, string) error {
+	return func(archive *zip.Writer, name string) error {
+		f, err := archive.Create(name)
+		if err != nil {
+			return err
+		}
+		writer := csv.NewWriter(f)
+		if err := writer.WriteAll(records); err != nil {
+			return err
+		}
+		return writer.Error()
+	}
+}
+
+func categoryRecords(categories []models.FinanceCategory) [][]string {
+	records := [][]string{{"id", "name", "type", "description", "color", "icon", "is_active", "created_at"}}
+	for _, category := range categories {
+		records = append(records, []string{
+			strconv.FormatUint(uint64(category.ID), 10),
+			category.Name,
+			category.Type,
+			category.Description,
+			category.Color,
+			category.Icon,
+			strconv.FormatBool(category.IsActive),
+			category.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return records
+}
+
+func journalRecords(journals []models.FinanceJournal) [][]string {
+	records := [][]string{{"id", "date", "type", "category_id", "category", "title", "description", "amount", "currency", "account_id", "transfer_id", "payment_method", "location", "is_recurring", "receipt_url", "created_at"}}
+	for _, journal := range journals {
+		var categoryName string
+		if journal.Category != nil {
+			categoryName = journal.Category.Name
+		}
+		records = append(records, []string{
+			strconv.FormatUint(uint64(journal.ID), 10),
+			journal.Date.Format("2006-01-02"),
+			journal.Type,
+			optionalID(journal.CategoryID),
+			categoryName,
+			journal.Title,
+			journal.Description,
+			journal.Amount.String(),
+			journal.Currency,
+			strconv.FormatUint(uint64(journal.AccountID), 10),
+			optionalID(journal.TransferID),
+			journal.PaymentMethod,
+			journal.Location,
+			strconv.FormatBool(journal.IsRecurring),
+			journal.ReceiptURL,
+			journal.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return records
+}
+
+// optionalID formats a nullable ID, empty when it is not set
+func optionalID(id *uint) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*id), 10)
+}
@@ -0,0 +1,49 @@
+// internal/handlers/account_data_handler_test.go
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/jedi116/kaizen-api/internal/audit"
+	"github.com/jedi116/kaizen-api/internal/handlers"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+func TestDeleteAccountKeepsAuditTrail(t *testing.T) {
+	api := startTestAPI(t, nil)
+	email := uniqueEmail("delete")
+	registered := register(t, api, email)
+	userID := registered.User.ID
+
+	status := do(t, newClient(t), http.MethodDelete, api.URL+"/api/users/me", registered.AccessToken,
+		handlers.DeleteAccountRequest{Password: "staple-orbit-lantern-42"}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("deleting the account returned %d, want 200", status)
+	}
+
+	var count int64
+	api.DB.Model(&models.User{}).Where("id = ?", userID).Count(&count)
+	if count != 0 {
+		t.Fatal("user still exists")
+	}
+
+	var events []models.AuditEvent
+	if err := api.DB.Where("actor_id = ?", userID).Order("id").Find(&events).Error; err != nil {
+		t.Fatal(err)
+	}
+	types := make(map[string]models.AuditEvent)
+	for _, event := range events {
+		if event.UserID != nil {
+			t.Errorf("%s event still references the deleted user", event.Type)
+		}
+		types[event.Type] = event
+	}
+	if _, ok := types[audit.EventRegister]; !ok {
+		t.Errorf("events of the deleted account were not kept: %v", events)
+	}
+	deleted, ok := types[audit.EventAccountDeleted]
+	if !ok || deleted.Outcome != models.AuditOutcomeSuccess || deleted.Email != email {
+		t.Errorf("deletion not recorded with the account's email: %+v", deleted)
+	}
+}
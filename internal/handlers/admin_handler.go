@@ -0,0 +1,433 @@
+// internal/handlers/admin_handler.go
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/audit"
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+type AdminHandler struct {
+	DB *gorm.DB
+}
+
+// AdminUserResponse is a user account as seen by administrators
+type AdminUserResponse struct {
+	ID               uint       `json:"id" example:"42"`
+	Name             string     `json:"name" example:"John Doe"`
+	Email            string     `json:"email" example:"john@example.com"`
+	EmailVerified    bool       `json:"email_verified" example:"true"`
+	Role             string     `json:"role" example:"user"`
+	TwoFactorEnabled bool       `json:"two_factor_enabled" example:"false"`
+	CreatedAt        time.Time  `json:"created_at"`
+	LastLoginAt      *time.Time `json:"last_login_at"`
+	DisabledAt       *time.Time `json:"disabled_at"`
+}
+
+type AdminUserListResponse struct {
+	Users      []AdminUserResponse `json:"users"`
+	TotalCount int64               `json:"total_count"`
+	Page       int                 `json:"page"`
+	PageSize   int                 `json:"page_size"`
+}
+
+type SetRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin" example:"admin"`
+}
+
+// AdminStatsResponse holds system-wide counts
+type AdminStatsResponse struct {
+	TotalUsers     int64 `json:"total_users" example:"1250"`
+	VerifiedUsers  int64 `json:"verified_users" example:"1100"`
+	DisabledUsers  int64 `json:"disabled_users" example:"3"`
+	AdminUsers     int64 `json:"admin_users" example:"2"`
+	TwoFactorUsers int64 `json:"two_factor_users" example:"310"`
+	Categories     int64 `json:"categories" example:"8400"`
+	Journals       int64 `json:"journals" example:"512000"`
+	ActiveAPIKeys  int64 `json:"active_api_keys" example:"95"`
+	ActiveSessions int64 `json:"active_sessions" example:"1830"`
+}
+
+// ListUsers godoc
+// @Summary List users
+// @Description List and search user accounts (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param q query string false "Search by name or email"
+// @Param role query string false "Filter by role (user or admin)"
+// @Param disabled query bool false "Filter by disabled status"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} AdminUserListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	query := h.DB.Model(&models.User{})
+
+	// Search by name or email
+	if q := strings.TrimSpace(c.Query("q")); q != "" {
+		pattern := "%" + escapeLike(q) + "%"
+		query = query.Where("email ILIKE ? OR name ILIKE ?", pattern, pattern)
+	}
+
+	// Filter by role
+	if role := c.Query("role"); role == models.RoleUser || role == models.RoleAdmin {
+		query = query.Where("role = ?", role)
+	}
+
+	// Filter by disabled status
+	switch c.Query("disabled") {
+	case "true":
+		query = query.Where("disabled_at IS NOT NULL")
+	case "false":
+		query = query.Where("disabled_at IS NULL")
+	}
+
+	// Get total count
+	var totalCount int64
+	query.Count(&totalCount)
+
+	// Pagination
+	page := 1
+	pageSize := 20
+	if p := c.Query("page"); p != "" {
+		if parsed, err := parseInt(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+	if ps := c.Query("page_size"); ps != "" {
+		if parsed, err := parseInt(ps); err == nil && parsed > 0 && parsed <= 100 {
+			pageSize = parsed
+		}
+	}
+	offset := (page - 1) * pageSize
+
+	var users []models.User
+	if err := query.Order("created_at DESC").Offset(offset).Limit(pageSize).Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to fetch users"})
+		return
+	}
+
+	response := make([]AdminUserResponse, 0, len(users))
+	for i := range users {
+		response = append(response, adminUserResponse(&users[i]))
+	}
+
+	c.JSON(http.StatusOK, AdminUserListResponse{
+		Users:      response,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+	})
+}
+
+// GetUser godoc
+// @Summary Get a user
+// @Description Get a user account by ID (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} AdminUserResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /admin/users/{id} [get]
+func (h *AdminHandler) GetUser(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, adminUserResponse(user))
+}
+
+// DisableUser godoc
+// @Summary Disable a user
+// @Description Disable an account: it is signed out everywhere, cannot log in and its API keys stop working (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} AdminUserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/users/{id}/disable [post]
+func (h *AdminHandler) DisableUser(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	if h.isSelf(c, user) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "You cannot disable your own account"})
+		return
+	}
+
+	if !user.IsDisabled() {
+		now := time.Now()
+		if err := h.DB.Model(user).Update("disabled_at", now).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to disable user"})
+			return
+		}
+		user.DisabledAt = &now
+	}
+
+	if err := auth.RevokeAllUserTokens(user.ID, h.DB); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to sign user out"})
+		return
+	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventAccountDisabled, UserID: user.ID})
+
+	c.JSON(http.StatusOK, adminUserResponse(user))
+}
+
+// EnableUser godoc
+// @Summary Re-enable a user
+// @Description Re-enable a disabled account (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} AdminUserResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/users/{id}/enable [post]
+func (h *AdminHandler) EnableUser(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	if err := h.DB.Model(user).Update("disabled_at", nil).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to enable user"})
+		return
+	}
+	user.DisabledAt = nil
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventAccountEnabled, UserID: user.ID})
+
+	c.JSON(http.StatusOK, adminUserResponse(user))
+}
+
+// LogoutUser godoc
+// @Summary Force-logout a user
+// @Description Revoke every token of a user, signing them out on all devices (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} MessageResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/users/{id}/logout [post]
+func (h *AdminHandler) LogoutUser(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	if err := auth.RevokeAllUserTokens(user.ID, h.DB); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to sign user out"})
+		return
+	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventLogoutAll, UserID: user.ID})
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "User logged out from all devices"})
+}
+
+// UnlockUser godoc
+// @Summary Unlock a user
+// @Description Clear failed login attempts and any login lockout on an account (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "User ID"
+// @Success 200 {object} MessageResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/users/{id}/unlock [post]
+func (h *AdminHandler) UnlockUser(c *gin.Context) {
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	if err := auth.UnlockAccount(user.Email, h.DB); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to unlock user"})
+		return
+	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventAccountUnlocked, UserID: user.ID})
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "User unlocked"})
+}
+
+// SetUserRole godoc
+// @Summary Change a user's role
+// @Description Change a user's role. The user is signed out so the new role applies immediately (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "User ID"
+// @Param request body SetRoleRequest true "New role"
+// @Success 200 {object} AdminUserResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/users/{id}/role [put]
+func (h *AdminHandler) SetUserRole(c *gin.Context) {
+	var req SetRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	user, ok := h.findUser(c)
+	if !ok {
+		return
+	}
+
+	if h.isSelf(c, user) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "You cannot change your own role"})
+		return
+	}
+
+	if user.Role != req.Role {
+		oldRole := user.Role
+		if err := h.DB.Model(user).Update("role", req.Role).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to change role"})
+			return
+		}
+		user.Role = req.Role
+		audit.Record(c, h.DB, audit.Event{Type: audit.EventRoleChanged, UserID: user.ID, Details: oldRole + " to " + req.Role})
+
+		// Existing tokens still carry the old role
+		if err := auth.RevokeAllUserTokens(user.ID, h.DB); err != nil {
+			log.Printf("failed to revoke tokens after role change for user %d: %v", user.ID, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, adminUserResponse(user))
+}
+
+// GetStats godoc
+// @Summary System statistics
+// @Description Get system-wide counts of users, finance data, API keys and sessions (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} AdminStatsResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/stats [get]
+func (h *AdminHandler) GetStats(c *gin.Context) {
+	var stats AdminStatsResponse
+	now := time.Now()
+
+	err := h.DB.Model(&models.User{}).
+		Select("COUNT(*) AS total_users, " +
+			"COUNT(*) FILTER (WHERE email_verified) AS verified_users, " +
+			"COUNT(*) FILTER (WHERE disabled_at IS NOT NULL) AS disabled_users, " +
+			"COUNT(*) FILTER (WHERE role = 'admin') AS admin_users, " +
+			"COUNT(*) FILTER (WHERE totp_enabled) AS two_factor_users").
+		Scan(&stats).Error
+	if err == nil {
+		err = h.DB.Model(&models.FinanceCategory{}).Count(&stats.Categories).Error
+	}
+	if err == nil {
+		err = h.DB.Model(&models.FinanceJournal{}).Count(&stats.Journals).Error
+	}
+	if err == nil {
+		err = h.DB.Model(&models.APIKey{}).
+			Where("is_active = ? AND (expires_at IS NULL OR expires_at > ?)", true, now).
+			Count(&stats.ActiveAPIKeys).Error
+	}
+	if err == nil {
+		err = h.DB.Model(&models.Session{}).Where("expires_at > ?", now).Count(&stats.ActiveSessions).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to fetch statistics"})
+		return
+	}
+
+	c.JSON(http.StatusOK, stats)
+}
+
+// ListJobs godoc
+// @Summary List background jobs
+// @Description Get the schedule, last run and outcome of every background job (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {array} models.ScheduledJob
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/jobs [get]
+func (h *AdminHandler) ListJobs(c *gin.Context) {
+	var jobs []models.ScheduledJob
+	if err := h.DB.Order("name").Find(&jobs).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to fetch jobs"})
+		return
+	}
+
+	c.JSON(http.StatusOK, jobs)
+}
+
+// findUser loads the user named by the :id path parameter, responding with 404 if there is none
+func (h *AdminHandler) findUser(c *gin.Context) (*models.User, bool) {
+	var user models.User
+	if err := h.DB.First(&user, "id = ?", c.Param("id")).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		return nil, false
+	}
+	return &user, true
+}
+
+// isSelf reports whether user is the administrator making the request
+func (h *AdminHandler) isSelf(c *gin.Context, user *models.User) bool {
+	adminID, _ := auth.GetUserID(c)
+	return adminID == user.ID
+}
+
+func adminUserResponse(user *models.User) AdminUserResponse {
+	return AdminUserResponse{
+		ID:               user.ID,
+		Name:             user.Name,
+		Email:            user.Email,
+		EmailVerified:    user.EmailVerified,
+		Role:             user.Role,
+		TwoFactorEnabled: user.TOTPEnabled,
+		CreatedAt:        user.CreatedAt,
+		LastLoginAt:      user.LastLoginAt,
+		DisabledAt:       user.DisabledAt,
+	}
+}
+
+// escapeLike escapes the wildcard characters of a LIKE pattern
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,165 @@
+// internal/handlers/admin_handler_test.go
+package handlers_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/handlers"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+// registerAdmin registers a user, makes them an administrator and signs in
+// again so the token carries the role
+func registerAdmin(t *testing.T, api *testAPI) handlers.AuthResponse {
+	t.Helper()
+	email := uniqueEmail("admin")
+	registered := register(t, api, email)
+	if err := api.DB.Model(&models.User{}).Where("id = ?", registered.User.ID).Update("role", models.RoleAdmin).Error; err != nil {
+		t.Fatal(err)
+	}
+	var signedIn handlers.AuthResponse
+	if status := login(t, api, email, &signedIn); status != http.StatusOK {
+		t.Fatalf("admin sign-in returned %d", status)
+	}
+	return signedIn
+}
+
+func TestAdminRoutesRequireAdminRole(t *testing.T) {
+	api := startTestAPI(t, nil)
+	admin := registerAdmin(t, api)
+	user := register(t, api, uniqueEmail("user"))
+	key := createAPIKey(t, api, admin.AccessToken, auth.ScopeJournalsRead)
+	client := newClient(t)
+	statsURL := api.URL + "/api/admin/stats"
+
+	if status := do(t, client, http.MethodGet, statsURL, "", nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("without a token got %d, want 401", status)
+	}
+	if status := do(t, client, http.MethodGet, statsURL, user.AccessToken, nil, nil); status != http.StatusForbidden {
+		t.Errorf("a user got %d, want 403", status)
+	}
+	if status := doWithAPIKey(t, api, http.MethodGet, "/api/admin/stats", key.Key, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("an admin's API key got %d, want 401", status)
+	}
+
+	var stats handlers.AdminStatsResponse
+	if status := do(t, client, http.MethodGet, statsURL, admin.AccessToken, nil, &stats); status != http.StatusOK {
+		t.Fatalf("an admin got %d, want 200", status)
+	}
+	if stats.TotalUsers < 2 || stats.AdminUsers < 1 {
+		t.Errorf("stats count %d users and %d admins, want at least 2 and 1", stats.TotalUsers, stats.AdminUsers)
+	}
+}
+
+func TestAdminManagesUsers(t *testing.T) {
+	api := startTestAPI(t, nil)
+	admin := registerAdmin(t, api)
+	email := uniqueEmail("managed")
+	user := register(t, api, email)
+	client := newClient(t)
+	userURL := api.URL + "/api/admin/users/" + strconv.Itoa(int(user.User.ID))
+
+	var list handlers.AdminUserListResponse
+	status := do(t, client, http.MethodGet, api.URL+"/api/admin/users?q="+url.QueryEscape(email), admin.AccessToken, nil, &list)
+	if status != http.StatusOK || list.TotalCount != 1 || list.Users[0].ID != user.User.ID {
+		t.Fatalf("searching for %s returned %d with %+v", email, status, list)
+	}
+
+	// Disabling signs the user out and keeps them out until re-enabled
+	var disabled handlers.AdminUserResponse
+	if status := do(t, client, http.MethodPost, userURL+"/disable", admin.AccessToken, nil, &disabled); status != http.StatusOK || disabled.DisabledAt == nil {
+		t.Fatalf("disabling returned %d with %+v", status, disabled)
+	}
+	if status := do(t, client, http.MethodGet, api.URL+"/api/users/me", user.AccessToken, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("a disabled user's session got %d, want 401", status)
+	}
+	if status := login(t, api, email, nil); status != http.StatusForbidden {
+		t.Errorf("a disabled user signing in got %d, want 403", status)
+	}
+	if status := do(t, client, http.MethodPost, userURL+"/enable", admin.AccessToken, nil, nil); status != http.StatusOK {
+		t.Fatalf("enabling returned %d, want 200", status)
+	}
+
+	var signedIn handlers.AuthResponse
+	if status := login(t, api, email, &signedIn); status != http.StatusOK {
+		t.Fatalf("a re-enabled user signing in got %d, want 200", status)
+	}
+
+	// A new role signs the user out, and their next session carries it
+	var promoted handlers.AdminUserResponse
+	status = do(t, client, http.MethodPut, userURL+"/role", admin.AccessToken, handlers.SetRoleRequest{Role: models.RoleAdmin}, &promoted)
+	if status != http.StatusOK || promoted.Role != models.RoleAdmin {
+		t.Fatalf("promoting returned %d with role %q", status, promoted.Role)
+	}
+	if status := do(t, client, http.MethodGet, api.URL+"/api/users/me", signedIn.AccessToken, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("a session from before the role change got %d, want 401", status)
+	}
+	if status := login(t, api, email, &signedIn); status != http.StatusOK {
+		t.Fatalf("signing in after promotion returned %d", status)
+	}
+	if status := do(t, client, http.MethodGet, api.URL+"/api/admin/stats", signedIn.AccessToken, nil, nil); status != http.StatusOK {
+		t.Errorf("a promoted user got %d from the admin API, want 200", status)
+	}
+
+	// Administrators can't lock themselves out
+	selfURL := api.URL + "/api/admin/users/" + strconv.Itoa(int(admin.User.ID))
+	if status := do(t, client, http.MethodPost, selfURL+"/disable", admin.AccessToken, nil, nil); status != http.StatusBadRequest {
+		t.Errorf("disabling yourself returned %d, want 400", status)
+	}
+	status = do(t, client, http.MethodPut, selfURL+"/role", admin.AccessToken, handlers.SetRoleRequest{Role: models.RoleUser}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("demoting yourself returned %d, want 400", status)
+	}
+	if status := do(t, client, http.MethodGet, api.URL+"/api/admin/users/0", admin.AccessToken, nil, nil); status != http.StatusNotFound {
+		t.Errorf("getting a missing user returned %d, want 404", status)
+	}
+}
+
+func TestAdminListsJobs(t *testing.T) {
+	api := startTestAPI(t, nil)
+	admin := registerAdmin(t, api)
+	user := register(t, api, uniqueEmail("user"))
+	client := newClient(t)
+
+	finished := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+	stored := models.ScheduledJob{
+		Name:           fmt.Sprintf("test_job_%d", time.Now().UnixNano()),
+		Schedule:       "@hourly",
+		LastFinishedAt: &finished,
+		LastStatus:     models.JobStatusFailed,
+		LastError:      "database unavailable",
+		LastRunBy:      "api-1/42",
+		RunCount:       3,
+		FailureCount:   1,
+	}
+	if err := api.DB.Create(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	if status := do(t, client, http.MethodGet, api.URL+"/api/admin/jobs", user.AccessToken, nil, nil); status != http.StatusForbidden {
+		t.Errorf("a user listing jobs got %d, want 403", status)
+	}
+
+	var jobs []models.ScheduledJob
+	if status := do(t, client, http.MethodGet, api.URL+"/api/admin/jobs", admin.AccessToken, nil, &jobs); status != http.StatusOK {
+		t.Fatalf("listing jobs returned %d, want 200", status)
+	}
+	for _, job := range jobs {
+		if job.Name != stored.Name {
+			continue
+		}
+		if job.Schedule != stored.Schedule || job.LastStatus != stored.LastStatus || job.LastError != stored.LastError ||
+			job.LastRunBy != stored.LastRunBy || job.RunCount != 3 || job.FailureCount != 1 ||
+			job.LastFinishedAt == nil || !job.LastFinishedAt.Equal(finished) {
+			t.Errorf("listed job %+v, want %+v", job, stored)
+		}
+		return
+	}
+	t.Errorf("job %s not listed", stored.Name)
+}
@@ -0,0 +1,142 @@
+// internal/handlers/audit_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+type AuditEventListResponse struct {
+	Events     []models.AuditEvent `json:"events"`
+	TotalCount int64               `json:"total_count"`
+	Page       int                 `json:"page"`
+	PageSize   int                 `json:"page_size"`
+}
+
+// ListSecurityEvents godoc
+// @Summary List security events
+// @Description Review activity on the current user's account: sign-ins (including failed attempts), refreshes, logouts, password, email and profile changes, and API key changes. Newest first.
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param type query string false "Filter by event type (e.g. login, api_key_created)"
+// @Param outcome query string false "Filter by outcome (success or failure)"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} AuditEventListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/security-events [get]
+func (h *UserHandler) ListSecurityEvents(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	query := filterAuditEvents(c, h.DB.Model(&models.AuditEvent{}).Where("user_id = ?", userID))
+	listAuditEvents(c, query)
+}
+
+// ListAuditEvents godoc
+// @Summary Query the audit log
+// @Description Search authentication and account events across all users, newest first (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param user_id query int false "Filter by the account the event concerns"
+// @Param actor_id query int false "Filter by the user who performed the action"
+// @Param email query string false "Filter by email named in the request (exact match)"
+// @Param ip_address query string false "Filter by client IP address"
+// @Param type query string false "Filter by event type (e.g. login, api_key_created)"
+// @Param outcome query string false "Filter by outcome (success or failure)"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} AuditEventListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/audit-events [get]
+func (h *AdminHandler) ListAuditEvents(c *gin.Context) {
+	query := h.DB.Model(&models.AuditEvent{})
+
+	if userID := c.Query("user_id"); userID != "" {
+		query = query.Where("user_id = ?", userID)
+	}
+	if actorID := c.Query("actor_id"); actorID != "" {
+		query = query.Where("actor_id = ?", actorID)
+	}
+	if email := strings.TrimSpace(c.Query("email")); email != "" {
+		query = query.Where("email = ?", email)
+	}
+	if ip := c.Query("ip_address"); ip != "" {
+		query = query.Where("ip_address = ?", ip)
+	}
+	if startDate := c.Query("start_date"); startDate != "" {
+		if parsed, err := time.Parse("2006-01-02", startDate); err == nil {
+			query = query.Where("created_at >= ?", parsed)
+		}
+	}
+	if endDate := c.Query("end_date"); endDate != "" {
+		if parsed, err := time.Parse("2006-01-02", endDate); err == nil {
+			query = query.Where("created_at < ?", parsed.AddDate(0, 0, 1))
+		}
+	}
+
+	listAuditEvents(c, filterAuditEvents(c, query))
+}
+
+// filterAuditEvents applies the type and outcome filters both endpoints share
+func filterAuditEvents(c *gin.Context, query *gorm.DB) *gorm.DB {
+	if eventType := c.Query("type"); eventType != "" {
+		query = query.Where("type = ?", eventType)
+	}
+	if outcome := c.Query("outcome"); outcome == models.AuditOutcomeSuccess || outcome == models.AuditOutcomeFailure {
+		query = query.Where("outcome = ?", outcome)
+	}
+	return query
+}
+
+// listAuditEvents responds with one page of the events matched by query
+func listAuditEvents(c *gin.Context, query *gorm.DB) {
+	// Get total count
+	var totalCount int64
+	query.Count(&totalCount)
+
+	// Pagination
+	page := 1
+	pageSize := 20
+	if p := c.Query("page"); p != "" {
+		if parsed, err := parseInt(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+	if ps := c.Query("page_size"); ps != "" {
+		if parsed, err := parseInt(ps); err == nil && parsed > 0 && parsed <= 100 {
+			pageSize = parsed
+		}
+	}
+	offset := (page - 1) * pageSize
+
+	var events []models.AuditEvent
+	if err := query.Order("created_at DESC, id DESC").Offset(offset).Limit(pageSize).Find(&events).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to fetch events"})
+		return
+	}
+
+	c.JSON(http.StatusOK, AuditEventListResponse{
+		Events:     events,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+	})
+}
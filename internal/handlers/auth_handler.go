@@ -2,26 +2,37 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
 	"net/http"
 	"os"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
+	"github.com/jedi116/kaizen-api/internal/audit"
 	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/mail"
 	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/oidc"
 )
 
 type AuthHandler struct {
-	DB *gorm.DB
+	DB            *gorm.DB
+	Mailer        mail.Mailer
+	OIDCProviders map[string]*oidc.Provider
 }
 
 type RegisterRequest struct {
 	Name     string `json:"name" binding:"required" example:"John Doe"`
 	Email    string `json:"email" binding:"required,email" example:"john@example.com"`
-	Password string `json:"password" binding:"required,min=8" example:"password123"`
+	Password string `json:"password" binding:"required" example:"correct-horse-battery"`
 }
 
 type LoginRequest struct {
@@ -29,6 +40,23 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required" example:"password123"`
 }
 
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ConfirmEmailChangeRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email" example:"john@example.com"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required" example:"staple-orbit-lantern"`
+}
+
 type AuthResponse struct {
 	AccessToken  string      `json:"access_token"`
 	RefreshToken string      `json:"refresh_token"`
@@ -36,9 +64,12 @@ type AuthResponse struct {
 }
 
 type UserProfile struct {
-	ID    uint   `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID            uint   `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	Role          string `json:"role" example:"user"`
+	BaseCurrency  string `json:"base_currency" example:"USD"`
 }
 
 // Register godoc
@@ -49,7 +80,7 @@ type UserProfile struct {
 // @Produce json
 // @Param request body RegisterRequest true "Registration details"
 // @Success 201 {object} AuthResponse
-// @Failure 400 {object} ErrorResponse
+// @Failure 400 {object} ValidationErrorResponse "Invalid request or password rejected by the password policy"
 // @Failure 500 {object} ErrorResponse
 // @Router /auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
@@ -66,6 +97,11 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
+	if err := auth.ValidatePassword(req.Password, req.Name, req.Email); err != nil {
+		respondPasswordError(c, "password", err)
+		return
+	}
+
 	// Hash password
 	hashedPassword, err := auth.HashPassword(req.Password)
 	if err != nil {
@@ -85,37 +121,29 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Generate tokens
-	tokens, err := auth.GenerateTokenPair(user.ID, user.Email, h.DB)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to generate tokens"})
-		return
-	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventRegister, UserID: user.ID, ActorID: user.ID})
 
-	// Set cookies
-	h.setAuthCookies(c, tokens)
+	// Send verification email; the account is usable even if this fails
+	if err := h.sendVerificationEmail(c, &user); err != nil {
+		log.Printf("failed to send verification email to user %d: %v", user.ID, err)
+	}
 
-	c.JSON(http.StatusCreated, AuthResponse{
-		AccessToken:  tokens.AccessToken,
-		RefreshToken: tokens.RefreshToken,
-		User: UserProfile{
-			ID:    user.ID,
-			Name:  user.Name,
-			Email: user.Email,
-		},
-	})
+	h.respondWithTokens(c, http.StatusCreated, &user)
 }
 
 // Login godoc
 // @Summary Login user
-// @Description Authenticate user and return tokens
+// @Description Authenticate user and return tokens. Users with two-factor authentication receive a challenge token instead, to be exchanged at /auth/2fa/verify.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body LoginRequest true "Login credentials"
 // @Success 200 {object} AuthResponse
+// @Success 202 {object} TwoFactorChallengeResponse
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
 // @Router /auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req LoginRequest
@@ -124,41 +152,114 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
+	// Refuse attempts while the account or IP is backing off or locked, and
+	// count this one until the password turns out to be right
+	if !h.reserveLoginAttempt(c, req.Email) {
+		return
+	}
+
 	// Find user
 	var user models.User
 	if err := h.DB.Where("email = ?", req.Email).First(&user).Error; err != nil {
+		audit.Record(c, h.DB, audit.Event{Type: audit.EventLogin, Failed: true, Email: req.Email, Details: "Unknown email"})
 		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid email or password"})
 		return
 	}
 
 	// Check password
 	if !auth.CheckPassword(user.Password, req.Password) {
+		audit.Record(c, h.DB, audit.Event{Type: audit.EventLogin, Failed: true, UserID: user.ID, Email: req.Email, Details: "Wrong password"})
 		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid email or password"})
 		return
 	}
+	h.releaseLoginAttempt(c)
+
+	// Users with two-factor authentication get a challenge instead of tokens
+	if user.TOTPEnabled {
+		h.respondWithChallenge(c, &user)
+		return
+	}
+
+	h.completeLogin(c, &user, "password")
+}
+
+// completeLogin records the login and responds with a fresh token pair.
+// Disabled accounts are refused here, after their credentials were checked.
+// method says how the user signed in, for the audit log.
+func (h *AuthHandler) completeLogin(c *gin.Context, user *models.User, method string) {
+	if user.IsDisabled() {
+		audit.Record(c, h.DB, audit.Event{Type: audit.EventLogin, Failed: true, UserID: user.ID, Email: user.Email, Details: "Account is disabled"})
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Account is disabled"})
+		return
+	}
 
-	// Update last login
 	now := time.Now()
 	user.LastLoginAt = &now
-	h.DB.Save(&user)
+	h.DB.Model(user).Update("last_login_at", now)
+
+	// A successful login clears earlier failures on the account
+	if err := auth.UnlockAccount(user.Email, h.DB); err != nil {
+		log.Printf("failed to reset login failures for user %d: %v", user.ID, err)
+	}
+
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventLogin, UserID: user.ID, ActorID: user.ID, Details: "Signed in with " + method})
+
+	h.respondWithTokens(c, http.StatusOK, user)
+}
+
+// reserveLoginAttempt counts a login attempt against email and the client IP,
+// or responds with 429 and returns false when either must wait
+func (h *AuthHandler) reserveLoginAttempt(c *gin.Context, email string) bool {
+	wait, err := auth.ReserveLoginAttempt(email, c.ClientIP(), h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to check login attempts"})
+		return false
+	}
+	if wait > 0 {
+		audit.Record(c, h.DB, audit.Event{Type: audit.EventLogin, Failed: true, Email: email, Details: "Too many failed attempts"})
+		seconds := int(math.Ceil(wait.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error: fmt.Sprintf("Too many failed login attempts. Try again in %d seconds.", seconds),
+		})
+		return false
+	}
+	return true
+}
 
-	// Generate tokens
-	tokens, err := auth.GenerateTokenPair(user.ID, user.Email, h.DB)
+// releaseLoginAttempt gives the client IP back an attempt whose credentials
+// were right. The account's attempts are cleared once the login completes.
+func (h *AuthHandler) releaseLoginAttempt(c *gin.Context) {
+	if err := auth.ReleaseLoginAttempt(c.ClientIP(), h.DB); err != nil {
+		log.Printf("failed to release login attempt: %v", err)
+	}
+}
+
+// respondWithTokens issues a token pair for user, sets the auth cookies and writes an AuthResponse
+func (h *AuthHandler) respondWithTokens(c *gin.Context, status int, user *models.User) {
+	tokens, err := auth.GenerateTokenPair(user, auth.SessionMetaFromRequest(c), h.DB)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to generate tokens"})
 		return
 	}
 
 	// Set cookies
-	h.setAuthCookies(c, tokens)
+	if err := h.setAuthCookies(c, tokens); err != nil {
+		log.Printf("failed to set auth cookies: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to generate tokens"})
+		return
+	}
 
-	c.JSON(http.StatusOK, AuthResponse{
+	c.JSON(status, AuthResponse{
 		AccessToken:  tokens.AccessToken,
 		RefreshToken: tokens.RefreshToken,
 		User: UserProfile{
-			ID:    user.ID,
-			Name:  user.Name,
-			Email: user.Email,
+			ID:            user.ID,
+			Name:          user.Name,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			Role:          user.Role,
+			BaseCurrency:  user.BaseCurrency,
 		},
 	})
 }
@@ -172,6 +273,14 @@ func (h *AuthHandler) Login(c *gin.Context) {
 // @Success 200 {object} MessageResponse
 // @Router /auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
+	userID, _ := auth.GetUserID(c)
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventLogout, UserID: userID})
+
+	// End the current session, revoking every token issued to this device
+	if sessionID := auth.GetSessionID(c); sessionID != "" {
+		auth.RevokeTokenFamily(sessionID, h.DB)
+	}
+
 	// Revoke access token from header
 	authHeader := c.GetHeader("Authorization")
 	if authHeader != "" {
@@ -186,24 +295,36 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 	}
 
 	// Clear cookies with SameSite
-	h.clearAuthCookies(c)
+	clearAuthCookies(c)
 
 	c.JSON(http.StatusOK, MessageResponse{Message: "Logged out successfully"})
 }
 
 // RefreshToken godoc
 // @Summary Refresh access token
-// @Description Get new access token using refresh token
+// @Description Get new access token using refresh token. Each refresh token can be used once; reusing one revokes every token from the same login.
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param refresh_token body object{refresh_token=string} false "Refresh token (optional if using cookies)"
+// @Param X-CSRF-Token header string false "Value of the csrf_token cookie, required when the refresh token comes from a cookie. Sessions without a csrf_token cookie need an Origin header naming the API or an allowed origin instead."
 // @Success 200 {object} auth.TokenPair
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "Missing or invalid CSRF token"
+// @Failure 500 {object} ErrorResponse
 // @Router /auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	// Try to get refresh token from cookie
 	refreshToken, _ := c.Cookie("refresh_token")
+	if refreshToken != "" && !auth.CheckCSRF(c) {
+		// Sessions from before the csrf_token cookie have no token to send. They
+		// are let through from a trusted origin and get one with the new cookies.
+		_, err := c.Cookie(auth.CSRFCookieName)
+		if err == nil || !auth.CheckOrigin(c) {
+			c.JSON(http.StatusForbidden, ErrorResponse{Error: "Missing or invalid CSRF token"})
+			return
+		}
+	}
 
 	// If not in cookie, try request body
 	if refreshToken == "" {
@@ -220,25 +341,39 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// Validate refresh token
-	claims, err := auth.ValidateToken(refreshToken, h.DB)
-	if err != nil {
+	// Rotate the refresh token within its family
+	tokens, claims, err := auth.RotateRefreshToken(refreshToken, auth.SessionMetaFromRequest(c), h.DB)
+	if errors.Is(err, auth.ErrRefreshTokenReused) {
+		// A rotated token was presented again, so it was probably stolen.
+		// The whole family has been revoked; record it for the account owner.
+		audit.Record(c, h.DB, audit.Event{
+			Type:    audit.EventRefreshTokenReuse,
+			Failed:  true,
+			UserID:  claims.UserID,
+			Details: "Refresh token " + claims.ID + " was presented after rotation; family " + claims.FamilyID + " revoked",
+		})
+		clearAuthCookies(c)
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Refresh token has already been used. Please log in again."})
+		return
+	}
+	if errors.Is(err, auth.ErrInvalidRefreshToken) || errors.Is(err, auth.ErrAccountDisabled) {
+		audit.Record(c, h.DB, audit.Event{Type: audit.EventRefresh, Failed: true, Details: err.Error()})
 		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid refresh token"})
 		return
 	}
-
-	// Revoke old tokens
-	auth.RevokeToken(refreshToken, h.DB)
-
-	// Generate new token pair
-	tokens, err := auth.GenerateTokenPair(claims.UserID, claims.Email, h.DB)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to generate tokens"})
+		log.Printf("failed to refresh tokens: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to refresh tokens"})
 		return
 	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventRefresh, UserID: claims.UserID, ActorID: claims.UserID})
 
 	// Set new cookies
-	h.setAuthCookies(c, tokens)
+	if err := h.setAuthCookies(c, tokens); err != nil {
+		log.Printf("failed to set auth cookies: %v", err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to refresh tokens"})
+		return
+	}
 
 	c.JSON(http.StatusOK, tokens)
 }
@@ -262,15 +397,315 @@ func (h *AuthHandler) LogoutAllDevices(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to logout from all devices"})
 		return
 	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventLogoutAll, UserID: userID})
 
 	// Clear cookies with SameSite
-	h.clearAuthCookies(c)
+	clearAuthCookies(c)
 
 	c.JSON(http.StatusOK, MessageResponse{Message: "Logged out from all devices"})
 }
 
-// Helper to set auth cookies with CSRF protection
-func (h *AuthHandler) setAuthCookies(c *gin.Context, tokens *auth.TokenPair) {
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Confirm an email address using the token sent by email
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body VerifyEmailRequest true "Verification token"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Router /auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	claims, err := auth.ValidateActionToken(req.Token, auth.PurposeEmailVerification)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid or expired verification token"})
+		return
+	}
+
+	// The token is only valid for the address it was sent to
+	result := h.DB.Model(&models.User{}).
+		Where("id = ? AND email = ?", claims.UserID, claims.Email).
+		Update("email_verified", true)
+	if result.Error != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify email"})
+		return
+	}
+	if result.RowsAffected == 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid or expired verification token"})
+		return
+	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventEmailVerified, UserID: claims.UserID, ActorID: claims.UserID})
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Email verified successfully"})
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Send a new email verification link to the current user
+// @Tags Authentication
+// @Security BearerAuth
+// @Produce json
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/resend-verification [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var user models.User
+	if err := h.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	if user.EmailVerified {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Email already verified"})
+		return
+	}
+
+	if err := h.sendVerificationEmail(c, &user); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to send verification email"})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Verification email sent"})
+}
+
+// ConfirmEmailChange godoc
+// @Summary Confirm email change
+// @Description Change the account's email to the new address using the token sent there. The previous address is notified.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body ConfirmEmailChangeRequest true "Email change token"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/confirm-email-change [post]
+func (h *AuthHandler) ConfirmEmailChange(c *gin.Context) {
+	var req ConfirmEmailChangeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var user models.User
+	var oldEmail string
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		changeToken, err := auth.ConsumeOneTimeToken(req.Token, auth.PurposeEmailChange, tx)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.First(&user, changeToken.UserID).Error; err != nil {
+			return err
+		}
+
+		// The address may have been taken since the change was requested
+		var count int64
+		if err := tx.Model(&models.User{}).Where("email = ?", changeToken.Payload).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return errEmailTaken
+		}
+
+		oldEmail = user.Email
+		user.Email = changeToken.Payload
+		user.EmailVerified = true // Proven by receiving the token
+		return tx.Model(&user).Updates(map[string]interface{}{
+			"email":          user.Email,
+			"email_verified": true,
+		}).Error
+	})
+	if errors.Is(err, auth.ErrInvalidOneTimeToken) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid or expired email change token"})
+		return
+	}
+	if errors.Is(err, errEmailTaken) {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Email already registered"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to change email"})
+		return
+	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventEmailChanged, UserID: user.ID, ActorID: user.ID, Details: "Changed from " + oldEmail})
+
+	if err := h.Mailer.Send(c.Request.Context(), emailChangedNotice(&user, oldEmail)); err != nil {
+		log.Printf("failed to send email change notice to user %d: %v", user.ID, err)
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Email changed successfully"})
+}
+
+var errEmailTaken = errors.New("email already registered")
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Email a password reset link. The response is the same whether or not the email is registered. Requests are rate limited per email address and per client.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body ForgotPasswordRequest true "Account email"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse "Too many reset links requested; see Retry-After"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// Every request counts, so the limit says nothing about whether the account exists
+	wait, err := auth.ReservePasswordReset(req.Email, c.ClientIP(), h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to check password reset requests"})
+		return
+	}
+	if wait > 0 {
+		seconds := int(math.Ceil(wait.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error: fmt.Sprintf("Too many password resets requested. Try again in %d seconds.", seconds),
+		})
+		return
+	}
+
+	// Everything that depends on the account happens after responding, so
+	// response timing doesn't reveal whether it exists
+	go h.sendPasswordReset(c.Copy(), req.Email)
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "If that email is registered, a password reset link has been sent"})
+}
+
+// sendPasswordReset emails a reset link if email belongs to an account. c is
+// a copy of the request's context, which outlives the request.
+func (h *AuthHandler) sendPasswordReset(c *gin.Context, email string) {
+	var user models.User
+	if err := h.DB.Where("email = ?", email).First(&user).Error; err != nil {
+		return
+	}
+
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventPasswordResetRequest, UserID: user.ID})
+
+	token, err := auth.IssueOneTimeToken(user.ID, auth.PurposePasswordReset, auth.PasswordResetDuration, h.DB)
+	if err != nil {
+		log.Printf("failed to issue password reset token for user %d: %v", user.ID, err)
+		return
+	}
+
+	if err := h.Mailer.Send(context.Background(), passwordResetEmail(&user, token)); err != nil {
+		log.Printf("failed to send password reset email to user %d: %v", user.ID, err)
+	}
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password using a reset token. Signs the user out of every device and lifts any login lockout. A rejected password leaves the token usable.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ValidationErrorResponse "Invalid token or password rejected by the password policy"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var userID uint
+	var policyErr *auth.PasswordPolicyError
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		resetToken, err := auth.ConsumeOneTimeToken(req.Token, auth.PurposePasswordReset, tx)
+		if err != nil {
+			return err
+		}
+		userID = resetToken.UserID
+
+		var user models.User
+		if err := tx.Select("id", "name", "email").First(&user, userID).Error; err != nil {
+			return err
+		}
+		// Rolling back on a rejected password keeps the token for another try
+		if err := auth.ValidatePassword(req.Password, user.Name, user.Email); err != nil {
+			return err
+		}
+
+		hashedPassword, err := auth.HashPassword(req.Password)
+		if err != nil {
+			return err
+		}
+		return tx.Model(&user).Update("password", hashedPassword).Error
+	})
+	if errors.Is(err, auth.ErrInvalidOneTimeToken) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid or expired reset token"})
+		return
+	}
+	if errors.As(err, &policyErr) {
+		respondPasswordError(c, "password", err)
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to reset password"})
+		return
+	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventPasswordReset, UserID: userID, ActorID: userID})
+
+	// Cut off any sessions that may have been stolen
+	if err := auth.RevokeAllUserTokens(userID, h.DB); err != nil {
+		log.Printf("failed to revoke tokens after password reset for user %d: %v", userID, err)
+	}
+
+	// Proving control of the email address lifts any login lockout
+	var user models.User
+	if err := h.DB.Select("id", "email").First(&user, userID).Error; err == nil {
+		if err := auth.UnlockAccount(user.Email, h.DB); err != nil {
+			log.Printf("failed to unlock user %d after password reset: %v", userID, err)
+		}
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Password reset successfully"})
+}
+
+func (h *AuthHandler) sendVerificationEmail(c *gin.Context, user *models.User) error {
+	token, err := auth.GenerateActionToken(user.ID, user.Email, auth.PurposeEmailVerification, auth.EmailVerificationDuration)
+	if err != nil {
+		return err
+	}
+	return h.Mailer.Send(c.Request.Context(), verificationEmail(user, token))
+}
+
+// Helper to set auth cookies with CSRF protection. Along with the HttpOnly token
+// cookies, a fresh csrf_token cookie is set that the client must echo in the
+// X-CSRF-Token header; the token is also sent in that response header for
+// clients on another origin, which can't read the cookie. No cookie is set if
+// the CSRF token can't be generated, since the session would be unusable.
+func (h *AuthHandler) setAuthCookies(c *gin.Context, tokens *auth.TokenPair) error {
+	csrfToken, err := auth.NewCSRFToken()
+	if err != nil {
+		return err
+	}
+
 	secure := os.Getenv("ENV") == "production"
 
 	// Set SameSite mode for CSRF protection
@@ -300,10 +735,22 @@ func (h *AuthHandler) setAuthCookies(c *gin.Context, tokens *auth.TokenPair) {
 		secure, // Secure only in production (HTTPS)
 		true,   // HttpOnly
 	)
+
+	c.SetCookie(
+		auth.CSRFCookieName,
+		csrfToken,
+		int(auth.RefreshTokenDuration.Seconds()),
+		"/",
+		"",
+		secure, // Secure only in production (HTTPS)
+		false,  // Readable by the client, which echoes it in X-CSRF-Token
+	)
+	c.Header(auth.CSRFHeaderName, csrfToken)
+	return nil
 }
 
 // Helper to clear auth cookies with proper SameSite settings
-func (h *AuthHandler) clearAuthCookies(c *gin.Context) {
+func clearAuthCookies(c *gin.Context) {
 	secure := os.Getenv("ENV") == "production"
 
 	if secure {
@@ -314,4 +761,5 @@ func (h *AuthHandler) clearAuthCookies(c *gin.Context) {
 
 	c.SetCookie("access_token", "", -1, "/", "", secure, true)
 	c.SetCookie("refresh_token", "", -1, "/", "", secure, true)
+	c.SetCookie(auth.CSRFCookieName, "", -1, "/", "", secure, false)
 }
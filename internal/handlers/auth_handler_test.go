@@ -0,0 +1,237 @@
+// internal/handlers/auth_handler_test.go
+package handlers_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/handlers"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+// refreshWithCookies posts to /auth/refresh with the given cookies and headers
+func refreshWithCookies(t *testing.T, api *testAPI, cookies []*http.Cookie, headers map[string]string) *http.Response {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodPost, api.URL+"/api/auth/refresh", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	return resp
+}
+
+// login signs in from a new client with email and the password register uses,
+// decoding the response into out
+func login(t *testing.T, api *testAPI, email string, out interface{}) int {
+	t.Helper()
+	return do(t, newClient(t), http.MethodPost, api.URL+"/api/auth/login", "",
+		handlers.LoginRequest{Email: email, Password: "staple-orbit-lantern-42"}, out)
+}
+
+func cookieNamed(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, cookie := range cookies {
+		if cookie.Name == name && cookie.Value != "" {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestRefreshFromCookiesChecksCSRF(t *testing.T) {
+	api := startTestAPI(t, nil)
+	client := newClient(t)
+	status := do(t, client, http.MethodPost, api.URL+"/api/auth/register", "", handlers.RegisterRequest{
+		Name:     "Test User",
+		Email:    uniqueEmail("refresh"),
+		Password: "staple-orbit-lantern-42",
+	}, nil)
+	if status != http.StatusCreated {
+		t.Fatalf("register returned %d", status)
+	}
+	apiURL, _ := url.Parse(api.URL)
+	cookies := client.Jar.Cookies(apiURL)
+	refreshCookie := cookieNamed(cookies, "refresh_token")
+	csrfCookie := cookieNamed(cookies, auth.CSRFCookieName)
+	if refreshCookie == nil || csrfCookie == nil {
+		t.Fatalf("register set cookies %v, want refresh_token and %s", cookies, auth.CSRFCookieName)
+	}
+
+	resp := refreshWithCookies(t, api, cookies, nil)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("refresh without a CSRF header returned %d, want 403", resp.StatusCode)
+	}
+	resp = refreshWithCookies(t, api, cookies, map[string]string{auth.CSRFHeaderName: "forged"})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("refresh with a wrong CSRF header returned %d, want 403", resp.StatusCode)
+	}
+	// A trusted origin doesn't stand in for the token once the session has one
+	resp = refreshWithCookies(t, api, cookies, map[string]string{"Origin": api.URL})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("refresh with a CSRF cookie but no header returned %d, want 403", resp.StatusCode)
+	}
+
+	resp = refreshWithCookies(t, api, cookies, map[string]string{auth.CSRFHeaderName: csrfCookie.Value})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("refresh with the CSRF header returned %d, want 200", resp.StatusCode)
+	}
+	refreshCookie = cookieNamed(resp.Cookies(), "refresh_token")
+	if refreshCookie == nil || cookieNamed(resp.Cookies(), auth.CSRFCookieName) == nil {
+		t.Fatalf("refresh set cookies %v, want new refresh_token and %s", resp.Cookies(), auth.CSRFCookieName)
+	}
+
+	// Sessions from before the csrf_token cookie only have the refresh token
+	legacy := []*http.Cookie{refreshCookie}
+	resp = refreshWithCookies(t, api, legacy, map[string]string{"Origin": "https://evil.example"})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("legacy refresh from another origin returned %d, want 403", resp.StatusCode)
+	}
+	resp = refreshWithCookies(t, api, legacy, nil)
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("legacy refresh without an origin returned %d, want 403", resp.StatusCode)
+	}
+	resp = refreshWithCookies(t, api, legacy, map[string]string{"Origin": api.URL})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("legacy refresh from the API's origin returned %d, want 200", resp.StatusCode)
+	}
+	if cookieNamed(resp.Cookies(), auth.CSRFCookieName) == nil {
+		t.Fatalf("legacy refresh set cookies %v, want %s", resp.Cookies(), auth.CSRFCookieName)
+	}
+}
+
+func TestForgotAndResetPassword(t *testing.T) {
+	api := startTestAPI(t, nil)
+	email := uniqueEmail("reset")
+	registered := register(t, api, email)
+	client := newClient(t)
+
+	status := do(t, client, http.MethodPost, api.URL+"/api/auth/forgot-password", "", handlers.ForgotPasswordRequest{Email: email}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("requesting a reset returned %d, want 200", status)
+	}
+	token := emailedToken(t, api, "/reset-password", email, 1)
+
+	// A rejected password leaves the token usable
+	resetURL := api.URL + "/api/auth/reset-password"
+	status = do(t, client, http.MethodPost, resetURL, "", handlers.ResetPasswordRequest{Token: token, Password: "short"}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("resetting to a weak password returned %d, want 400", status)
+	}
+	status = do(t, client, http.MethodPost, resetURL, "", handlers.ResetPasswordRequest{Token: token, Password: "violet-harbor-compass-17"}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("resetting returned %d, want 200", status)
+	}
+	status = do(t, client, http.MethodPost, resetURL, "", handlers.ResetPasswordRequest{Token: token, Password: "violet-harbor-compass-18"}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("reusing the reset token returned %d, want 400", status)
+	}
+
+	// Every session ends and only the new password works
+	if status := do(t, client, http.MethodGet, api.URL+"/api/users/me", registered.AccessToken, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("a session from before the reset got %d, want 401", status)
+	}
+	if status := login(t, api, email, nil); status != http.StatusUnauthorized {
+		t.Errorf("signing in with the old password returned %d, want 401", status)
+	}
+	status = do(t, client, http.MethodPost, api.URL+"/api/auth/login", "", handlers.LoginRequest{Email: email, Password: "violet-harbor-compass-17"}, nil)
+	if status != http.StatusOK {
+		t.Errorf("signing in with the new password returned %d, want 200", status)
+	}
+}
+
+func TestForgotPasswordIsRateLimited(t *testing.T) {
+	api := startTestAPI(t, nil)
+	email := uniqueEmail("forgot")
+	register(t, api, email)
+	client := newClient(t)
+	forgotURL := api.URL + "/api/auth/forgot-password"
+
+	// Unknown addresses get the same answer and the same limit
+	for _, address := range []string{email, uniqueEmail("unknown")} {
+		var response handlers.MessageResponse
+		status := do(t, client, http.MethodPost, forgotURL, "", handlers.ForgotPasswordRequest{Email: address}, &response)
+		if status != http.StatusOK {
+			t.Fatalf("requesting a reset for %s returned %d, want 200", address, status)
+		}
+		if response.Message != "If that email is registered, a password reset link has been sent" {
+			t.Errorf("requesting a reset for %s returned %q", address, response.Message)
+		}
+		if status := do(t, client, http.MethodPost, forgotURL, "", handlers.ForgotPasswordRequest{Email: address}, nil); status != http.StatusTooManyRequests {
+			t.Errorf("requesting another reset for %s returned %d, want 429", address, status)
+		}
+	}
+
+	// One client can't work through many addresses either
+	for i := 0; i < auth.PasswordResetIPPolicy.LockoutAfter-2; i++ {
+		if status := do(t, client, http.MethodPost, forgotURL, "", handlers.ForgotPasswordRequest{Email: uniqueEmail("spray")}, nil); status != http.StatusOK {
+			t.Fatalf("request %d from one client returned %d, want 200", i+3, status)
+		}
+	}
+	if status := do(t, client, http.MethodPost, forgotURL, "", handlers.ForgotPasswordRequest{Email: uniqueEmail("spray")}, nil); status != http.StatusTooManyRequests {
+		t.Errorf("request past the client limit returned %d, want 429", status)
+	}
+}
+
+func TestVerifyEmail(t *testing.T) {
+	t.Setenv("REQUIRE_EMAIL_VERIFICATION", "true")
+	api := startTestAPI(t, nil)
+	email := uniqueEmail("verify")
+	registered := register(t, api, email)
+	token := registered.AccessToken
+	client := newClient(t)
+
+	if registered.User.EmailVerified {
+		t.Fatal("new user is already verified")
+	}
+	if status := do(t, client, http.MethodGet, api.URL+"/api/journals", token, nil, nil); status != http.StatusForbidden {
+		t.Errorf("unverified user got %d from the finance API, want 403", status)
+	}
+
+	status := do(t, client, http.MethodPost, api.URL+"/api/auth/resend-verification", token, nil, nil)
+	if status != http.StatusOK {
+		t.Fatalf("resending the verification email returned %d, want 200", status)
+	}
+	first := emailedToken(t, api, "/verify-email", email, 1)
+	second := emailedToken(t, api, "/verify-email", email, 2)
+
+	verifyURL := api.URL + "/api/auth/verify-email"
+	if status := do(t, client, http.MethodPost, verifyURL, "", handlers.VerifyEmailRequest{Token: "not-a-token"}, nil); status != http.StatusBadRequest {
+		t.Errorf("verifying with a bad token returned %d, want 400", status)
+	}
+	if status := do(t, client, http.MethodPost, verifyURL, "", handlers.VerifyEmailRequest{Token: second}, nil); status != http.StatusOK {
+		t.Fatalf("verifying returned %d, want 200", status)
+	}
+
+	var user models.User
+	if err := api.DB.First(&user, registered.User.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !user.EmailVerified {
+		t.Error("user not verified after verifying")
+	}
+	if status := do(t, client, http.MethodGet, api.URL+"/api/journals", token, nil, nil); status != http.StatusOK {
+		t.Errorf("verified user got %d from the finance API, want 200", status)
+	}
+	if status := do(t, client, http.MethodPost, api.URL+"/api/auth/resend-verification", token, nil, nil); status != http.StatusBadRequest {
+		t.Errorf("resending after verifying returned %d, want 400", status)
+	}
+
+	// A link only verifies the address it was sent to
+	if err := api.DB.Model(&user).Updates(map[string]interface{}{"email": uniqueEmail("moved"), "email_verified": false}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if status := do(t, client, http.MethodPost, verifyURL, "", handlers.VerifyEmailRequest{Token: first}, nil); status != http.StatusBadRequest {
+		t.Errorf("verifying a changed address with the old link returned %d, want 400", status)
+	}
+}
@@ -0,0 +1,100 @@
+// internal/handlers/emails.go
+package handlers
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/mail"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+// appURL builds a link into the web app from APP_URL (default "http://localhost:3000")
+func appURL(path string, query url.Values) string {
+	base := os.Getenv("APP_URL")
+	if base == "" {
+		base = "http://localhost:3000"
+	}
+	return strings.TrimRight(base, "/") + path + "?" + query.Encode()
+}
+
+// expiresIn describes how long a link stays valid, e.g. "15 minutes" or "24 hours"
+func expiresIn(d time.Duration) string {
+	count, unit := int(d.Minutes()), "minute"
+	if d >= time.Hour && d%time.Hour == 0 {
+		count, unit = int(d.Hours()), "hour"
+	}
+	if count != 1 {
+		unit += "s"
+	}
+	return fmt.Sprintf("%d %s", count, unit)
+}
+
+func verificationEmail(user *models.User, token string) mail.Message {
+	link := appURL("/verify-email", url.Values{"token": {token}})
+	return mail.Message{
+		To:      user.Email,
+		Subject: "Verify your Kaizen email address",
+		Body: fmt.Sprintf("Hi %s,\n\nPlease confirm your email address by opening the link below:\n\n%s\n\n"+
+			"The link expires in %s. If you did not create a Kaizen account, you can ignore this email.\n",
+			user.Name, link, expiresIn(auth.EmailVerificationDuration)),
+	}
+}
+
+func passwordResetEmail(user *models.User, token string) mail.Message {
+	link := appURL("/reset-password", url.Values{"token": {token}})
+	return mail.Message{
+		To:      user.Email,
+		Subject: "Reset your Kaizen password",
+		Body: fmt.Sprintf("Hi %s,\n\nWe received a request to reset your password. Open the link below to choose a new one:\n\n%s\n\n"+
+			"The link expires in %s and can only be used once. If you did not ask for this, you can ignore this email.\n",
+			user.Name, link, expiresIn(auth.PasswordResetDuration)),
+	}
+}
+
+func magicLinkEmail(user *models.User, token string) mail.Message {
+	link := appURL("/magic-link", url.Values{"token": {token}})
+	return mail.Message{
+		To:      user.Email,
+		Subject: "Your Kaizen sign-in link",
+		Body: fmt.Sprintf("Hi %s,\n\nOpen the link below to sign in to Kaizen:\n\n%s\n\n"+
+			"The link expires in %s, can only be used once and only works on the device where you requested it. "+
+			"If you did not ask for this, you can ignore this email.\n",
+			user.Name, link, expiresIn(auth.MagicLinkDuration)),
+	}
+}
+
+func emailChangeEmail(user *models.User, newEmail, token string) mail.Message {
+	link := appURL("/confirm-email-change", url.Values{"token": {token}})
+	return mail.Message{
+		To:      newEmail,
+		Subject: "Confirm your new Kaizen email address",
+		Body: fmt.Sprintf("Hi %s,\n\nYou asked to change the email address on your Kaizen account to %s. Confirm the change by opening the link below:\n\n%s\n\n"+
+			"The link expires in %s. Until then you keep signing in with your current address.\n",
+			user.Name, newEmail, link, expiresIn(auth.EmailChangeDuration)),
+	}
+}
+
+func emailChangedNotice(user *models.User, oldEmail string) mail.Message {
+	return mail.Message{
+		To:      oldEmail,
+		Subject: "Your Kaizen email address was changed",
+		Body: fmt.Sprintf("Hi %s,\n\nThe email address on your Kaizen account was changed from %s to %s.\n\n"+
+			"If you did not make this change, reset your password and contact support immediately.\n",
+			user.Name, oldEmail, user.Email),
+	}
+}
+
+func passwordChangedNotice(user *models.User) mail.Message {
+	return mail.Message{
+		To:      user.Email,
+		Subject: "Your Kaizen password was changed",
+		Body: fmt.Sprintf("Hi %s,\n\nThe password on your Kaizen account was just changed and your other devices were signed out.\n\n"+
+			"If you did not make this change, reset your password immediately.\n",
+			user.Name),
+	}
+}
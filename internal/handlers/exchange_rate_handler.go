@@ -0,0 +1,137 @@
+// internal/handlers/exchange_rate_handler.go
+package handlers
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jedi116/kaizen-api/internal/exchange"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+type ExchangeRateImportResponse struct {
+	Imported int `json:"imported" example:"1240"`
+}
+
+type ExchangeRateListResponse struct {
+	Rates      []models.ExchangeRate `json:"rates"`
+	TotalCount int64                 `json:"total_count"`
+	Page       int                   `json:"page"`
+	PageSize   int                   `json:"page_size"`
+}
+
+// ImportExchangeRates godoc
+// @Summary Import exchange rates
+// @Description Load exchange rates from an uploaded CSV file (columns date, base, quote, rate) or an ECB reference rate XML file such as eurofxref-hist.xml. Rates already stored for the same pair and day are replaced. Nothing is stored if any line is invalid (admin only).
+// @Tags Admin
+// @Security BearerAuth
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "CSV or ECB XML file"
+// @Success 200 {object} ExchangeRateImportResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/exchange-rates [post]
+func (h *AdminHandler) ImportExchangeRates(c *gin.Context) {
+	header, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "A rates file is required in the \"file\" field"})
+		return
+	}
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Failed to read the rates file"})
+		return
+	}
+	defer file.Close()
+
+	rates, err := exchange.Parse(file, header.Filename)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid rates file: " + err.Error()})
+		return
+	}
+
+	imported, err := exchange.Import(c.Request.Context(), h.DB, rates)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to import exchange rates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ExchangeRateImportResponse{Imported: imported})
+}
+
+// ListExchangeRates godoc
+// @Summary List exchange rates
+// @Description Get stored exchange rates, newest first (admin only)
+// @Tags Admin
+// @Security BearerAuth
+// @Produce json
+// @Param base query string false "Filter by base currency, e.g. EUR"
+// @Param quote query string false "Filter by quote currency, e.g. USD"
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} ExchangeRateListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /admin/exchange-rates [get]
+func (h *AdminHandler) ListExchangeRates(c *gin.Context) {
+	query := h.DB.Model(&models.ExchangeRate{})
+
+	if base := c.Query("base"); base != "" {
+		query = query.Where("base = ?", strings.ToUpper(base))
+	}
+	if quote := c.Query("quote"); quote != "" {
+		query = query.Where("quote = ?", strings.ToUpper(quote))
+	}
+	if startDate := c.Query("start_date"); startDate != "" {
+		if parsed, err := time.Parse("2006-01-02", startDate); err == nil {
+			query = query.Where("date >= ?", parsed)
+		}
+	}
+	if endDate := c.Query("end_date"); endDate != "" {
+		if parsed, err := time.Parse("2006-01-02", endDate); err == nil {
+			query = query.Where("date <= ?", parsed)
+		}
+	}
+
+	var totalCount int64
+	if err := query.Count(&totalCount).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to fetch exchange rates"})
+		return
+	}
+
+	// Pagination
+	page := 1
+	pageSize := 20
+	if p := c.Query("page"); p != "" {
+		if parsed, err := parseInt(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+	if ps := c.Query("page_size"); ps != "" {
+		if parsed, err := parseInt(ps); err == nil && parsed > 0 && parsed <= 100 {
+			pageSize = parsed
+		}
+	}
+
+	rates := []models.ExchangeRate{}
+	if err := query.Order("date DESC, base, quote").Offset((page - 1) * pageSize).Limit(pageSize).Find(&rates).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to fetch exchange rates"})
+		return
+	}
+
+	c.JSON(http.StatusOK, ExchangeRateListResponse{
+		Rates:      rates,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+	})
+}
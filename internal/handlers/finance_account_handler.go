@@ -0,0 +1,598 @@
+// internal/handlers/finance_account_handler.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/exchange"
+	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+type FinanceAccountHandler struct {
+	DB *gorm.DB
+}
+
+type CreateAccountRequest struct {
+	Name           string      `json:"name" binding:"required" example:"Everyday checking"`
+	Type           string      `json:"type" binding:"required,oneof=checking savings cash credit_card loan" example:"checking"`
+	Currency       string      `json:"currency" binding:"omitempty,iso4217" example:"USD"`     // Defaults to the user's base currency
+	OpeningBalance money.Money `json:"opening_balance" swaggertype:"number" example:"1250.00"` // Negative for money owed
+}
+
+type UpdateAccountRequest struct {
+	Name           string       `json:"name" example:"Everyday checking"`
+	Type           string       `json:"type" binding:"omitempty,oneof=checking savings cash credit_card loan" example:"checking"`
+	Currency       string       `json:"currency" binding:"omitempty,iso4217" example:"USD"` // Only while the account has no journal entries
+	OpeningBalance *money.Money `json:"opening_balance" swaggertype:"number" example:"1250.00"`
+	IsActive       *bool        `json:"is_active" example:"true"`
+}
+
+// FinanceAccountResponse is an account with its current balance
+type FinanceAccountResponse struct {
+	models.FinanceAccount
+	Balance money.Money `json:"balance" swaggertype:"number" example:"1830.45"`
+}
+
+// AccountBalanceResponse breaks an account's balance down, in the account's currency
+type AccountBalanceResponse struct {
+	AccountID      uint        `json:"account_id" example:"1"`
+	Currency       string      `json:"currency" example:"USD"`
+	AsOf           string      `json:"as_of,omitempty" example:"2025-01-31"` // Empty for the current balance
+	OpeningBalance money.Money `json:"opening_balance" swaggertype:"number" example:"1250.00"`
+	TotalIncome    money.Money `json:"total_income" swaggertype:"number" example:"5000.00"`
+	TotalExpense   money.Money `json:"total_expense" swaggertype:"number" example:"4419.55"`
+	TransfersIn    money.Money `json:"transfers_in" swaggertype:"number" example:"500.00"`
+	TransfersOut   money.Money `json:"transfers_out" swaggertype:"number" example:"500.00"`
+	Balance        money.Money `json:"balance" swaggertype:"number" example:"1830.45"`
+}
+
+// NetWorthResponse totals every account in the user's base currency. Accounts
+// with no exchange rate for the date have no converted balance and are left out.
+type NetWorthResponse struct {
+	BaseCurrency     string            `json:"base_currency" example:"USD"`
+	AsOf             string            `json:"as_of" example:"2025-01-31"`
+	Assets           money.Money       `json:"assets" swaggertype:"number" example:"25000.00"`      // Sum of positive balances
+	Liabilities      money.Money       `json:"liabilities" swaggertype:"number" example:"-4200.00"` // Sum of negative balances
+	NetWorth         money.Money       `json:"net_worth" swaggertype:"number" example:"20800.00"`
+	Accounts         []NetWorthAccount `json:"accounts"`
+	UnconvertedCount int               `json:"unconverted_count" example:"0"`
+}
+
+type NetWorthAccount struct {
+	AccountID        uint         `json:"account_id" example:"1"`
+	Name             string       `json:"name" example:"Everyday checking"`
+	Type             string       `json:"type" example:"checking"`
+	Currency         string       `json:"currency" example:"EUR"`
+	Balance          money.Money  `json:"balance" swaggertype:"number" example:"1200.00"`
+	ConvertedBalance *money.Money `json:"converted_balance" swaggertype:"number" example:"1236.60"` // Null when there is no exchange rate
+}
+
+// CreateAccount godoc
+// @Summary Create a finance account
+// @Description Create a checking, savings, cash, credit card or loan account
+// @Tags Finance Accounts
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body CreateAccountRequest true "Account details"
+// @Success 201 {object} FinanceAccountResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /accounts [post]
+func (h *FinanceAccountHandler) CreateAccount(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var req CreateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		var user models.User
+		if err := h.DB.Select("base_currency").First(&user, userID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create account"})
+			return
+		}
+		currency = user.BaseCurrency
+	}
+
+	account := models.FinanceAccount{
+		UserID:         userID,
+		Name:           req.Name,
+		Type:           req.Type,
+		Currency:       currency,
+		OpeningBalance: req.OpeningBalance,
+		IsActive:       true,
+	}
+
+	if err := h.DB.Create(&account).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create account"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, FinanceAccountResponse{FinanceAccount: account, Balance: account.OpeningBalance})
+}
+
+// ListAccounts godoc
+// @Summary List finance accounts
+// @Description Get all accounts for the current user with their current balances
+// @Tags Finance Accounts
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param type query string false "Filter by type (checking, savings, cash, credit_card or loan)"
+// @Param active query bool false "Filter by active status"
+// @Success 200 {array} FinanceAccountResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /accounts [get]
+func (h *FinanceAccountHandler) ListAccounts(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	query := h.DB.Where("user_id = ?", userID)
+
+	// Filter by type
+	if typeFilter := c.Query("type"); models.IsValidAccountType(typeFilter) {
+		query = query.Where("type = ?", typeFilter)
+	}
+
+	// Filter by active status
+	if activeFilter := c.Query("active"); activeFilter != "" {
+		if activeFilter == "true" {
+			query = query.Where("is_active = ?", true)
+		} else if activeFilter == "false" {
+			query = query.Where("is_active = ?", false)
+		}
+	}
+
+	var accounts []models.FinanceAccount
+	if err := query.Order("name ASC").Find(&accounts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to fetch accounts"})
+		return
+	}
+
+	totals, err := accountTotals(h.DB, userID, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to calculate balances"})
+		return
+	}
+
+	response := make([]FinanceAccountResponse, 0, len(accounts))
+	for _, account := range accounts {
+		response = append(response, FinanceAccountResponse{
+			FinanceAccount: account,
+			Balance:        totals[account.ID].balance(&account),
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetAccount godoc
+// @Summary Get a finance account
+// @Description Get a single account by ID with its current balance
+// @Tags Finance Accounts
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Account ID"
+// @Success 200 {object} FinanceAccountResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /accounts/{id} [get]
+func (h *FinanceAccountHandler) GetAccount(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var account models.FinanceAccount
+	if err := h.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Account not found"})
+		return
+	}
+
+	totals, err := accountTotals(h.DB.Where("account_id = ?", account.ID), userID, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to calculate balance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, FinanceAccountResponse{
+		FinanceAccount: account,
+		Balance:        totals[account.ID].balance(&account),
+	})
+}
+
+// GetAccountBalance godoc
+// @Summary Get an account balance
+// @Description Get an account's balance broken down into opening balance, income, expenses and transfers, currently or at the end of a given day. Before the account was opened the balance is zero.
+// @Tags Finance Accounts
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Account ID"
+// @Param as_of query string false "Balance at the end of this date (YYYY-MM-DD, default: every entry)"
+// @Success 200 {object} AccountBalanceResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /accounts/{id}/balance [get]
+func (h *FinanceAccountHandler) GetAccountBalance(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	asOf, ok := parseAsOf(c)
+	if !ok {
+		return
+	}
+
+	var account models.FinanceAccount
+	if err := h.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Account not found"})
+		return
+	}
+
+	totals, err := accountTotals(h.DB.Where("account_id = ?", account.ID), userID, asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to calculate balance"})
+		return
+	}
+	total := totals[account.ID]
+	if !total.openedBy(&account, asOf) {
+		// Nothing to show before the account existed
+		account.OpeningBalance = 0
+	}
+
+	response := AccountBalanceResponse{
+		AccountID:      account.ID,
+		Currency:       account.Currency,
+		OpeningBalance: account.OpeningBalance,
+		TotalIncome:    total.Income,
+		TotalExpense:   total.Expense,
+		TransfersIn:    total.TransfersIn,
+		TransfersOut:   total.TransfersOut,
+		Balance:        total.balance(&account),
+	}
+	if asOf != nil {
+		response.AsOf = asOf.Format("2006-01-02")
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// GetNetWorth godoc
+// @Summary Get net worth
+// @Description Total the balances of every account in the user's base currency, converting other currencies at the exchange rate on the date. Accounts opened after the date are left out.
+// @Tags Finance Accounts
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param as_of query string false "Net worth at the end of this date (YYYY-MM-DD, default: today)"
+// @Success 200 {object} NetWorthResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /accounts/net-worth [get]
+func (h *FinanceAccountHandler) GetNetWorth(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	asOf, ok := parseAsOf(c)
+	if !ok {
+		return
+	}
+	if asOf == nil {
+		date := today()
+		asOf = &date
+	}
+
+	var user models.User
+	if err := h.DB.Select("base_currency").First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to calculate net worth"})
+		return
+	}
+
+	var accounts []models.FinanceAccount
+	if err := h.DB.Where("user_id = ?", userID).Order("name ASC").Find(&accounts).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to fetch accounts"})
+		return
+	}
+
+	totals, err := accountTotals(h.DB, userID, asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to calculate balances"})
+		return
+	}
+
+	currencies := make([]string, 0, len(accounts))
+	for _, account := range accounts {
+		currencies = append(currencies, account.Currency)
+	}
+	converter, err := exchange.NewConverter(h.DB, user.BaseCurrency, currencies, *asOf, *asOf)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to calculate net worth"})
+		return
+	}
+
+	response := NetWorthResponse{
+		BaseCurrency: user.BaseCurrency,
+		AsOf:         asOf.Format("2006-01-02"),
+		Accounts:     make([]NetWorthAccount, 0, len(accounts)),
+	}
+	for _, account := range accounts {
+		total := totals[account.ID]
+		if !total.openedBy(&account, asOf) {
+			continue
+		}
+		entry := NetWorthAccount{
+			AccountID: account.ID,
+			Name:      account.Name,
+			Type:      account.Type,
+			Currency:  account.Currency,
+			Balance:   total.balance(&account),
+		}
+
+		if converted, ok := converter.Convert(entry.Balance, account.Currency, *asOf); ok {
+			entry.ConvertedBalance = &converted
+			if converted >= 0 {
+				response.Assets += converted
+			} else {
+				response.Liabilities += converted
+			}
+		} else {
+			response.UnconvertedCount++
+		}
+		response.Accounts = append(response.Accounts, entry)
+	}
+	response.NetWorth = response.Assets + response.Liabilities
+
+	c.JSON(http.StatusOK, response)
+}
+
+// UpdateAccount godoc
+// @Summary Update a finance account
+// @Description Update an existing account. The currency can only be changed while the account has no journal entries.
+// @Tags Finance Accounts
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Account ID"
+// @Param request body UpdateAccountRequest true "Account data"
+// @Success 200 {object} FinanceAccountResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /accounts/{id} [put]
+func (h *FinanceAccountHandler) UpdateAccount(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var account models.FinanceAccount
+	if err := h.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Account not found"})
+		return
+	}
+
+	var req UpdateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// Journals are recorded in the account's currency, so it is fixed once there are any
+	if req.Currency != "" && req.Currency != account.Currency {
+		var journalCount int64
+		h.DB.Model(&models.FinanceJournal{}).Where("account_id = ?", account.ID).Count(&journalCount)
+		if journalCount > 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Cannot change the currency of an account with journal entries"})
+			return
+		}
+		account.Currency = req.Currency
+	}
+
+	// Update other fields if provided
+	if req.Name != "" {
+		account.Name = req.Name
+	}
+	if req.Type != "" {
+		account.Type = req.Type
+	}
+	if req.OpeningBalance != nil {
+		account.OpeningBalance = *req.OpeningBalance
+	}
+	if req.IsActive != nil {
+		account.IsActive = *req.IsActive
+	}
+
+	if err := h.DB.Save(&account).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update account"})
+		return
+	}
+
+	totals, err := accountTotals(h.DB.Where("account_id = ?", account.ID), userID, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to calculate balance"})
+		return
+	}
+
+	c.JSON(http.StatusOK, FinanceAccountResponse{
+		FinanceAccount: account,
+		Balance:        totals[account.ID].balance(&account),
+	})
+}
+
+// DeleteAccount godoc
+// @Summary Delete a finance account
+// @Description Delete an account (soft delete). Accounts with journal entries can only be deactivated.
+// @Tags Finance Accounts
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Account ID"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /accounts/{id} [delete]
+func (h *FinanceAccountHandler) DeleteAccount(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var account models.FinanceAccount
+	if err := h.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Account not found"})
+		return
+	}
+
+	// Check if account has journals
+	var journalCount int64
+	h.DB.Model(&models.FinanceJournal{}).Where("account_id = ?", account.ID).Count(&journalCount)
+	if journalCount > 0 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Cannot delete account with existing journal entries. Delete the entries first or deactivate the account."})
+		return
+	}
+
+	if err := h.DB.Delete(&account).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete account"})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Account deleted successfully"})
+}
+
+// accountTotal is the income, expenses and transfers recorded against one account
+type accountTotal struct {
+	AccountID    uint
+	Income       money.Money
+	Expense      money.Money
+	TransfersIn  money.Money
+	TransfersOut money.Money
+}
+
+func (t accountTotal) balance(account *models.FinanceAccount) money.Money {
+	return account.OpeningBalance + t.Income - t.Expense + t.TransfersIn - t.TransfersOut
+}
+
+// openedBy reports whether the account existed at the end of asOf, so its
+// opening balance counts. An account with journals dated by then did, even if
+// it was created later, as the accounts made for older journals were.
+func (t accountTotal) openedBy(account *models.FinanceAccount, asOf *time.Time) bool {
+	if asOf == nil || t.AccountID != 0 {
+		return true
+	}
+	return account.CreatedAt.Before(asOf.AddDate(0, 0, 1))
+}
+
+// mainAccount finds the user's main account in currency, creating it the first
+// time an entry is recorded without an account. It is named like the accounts
+// made for the journals from before there were accounts.
+func mainAccount(db *gorm.DB, userID uint, currency string) (*models.FinanceAccount, error) {
+	name := "Main account (" + currency + ")"
+
+	var account models.FinanceAccount
+	err := db.Where("user_id = ? AND name = ? AND currency = ?", userID, name, currency).Order("id ASC").First(&account).Error
+	if err == nil {
+		return &account, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	account = models.FinanceAccount{
+		UserID:   userID,
+		Name:     name,
+		Type:     models.AccountTypeChecking,
+		Currency: currency,
+		IsActive: true,
+	}
+	if err := db.Create(&account).Error; err != nil {
+		return nil, err
+	}
+	return &account, nil
+}
+
+// accountTotals sums the journals of a user's accounts by account, up to and
+// including asOf if it is set. query may narrow the journals further.
+func accountTotals(query *gorm.DB, userID uint, asOf *time.Time) (map[uint]accountTotal, error) {
+	query = query.Model(&models.FinanceJournal{}).Where("user_id = ?", userID)
+	if asOf != nil {
+		query = query.Where("date <= ?", *asOf)
+	}
+
+	var rows []accountTotal
+	err := query.Select("account_id, " +
+		"COALESCE(SUM(CASE WHEN type = 'income' THEN amount ELSE 0 END), 0) AS income, " +
+		"COALESCE(SUM(CASE WHEN type = 'expense' THEN amount ELSE 0 END), 0) AS expense, " +
+		"COALESCE(SUM(CASE WHEN type = 'transfer_in' THEN amount ELSE 0 END), 0) AS transfers_in, " +
+		"COALESCE(SUM(CASE WHEN type = 'transfer_out' THEN amount ELSE 0 END), 0) AS transfers_out").
+		Group("account_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[uint]accountTotal, len(rows))
+	for _, row := range rows {
+		totals[row.AccountID] = row
+	}
+	return totals, nil
+}
+
+// parseAsOf reads the optional as_of date, responding with 400 if it is invalid
+func parseAsOf(c *gin.Context) (*time.Time, bool) {
+	value := c.Query("as_of")
+	if value == "" {
+		return nil, true
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid as_of date format. Use YYYY-MM-DD"})
+		return nil, false
+	}
+	return &parsed, true
+}
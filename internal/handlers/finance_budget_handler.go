@@ -0,0 +1,632 @@
+// internal/handlers/finance_budget_handler.go
+package handlers
+
+import (
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/exchange"
+	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+type FinanceBudgetHandler struct {
+	DB *gorm.DB
+}
+
+type CreateBudgetRequest struct {
+	CategoryID uint        `json:"category_id" binding:"required" example:"3"`
+	Amount     money.Money `json:"amount" binding:"required,gt=0" swaggertype:"number" example:"400.00"`
+	Currency   string      `json:"currency" binding:"omitempty,iso4217" example:"USD"` // Defaults to the user's base currency
+	Month      string      `json:"month" example:"2025-01"`                            // First month (YYYY-MM), defaults to the current month
+	Repeat     bool        `json:"repeat" example:"true"`                              // Repeat every month from Month instead of covering Month only
+	EndMonth   string      `json:"end_month" example:"2025-12"`                        // Last month of a repeating budget (YYYY-MM), open-ended if empty
+	Rollover   bool        `json:"rollover" example:"false"`
+}
+
+type UpdateBudgetRequest struct {
+	Amount   *money.Money `json:"amount" swaggertype:"number" example:"400.00"`
+	Currency string       `json:"currency" binding:"omitempty,iso4217" example:"USD"` // Must match the budget's currency
+	Repeat   *bool        `json:"repeat" example:"true"`
+	EndMonth string       `json:"end_month" example:"2025-12"` // Last month of a repeating budget (YYYY-MM)
+	Rollover *bool        `json:"rollover" example:"false"`
+}
+
+// BudgetProgress is how a category's spending compares to its budget in one
+// month. Amounts are in the budget's currency; spending in other currencies is
+// converted at the rate on its date.
+type BudgetProgress struct {
+	BudgetID         uint        `json:"budget_id" example:"1"`
+	CategoryID       uint        `json:"category_id" example:"3"`
+	CategoryName     string      `json:"category_name" example:"Groceries"`
+	Currency         string      `json:"currency" example:"USD"`
+	Limit            money.Money `json:"limit" swaggertype:"number" example:"400.00"`
+	Rollover         money.Money `json:"rollover" swaggertype:"number" example:"35.20"` // Carried from the previous month, negative if it was overspent
+	Available        money.Money `json:"available" swaggertype:"number" example:"435.20"`
+	Spent            money.Money `json:"spent" swaggertype:"number" example:"212.75"`
+	Remaining        money.Money `json:"remaining" swaggertype:"number" example:"222.45"` // Negative when overspent
+	PercentUsed      *float64    `json:"percent_used" example:"48.9"`                     // Null when nothing is available
+	ProjectedSpend   money.Money `json:"projected_spend" swaggertype:"number" example:"425.50"`
+	UnconvertedCount int64       `json:"unconverted_count" example:"0"` // Entries left out for lack of an exchange rate
+}
+
+type BudgetProgressResponse struct {
+	Month     string           `json:"month" example:"2025-01"`
+	StartDate string           `json:"start_date" example:"2025-01-01"`
+	EndDate   string           `json:"end_date" example:"2025-01-31"`
+	Budgets   []BudgetProgress `json:"budgets"`
+}
+
+// CreateBudget godoc
+// @Summary Create a budget
+// @Description Set a monthly spending limit for an expense category, for one month or repeating every month. A budget that starts later overrides an earlier one for the months both cover, so a one-off budget can adjust a single month of a repeating one.
+// @Tags Finance Budgets
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body CreateBudgetRequest true "Budget details"
+// @Success 201 {object} models.FinanceBudget
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /budgets [post]
+func (h *FinanceBudgetHandler) CreateBudget(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var req CreateBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// Verify category belongs to user and is for spending
+	var category models.FinanceCategory
+	if err := h.DB.Where("id = ? AND user_id = ?", req.CategoryID, userID).First(&category).Error; err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Category not found or doesn't belong to you"})
+		return
+	}
+	if category.Type != models.JournalTypeExpense {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Budgets can only be set for expense categories"})
+		return
+	}
+
+	startMonth := currentMonth()
+	if req.Month != "" {
+		parsed, err := parseMonth(req.Month)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid month format. Use YYYY-MM"})
+			return
+		}
+		startMonth = parsed
+	}
+
+	endMonth := &startMonth
+	if req.Repeat {
+		endMonth = nil
+	}
+	if req.EndMonth != "" {
+		if !req.Repeat {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "end_month can only be set for a repeating budget"})
+			return
+		}
+		parsed, err := parseMonth(req.EndMonth)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid end_month format. Use YYYY-MM"})
+			return
+		}
+		if parsed.Before(startMonth) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "end_month cannot be before month"})
+			return
+		}
+		endMonth = &parsed
+	}
+
+	currency := req.Currency
+	if currency == "" {
+		var user models.User
+		if err := h.DB.Select("base_currency").First(&user, userID).Error; err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create budget"})
+			return
+		}
+		currency = user.BaseCurrency
+	}
+
+	budget := models.FinanceBudget{
+		UserID:     userID,
+		CategoryID: category.ID,
+		Amount:     req.Amount,
+		Currency:   currency,
+		StartMonth: startMonth,
+		EndMonth:   endMonth,
+		Rollover:   req.Rollover,
+	}
+
+	if err := h.DB.Create(&budget).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create budget"})
+		return
+	}
+	budget.Category = &category
+
+	c.JSON(http.StatusCreated, budget)
+}
+
+// ListBudgets godoc
+// @Summary List budgets
+// @Description Get the user's budgets, optionally only those for a category or covering a month
+// @Tags Finance Budgets
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param category_id query int false "Filter by category ID"
+// @Param month query string false "Only budgets covering this month (YYYY-MM)"
+// @Success 200 {array} models.FinanceBudget
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /budgets [get]
+func (h *FinanceBudgetHandler) ListBudgets(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	query := h.DB.Where("user_id = ?", userID)
+
+	// Filter by category
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		query = query.Where("category_id = ?", categoryID)
+	}
+
+	// Filter by month covered
+	if month := c.Query("month"); month != "" {
+		parsed, err := parseMonth(month)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid month format. Use YYYY-MM"})
+			return
+		}
+		query = query.Where("start_month <= ? AND (end_month IS NULL OR end_month >= ?)", parsed, parsed)
+	}
+
+	budgets := []models.FinanceBudget{}
+	if err := query.Preload("Category").Order("category_id, start_month, id").Find(&budgets).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to fetch budgets"})
+		return
+	}
+
+	c.JSON(http.StatusOK, budgets)
+}
+
+// GetBudgetProgress godoc
+// @Summary Get budget progress
+// @Description For every category with a budget in the month: the limit, any amount rolled over from the previous month, spending to date, what remains, the percentage used and the spending projected by the end of the month at the current pace. For a future month the rollover is what the current month would carry if nothing more were spent.
+// @Tags Finance Budgets
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param month query string false "Month (YYYY-MM, default: current month)"
+// @Success 200 {object} BudgetProgressResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /budgets/progress [get]
+func (h *FinanceBudgetHandler) GetBudgetProgress(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	month := currentMonth()
+	if value := c.Query("month"); value != "" {
+		parsed, err := parseMonth(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid month format. Use YYYY-MM"})
+			return
+		}
+		month = parsed
+	}
+
+	progress, err := budgetProgress(h.DB, userID, month, today())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to calculate budget progress"})
+		return
+	}
+
+	c.JSON(http.StatusOK, BudgetProgressResponse{
+		Month:     month.Format("2006-01"),
+		StartDate: month.Format("2006-01-02"),
+		EndDate:   endOfMonth(month).Format("2006-01-02"),
+		Budgets:   progress,
+	})
+}
+
+// GetBudget godoc
+// @Summary Get a budget
+// @Description Get a single budget by ID
+// @Tags Finance Budgets
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Success 200 {object} models.FinanceBudget
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /budgets/{id} [get]
+func (h *FinanceBudgetHandler) GetBudget(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var budget models.FinanceBudget
+	if err := h.DB.Preload("Category").Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&budget).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Budget not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, budget)
+}
+
+// UpdateBudget godoc
+// @Summary Update a budget
+// @Description Update an existing budget. Changes apply to every month it covers; to change a repeating budget from a given month on, set its end_month and create a new budget from the following month. The currency can't be changed.
+// @Tags Finance Budgets
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Param request body UpdateBudgetRequest true "Budget data"
+// @Success 200 {object} models.FinanceBudget
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /budgets/{id} [put]
+func (h *FinanceBudgetHandler) UpdateBudget(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var budget models.FinanceBudget
+	if err := h.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&budget).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Budget not found"})
+		return
+	}
+
+	var req UpdateBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if req.Amount != nil {
+		if *req.Amount <= 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Amount must be greater than 0"})
+			return
+		}
+		budget.Amount = *req.Amount
+	}
+
+	if req.Repeat != nil {
+		if *req.Repeat {
+			budget.EndMonth = nil
+		} else {
+			startMonth := budget.StartMonth
+			budget.EndMonth = &startMonth
+		}
+	}
+	if req.EndMonth != "" {
+		if req.Repeat != nil && !*req.Repeat {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "end_month can only be set for a repeating budget"})
+			return
+		}
+		parsed, err := parseMonth(req.EndMonth)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid end_month format. Use YYYY-MM"})
+			return
+		}
+		if parsed.Before(budget.StartMonth) {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "end_month cannot be before the budget's first month"})
+			return
+		}
+		budget.EndMonth = &parsed
+	}
+
+	// Earlier months would roll over in the old currency
+	if req.Currency != "" && req.Currency != budget.Currency {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "A budget's currency can't be changed. Set its end_month and create a new budget from the following month."})
+		return
+	}
+
+	// Update other fields if provided
+	if req.Rollover != nil {
+		budget.Rollover = *req.Rollover
+	}
+
+	if err := h.DB.Save(&budget).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update budget"})
+		return
+	}
+
+	// Load category for response
+	h.DB.Preload("Category").First(&budget, budget.ID)
+
+	c.JSON(http.StatusOK, budget)
+}
+
+// DeleteBudget godoc
+// @Summary Delete a budget
+// @Description Delete a budget (soft delete). Months it covered fall back to any earlier budget for the category.
+// @Tags Finance Budgets
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Budget ID"
+// @Success 200 {object} MessageResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /budgets/{id} [delete]
+func (h *FinanceBudgetHandler) DeleteBudget(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var budget models.FinanceBudget
+	if err := h.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&budget).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Budget not found"})
+		return
+	}
+
+	if err := h.DB.Delete(&budget).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete budget"})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Budget deleted successfully"})
+}
+
+// categorySpend is the sum of one day's expenses in one category and currency
+type categorySpend struct {
+	CategoryID uint
+	Currency   string
+	Date       time.Time
+	Amount     money.Money
+	Entries    int64
+}
+
+// monthSpend is a category's spending in one month, in its budget's currency
+type monthSpend struct {
+	amount      money.Money
+	unconverted int64
+}
+
+// budgetSchedule holds a user's budgets by category, in the order they started
+type budgetSchedule map[uint][]*models.FinanceBudget
+
+// budgetFor returns the budget that applies to the category in the month, if any
+func (s budgetSchedule) budgetFor(categoryID uint, month time.Time) *models.FinanceBudget {
+	budgets := s[categoryID]
+	for i := len(budgets) - 1; i >= 0; i-- {
+		if budgets[i].Covers(month) {
+			return budgets[i]
+		}
+	}
+	return nil
+}
+
+// rolloverStart returns the first month whose remainder rolls into month: it
+// walks back while each month's budget rolls over from a previous month that
+// also had a budget in the same currency
+func (s budgetSchedule) rolloverStart(categoryID uint, month time.Time) time.Time {
+	for {
+		budget := s.budgetFor(categoryID, month)
+		if !budget.Rollover {
+			return month
+		}
+		previous := s.budgetFor(categoryID, month.AddDate(0, -1, 0))
+		if previous == nil || previous.Currency != budget.Currency {
+			return month
+		}
+		month = month.AddDate(0, -1, 0)
+	}
+}
+
+// budgetProgress compares spending to budget for every category with a budget
+// in month. Spending is counted up to today; months before it are replayed, up
+// to the current one, to work out what rolls over into it.
+func budgetProgress(db *gorm.DB, userID uint, month, today time.Time) ([]BudgetProgress, error) {
+	var budgets []models.FinanceBudget
+	if err := db.Where("user_id = ? AND start_month <= ?", userID, month).
+		Preload("Category").
+		Order("start_month, id").
+		Find(&budgets).Error; err != nil {
+		return nil, err
+	}
+
+	schedule := budgetSchedule{}
+	for i := range budgets {
+		schedule[budgets[i].CategoryID] = append(schedule[budgets[i].CategoryID], &budgets[i])
+	}
+
+	var categoryIDs []uint
+	starts := map[uint]time.Time{}
+	first := month
+	for categoryID := range schedule {
+		if schedule.budgetFor(categoryID, month) == nil {
+			continue
+		}
+		categoryIDs = append(categoryIDs, categoryID)
+		starts[categoryID] = schedule.rolloverStart(categoryID, month)
+		if starts[categoryID].Before(first) {
+			first = starts[categoryID]
+		}
+	}
+	if len(categoryIDs) == 0 {
+		return []BudgetProgress{}, nil
+	}
+
+	end := endOfMonth(month)
+	if today.Before(end) {
+		end = today
+	}
+
+	var totals []categorySpend
+	if !end.Before(first) {
+		if err := db.Model(&models.FinanceJournal{}).
+			Where("user_id = ? AND type = ? AND category_id IN ? AND date >= ? AND date <= ?",
+				userID, models.JournalTypeExpense, categoryIDs, first, end).
+			Select("category_id, currency, date, SUM(amount) AS amount, COUNT(*) AS entries").
+			Group("category_id, currency, date").
+			Scan(&totals).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	var currencies []string
+	for _, total := range totals {
+		currencies = append(currencies, total.Currency)
+	}
+	converters := map[string]*exchange.Converter{}
+
+	spent := map[uint]map[time.Time]*monthSpend{}
+	for _, total := range totals {
+		totalMonth := time.Date(total.Date.Year(), total.Date.Month(), 1, 0, 0, 0, 0, time.UTC)
+		if totalMonth.Before(starts[total.CategoryID]) {
+			continue
+		}
+		budget := schedule.budgetFor(total.CategoryID, totalMonth)
+		if budget == nil {
+			continue
+		}
+
+		converter := converters[budget.Currency]
+		if converter == nil {
+			var err error
+			if converter, err = exchange.NewConverter(db, budget.Currency, currencies, first, end); err != nil {
+				return nil, err
+			}
+			converters[budget.Currency] = converter
+		}
+
+		if spent[total.CategoryID] == nil {
+			spent[total.CategoryID] = map[time.Time]*monthSpend{}
+		}
+		entry := spent[total.CategoryID][totalMonth]
+		if entry == nil {
+			entry = &monthSpend{}
+			spent[total.CategoryID][totalMonth] = entry
+		}
+		if converted, ok := converter.Convert(total.Amount, total.Currency, total.Date); ok {
+			entry.amount += converted
+		} else {
+			entry.unconverted += total.Entries
+		}
+	}
+
+	// Months after the current one haven't happened, so nothing of theirs rolls over
+	thisMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
+
+	progress := make([]BudgetProgress, 0, len(categoryIDs))
+	for _, categoryID := range categoryIDs {
+		// Replay the months that roll into this one
+		var rollover money.Money
+		for m := starts[categoryID]; m.Before(month) && !m.After(thisMonth); m = m.AddDate(0, 1, 0) {
+			var monthSpent money.Money
+			if entry := spent[categoryID][m]; entry != nil {
+				monthSpent = entry.amount
+			}
+			rollover += schedule.budgetFor(categoryID, m).Amount - monthSpent
+		}
+
+		budget := schedule.budgetFor(categoryID, month)
+		item := BudgetProgress{
+			BudgetID:   budget.ID,
+			CategoryID: categoryID,
+			Currency:   budget.Currency,
+			Limit:      budget.Amount,
+			Rollover:   rollover,
+			Available:  budget.Amount + rollover,
+		}
+		if budget.Category != nil {
+			item.CategoryName = budget.Category.Name
+		}
+		if entry := spent[categoryID][month]; entry != nil {
+			item.Spent = entry.amount
+			item.UnconvertedCount = entry.unconverted
+		}
+		item.Remaining = item.Available - item.Spent
+		if item.Available > 0 {
+			percent := math.Round(float64(item.Spent)/float64(item.Available)*1000) / 10
+			item.PercentUsed = &percent
+		}
+		item.ProjectedSpend = projectedSpend(item.Spent, month, today)
+
+		progress = append(progress, item)
+	}
+
+	sort.Slice(progress, func(i, j int) bool {
+		if progress[i].CategoryName != progress[j].CategoryName {
+			return progress[i].CategoryName < progress[j].CategoryName
+		}
+		return progress[i].CategoryID < progress[j].CategoryID
+	})
+	return progress, nil
+}
+
+// projectedSpend extrapolates a month's spending so far to the whole month at
+// the same daily pace. Past and future months are returned unchanged.
+func projectedSpend(spent money.Money, month, today time.Time) money.Money {
+	end := endOfMonth(month)
+	if today.Before(month) || today.After(end) {
+		return spent
+	}
+	days, elapsed := int64(end.Day()), int64(today.Day())
+	return money.Money((int64(spent)*days + elapsed/2) / elapsed)
+}
+
+// parseMonth parses a YYYY-MM month into its first day
+func parseMonth(value string) (time.Time, error) {
+	return time.Parse("2006-01", value)
+}
+
+// endOfMonth returns the last day of the month starting on month
+func endOfMonth(month time.Time) time.Time {
+	return month.AddDate(0, 1, -1)
+}
+
+// today returns the current UTC date at midnight
+func today() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// currentMonth returns the first day of the current UTC month
+func currentMonth() time.Time {
+	now := time.Now().UTC()
+	return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,123 @@
+// internal/handlers/finance_budget_handler_test.go
+package handlers_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jedi116/kaizen-api/internal/handlers"
+	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+func budgetProgress(t *testing.T, api *testAPI, token string, month time.Time) handlers.BudgetProgress {
+	t.Helper()
+	var response handlers.BudgetProgressResponse
+	status := do(t, newClient(t), http.MethodGet, api.URL+"/api/budgets/progress?month="+month.Format("2006-01"), token, nil, &response)
+	if status != http.StatusOK {
+		t.Fatalf("budget progress returned %d, want 200", status)
+	}
+	if len(response.Budgets) != 1 {
+		t.Fatalf("budget progress for %s has %d budgets, want 1", response.Month, len(response.Budgets))
+	}
+	return response.Budgets[0]
+}
+
+func TestBudgetRollover(t *testing.T) {
+	api := startTestAPI(t, nil)
+	token := register(t, api, uniqueEmail("budget"))
+	category := createCategory(t, api, token.AccessToken, models.JournalTypeExpense)
+	client := newClient(t)
+
+	now := time.Now().UTC()
+	thisMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	twoMonthsAgo := thisMonth.AddDate(0, -2, 0)
+
+	status := do(t, client, http.MethodPost, api.URL+"/api/budgets", token.AccessToken, handlers.CreateBudgetRequest{
+		CategoryID: category.ID,
+		Amount:     money.Money(10000),
+		Month:      twoMonthsAgo.Format("2006-01"),
+		Repeat:     true,
+		Rollover:   true,
+	}, nil)
+	if status != http.StatusCreated {
+		t.Fatalf("creating a budget returned %d, want 201", status)
+	}
+
+	spend := func(amount money.Money, date time.Time) {
+		t.Helper()
+		status := do(t, client, http.MethodPost, api.URL+"/api/journals", token.AccessToken, handlers.CreateJournalRequest{
+			CategoryID: category.ID,
+			Amount:     amount,
+			Title:      "Spending",
+			Date:       date.Format("2006-01-02"),
+		}, nil)
+		if status != http.StatusCreated {
+			t.Fatalf("creating an entry returned %d, want 201", status)
+		}
+	}
+	spend(money.Money(3000), twoMonthsAgo)
+	spend(money.Money(12000), thisMonth.AddDate(0, -1, 0))
+
+	tests := []struct {
+		name     string
+		month    time.Time
+		rollover money.Money
+	}{
+		{"first month", twoMonthsAgo, 0},
+		{"after underspending", thisMonth.AddDate(0, -1, 0), money.Money(7000)},
+		{"after overspending", thisMonth, money.Money(5000)},
+		// Only months up to the current one are replayed
+		{"next month", thisMonth.AddDate(0, 1, 0), money.Money(15000)},
+		{"far future", thisMonth.AddDate(0, 6, 0), money.Money(15000)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			progress := budgetProgress(t, api, token.AccessToken, tt.month)
+			if progress.Rollover != tt.rollover {
+				t.Errorf("rollover = %s, want %s", progress.Rollover, tt.rollover)
+			}
+			if progress.Available != money.Money(10000)+tt.rollover {
+				t.Errorf("available = %s, want %s", progress.Available, money.Money(10000)+tt.rollover)
+			}
+		})
+	}
+
+	// A past month's projection is what was spent
+	if progress := budgetProgress(t, api, token.AccessToken, thisMonth.AddDate(0, -1, 0)); progress.ProjectedSpend != money.Money(12000) {
+		t.Errorf("projected spend of last month = %s, want 120.00", progress.ProjectedSpend)
+	}
+}
+
+func TestBudgetCurrencyCannotChange(t *testing.T) {
+	api := startTestAPI(t, nil)
+	token := register(t, api, uniqueEmail("budget")).AccessToken
+	category := createCategory(t, api, token, models.JournalTypeExpense)
+	client := newClient(t)
+
+	var budget models.FinanceBudget
+	status := do(t, client, http.MethodPost, api.URL+"/api/budgets", token, handlers.CreateBudgetRequest{
+		CategoryID: category.ID,
+		Amount:     money.Money(10000),
+		Currency:   "USD",
+		Repeat:     true,
+		Rollover:   true,
+	}, &budget)
+	if status != http.StatusCreated {
+		t.Fatalf("creating a budget returned %d, want 201", status)
+	}
+
+	budgetURL := api.URL + "/api/budgets/" + strconv.Itoa(int(budget.ID))
+	status = do(t, client, http.MethodPut, budgetURL, token, handlers.UpdateBudgetRequest{Currency: "EUR"}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("changing the currency returned %d, want 400", status)
+	}
+
+	amount := money.Money(20000)
+	status = do(t, client, http.MethodPut, budgetURL, token, handlers.UpdateBudgetRequest{Currency: "USD", Amount: &amount}, &budget)
+	if status != http.StatusOK || budget.Currency != "USD" || budget.Amount != amount {
+		t.Errorf("updating with the same currency returned %d with %s %s, want 200 with USD 200.00", status, budget.Currency, budget.Amount)
+	}
+}
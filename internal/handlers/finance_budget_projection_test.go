@@ -0,0 +1,35 @@
+// internal/handlers/finance_budget_projection_test.go
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+func TestProjectedSpend(t *testing.T) {
+	month := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		spent money.Money
+		today time.Time
+		want  money.Money
+	}{
+		{"first day", money.Money(1000), month, money.Money(31000)},
+		{"halfway", money.Money(15000), time.Date(2026, 10, 15, 0, 0, 0, 0, time.UTC), money.Money(31000)},
+		{"rounds to the nearest cent", money.Money(1000), time.Date(2026, 10, 3, 0, 0, 0, 0, time.UTC), money.Money(10333)},
+		{"last day", money.Money(31000), time.Date(2026, 10, 31, 0, 0, 0, 0, time.UTC), money.Money(31000)},
+		{"nothing spent", 0, time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), 0},
+		{"past month", money.Money(12345), time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC), money.Money(12345)},
+		{"future month", money.Money(500), time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC), money.Money(500)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := projectedSpend(tt.spent, month, tt.today); got != tt.want {
+				t.Errorf("projectedSpend(%s) on %s = %s, want %s", tt.spent, tt.today.Format("2006-01-02"), got, tt.want)
+			}
+		})
+	}
+}
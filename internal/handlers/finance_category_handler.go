@@ -37,12 +37,14 @@ type UpdateCategoryRequest struct {
 // @Description Create a new income or expense category
 // @Tags Finance Categories
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param request body CreateCategoryRequest true "Category details"
 // @Success 201 {object} models.FinanceCategory
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /categories [post]
 func (h *FinanceCategoryHandler) CreateCategory(c *gin.Context) {
@@ -81,11 +83,13 @@ func (h *FinanceCategoryHandler) CreateCategory(c *gin.Context) {
 // @Description Get all categories for the current user with optional type filter
 // @Tags Finance Categories
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce json
 // @Param type query string false "Filter by type (income or expense)"
 // @Param active query bool false "Filter by active status"
 // @Success 200 {array} models.FinanceCategory
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /categories [get]
 func (h *FinanceCategoryHandler) ListCategories(c *gin.Context) {
@@ -127,10 +131,12 @@ func (h *FinanceCategoryHandler) ListCategories(c *gin.Context) {
 // @Description Get a single category by ID
 // @Tags Finance Categories
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce json
 // @Param id path int true "Category ID"
 // @Success 200 {object} models.FinanceCategory
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Router /categories/{id} [get]
 func (h *FinanceCategoryHandler) GetCategory(c *gin.Context) {
@@ -156,6 +162,7 @@ func (h *FinanceCategoryHandler) GetCategory(c *gin.Context) {
 // @Description Update an existing category
 // @Tags Finance Categories
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param id path int true "Category ID"
@@ -163,6 +170,7 @@ func (h *FinanceCategoryHandler) GetCategory(c *gin.Context) {
 // @Success 200 {object} models.FinanceCategory
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /categories/{id} [put]
@@ -217,13 +225,15 @@ func (h *FinanceCategoryHandler) UpdateCategory(c *gin.Context) {
 
 // DeleteCategory godoc
 // @Summary Delete a finance category
-// @Description Delete a category (soft delete)
+// @Description Delete a category and its budgets (soft delete)
 // @Tags Finance Categories
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce json
 // @Param id path int true "Category ID"
 // @Success 200 {object} MessageResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /categories/{id} [delete]
@@ -251,11 +261,17 @@ func (h *FinanceCategoryHandler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	if err := h.DB.Delete(&category).Error; err != nil {
+	// Budgets only make sense for an existing category
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("category_id = ?", category.ID).Delete(&models.FinanceBudget{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&category).Error
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete category"})
 		return
 	}
 
 	c.JSON(http.StatusOK, MessageResponse{Message: "Category deleted successfully"})
 }
-
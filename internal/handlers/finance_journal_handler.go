@@ -3,13 +3,16 @@ package handlers
 
 import (
 	"net/http"
+	"sort"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 
 	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/exchange"
 	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/money"
 )
 
 type FinanceJournalHandler struct {
@@ -17,36 +20,58 @@ type FinanceJournalHandler struct {
 }
 
 type CreateJournalRequest struct {
-	CategoryID    uint    `json:"category_id" binding:"required" example:"1"`
-	Amount        float64 `json:"amount" binding:"required,gt=0" example:"25.50"`
-	Title         string  `json:"title" binding:"required" example:"Grocery shopping"`
-	Description   string  `json:"description" example:"Weekly groceries at Walmart"`
-	Date          string  `json:"date" example:"2025-01-15"`
-	PaymentMethod string  `json:"payment_method" example:"credit_card"`
-	Location      string  `json:"location" example:"Walmart"`
-	IsRecurring   bool    `json:"is_recurring" example:"false"`
-	ReceiptURL    string  `json:"receipt_url" example:"https://example.com/receipt.jpg"`
+	CategoryID    uint        `json:"category_id" binding:"required" example:"1"`
+	AccountID     uint        `json:"account_id" example:"1"` // Defaults to the main account in the entry's currency, created if needed
+	Amount        money.Money `json:"amount" binding:"required,gt=0" swaggertype:"number" example:"25.50"`
+	Currency      string      `json:"currency" binding:"omitempty,iso4217" example:"USD"` // Must match the account's currency if given; defaults to the user's base currency without an account
+	Title         string      `json:"title" binding:"required" example:"Grocery shopping"`
+	Description   string      `json:"description" example:"Weekly groceries at Walmart"`
+	Date          string      `json:"date" example:"2025-01-15"`
+	PaymentMethod string      `json:"payment_method" example:"credit_card"`
+	Location      string      `json:"location" example:"Walmart"`
+	IsRecurring   bool        `json:"is_recurring" example:"false"`
+	ReceiptURL    string      `json:"receipt_url" example:"https://example.com/receipt.jpg"`
 }
 
 type UpdateJournalRequest struct {
-	CategoryID    *uint    `json:"category_id" example:"1"`
-	Amount        *float64 `json:"amount" example:"25.50"`
-	Title         string   `json:"title" example:"Grocery shopping"`
-	Description   string   `json:"description" example:"Weekly groceries at Walmart"`
-	Date          string   `json:"date" example:"2025-01-15"`
-	PaymentMethod string   `json:"payment_method" example:"credit_card"`
-	Location      string   `json:"location" example:"Walmart"`
-	IsRecurring   *bool    `json:"is_recurring" example:"false"`
-	ReceiptURL    string   `json:"receipt_url" example:"https://example.com/receipt.jpg"`
+	CategoryID    *uint        `json:"category_id" example:"1"`
+	AccountID     *uint        `json:"account_id" example:"1"`
+	Amount        *money.Money `json:"amount" swaggertype:"number" example:"25.50"`
+	Currency      string       `json:"currency" binding:"omitempty,iso4217" example:"USD"` // Must match the account's currency if given
+	Title         string       `json:"title" example:"Grocery shopping"`
+	Description   string       `json:"description" example:"Weekly groceries at Walmart"`
+	Date          string       `json:"date" example:"2025-01-15"`
+	PaymentMethod string       `json:"payment_method" example:"credit_card"`
+	Location      string       `json:"location" example:"Walmart"`
+	IsRecurring   *bool        `json:"is_recurring" example:"false"`
+	ReceiptURL    string       `json:"receipt_url" example:"https://example.com/receipt.jpg"`
 }
 
+// JournalSummary totals are in the user's base currency. Entries in other
+// currencies are converted at the rate on their date; Currencies breaks the
+// totals down by original currency.
 type JournalSummary struct {
-	TotalIncome  float64 `json:"total_income" example:"5000.00"`
-	TotalExpense float64 `json:"total_expense" example:"3500.00"`
-	NetBalance   float64 `json:"net_balance" example:"1500.00"`
-	StartDate    string  `json:"start_date" example:"2025-01-01"`
-	EndDate      string  `json:"end_date" example:"2025-01-31"`
-	EntryCount   int64   `json:"entry_count" example:"42"`
+	BaseCurrency string            `json:"base_currency" example:"USD"`
+	TotalIncome  money.Money       `json:"total_income" swaggertype:"number" example:"5000.00"`
+	TotalExpense money.Money       `json:"total_expense" swaggertype:"number" example:"3500.00"`
+	NetBalance   money.Money       `json:"net_balance" swaggertype:"number" example:"1500.00"`
+	Currencies   []CurrencySummary `json:"currencies"`
+	StartDate    string            `json:"start_date" example:"2025-01-01"`
+	EndDate      string            `json:"end_date" example:"2025-01-31"`
+	EntryCount   int64             `json:"entry_count" example:"42"`
+}
+
+// CurrencySummary totals the entries in one currency, as entered and converted
+// to the base currency. Entries without an exchange rate for their date are
+// counted in UnconvertedCount and left out of the converted totals.
+type CurrencySummary struct {
+	Currency         string      `json:"currency" example:"EUR"`
+	TotalIncome      money.Money `json:"total_income" swaggertype:"number" example:"1200.00"`
+	TotalExpense     money.Money `json:"total_expense" swaggertype:"number" example:"800.00"`
+	ConvertedIncome  money.Money `json:"converted_income" swaggertype:"number" example:"1236.60"`
+	ConvertedExpense money.Money `json:"converted_expense" swaggertype:"number" example:"824.40"`
+	EntryCount       int64       `json:"entry_count" example:"12"`
+	UnconvertedCount int64       `json:"unconverted_count" example:"0"`
 }
 
 type JournalListResponse struct {
@@ -58,15 +83,17 @@ type JournalListResponse struct {
 
 // CreateJournal godoc
 // @Summary Create a journal entry
-// @Description Create a new income or expense entry
+// @Description Create a new income or expense entry. Without an account it goes to the main account in its currency (the base currency if not given), which is created the first time. Closed accounts don't accept new entries.
 // @Tags Finance Journals
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param request body CreateJournalRequest true "Journal entry details"
 // @Success 201 {object} models.FinanceJournal
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /journals [post]
 func (h *FinanceJournalHandler) CreateJournal(c *gin.Context) {
@@ -89,6 +116,37 @@ func (h *FinanceJournalHandler) CreateJournal(c *gin.Context) {
 		return
 	}
 
+	// Verify account belongs to user; the entry is in the account's currency
+	var account models.FinanceAccount
+	if req.AccountID == 0 {
+		currency := req.Currency
+		if currency == "" {
+			var user models.User
+			if err := h.DB.Select("base_currency").First(&user, userID).Error; err != nil {
+				c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create journal entry"})
+				return
+			}
+			currency = user.BaseCurrency
+		}
+		found, err := mainAccount(h.DB, userID, currency)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create journal entry"})
+			return
+		}
+		account = *found
+	} else if err := h.DB.Where("id = ? AND user_id = ?", req.AccountID, userID).First(&account).Error; err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Account not found or doesn't belong to you"})
+		return
+	}
+	if !account.IsActive {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Account " + account.Name + " is closed"})
+		return
+	}
+	if req.Currency != "" && req.Currency != account.Currency {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Currency must match the account's currency (" + account.Currency + ")"})
+		return
+	}
+
 	// Parse date
 	var entryDate time.Time
 	if req.Date != "" {
@@ -104,9 +162,11 @@ func (h *FinanceJournalHandler) CreateJournal(c *gin.Context) {
 
 	journal := models.FinanceJournal{
 		UserID:        userID,
-		CategoryID:    req.CategoryID,
+		CategoryID:    &category.ID,
+		AccountID:     account.ID,
 		Type:          category.Type, // Inherit type from category
 		Amount:        req.Amount,
+		Currency:      account.Currency,
 		Title:         req.Title,
 		Description:   req.Description,
 		Date:          entryDate,
@@ -132,15 +192,18 @@ func (h *FinanceJournalHandler) CreateJournal(c *gin.Context) {
 // @Description Get all journal entries with optional filters
 // @Tags Finance Journals
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce json
 // @Param start_date query string false "Start date (YYYY-MM-DD)"
 // @Param end_date query string false "End date (YYYY-MM-DD)"
 // @Param category_id query int false "Filter by category ID"
-// @Param type query string false "Filter by type (income or expense)"
+// @Param account_id query int false "Filter by account ID"
+// @Param type query string false "Filter by type (income, expense or transfer)"
 // @Param page query int false "Page number (default: 1)"
 // @Param page_size query int false "Items per page (default: 20, max: 100)"
 // @Success 200 {object} JournalListResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /journals [get]
 func (h *FinanceJournalHandler) ListJournals(c *gin.Context) {
@@ -169,10 +232,17 @@ func (h *FinanceJournalHandler) ListJournals(c *gin.Context) {
 		query = query.Where("category_id = ?", categoryID)
 	}
 
+	// Filter by account
+	if accountID := c.Query("account_id"); accountID != "" {
+		query = query.Where("account_id = ?", accountID)
+	}
+
 	// Filter by type
 	if typeFilter := c.Query("type"); typeFilter != "" {
-		if typeFilter == "income" || typeFilter == "expense" {
+		if typeFilter == models.JournalTypeIncome || typeFilter == models.JournalTypeExpense {
 			query = query.Where("type = ?", typeFilter)
+		} else if typeFilter == "transfer" {
+			query = query.Where("transfer_id IS NOT NULL")
 		}
 	}
 
@@ -214,10 +284,12 @@ func (h *FinanceJournalHandler) ListJournals(c *gin.Context) {
 // @Description Get a single journal entry by ID
 // @Tags Finance Journals
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce json
 // @Param id path int true "Journal ID"
 // @Success 200 {object} models.FinanceJournal
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Router /journals/{id} [get]
 func (h *FinanceJournalHandler) GetJournal(c *gin.Context) {
@@ -240,9 +312,10 @@ func (h *FinanceJournalHandler) GetJournal(c *gin.Context) {
 
 // UpdateJournal godoc
 // @Summary Update a journal entry
-// @Description Update an existing journal entry
+// @Description Update an existing journal entry. Transfer legs are changed through their transfer. Entries can't be moved into a closed account.
 // @Tags Finance Journals
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Accept json
 // @Produce json
 // @Param id path int true "Journal ID"
@@ -250,6 +323,7 @@ func (h *FinanceJournalHandler) GetJournal(c *gin.Context) {
 // @Success 200 {object} models.FinanceJournal
 // @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /journals/{id} [put]
@@ -267,6 +341,10 @@ func (h *FinanceJournalHandler) UpdateJournal(c *gin.Context) {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Journal entry not found"})
 		return
 	}
+	if journal.TransferID != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "This entry is part of a transfer. Update the transfer instead."})
+		return
+	}
 
 	var req UpdateJournalRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -281,10 +359,33 @@ func (h *FinanceJournalHandler) UpdateJournal(c *gin.Context) {
 			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Category not found or doesn't belong to you"})
 			return
 		}
-		journal.CategoryID = *req.CategoryID
+		journal.CategoryID = &category.ID
 		journal.Type = category.Type
 	}
 
+	// Moving the entry to another account also moves it to that account's currency
+	if req.AccountID != nil || req.Currency != "" {
+		accountID := journal.AccountID
+		if req.AccountID != nil {
+			accountID = *req.AccountID
+		}
+		var account models.FinanceAccount
+		if err := h.DB.Where("id = ? AND user_id = ?", accountID, userID).First(&account).Error; err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Account not found or doesn't belong to you"})
+			return
+		}
+		if !account.IsActive && account.ID != journal.AccountID {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Account " + account.Name + " is closed"})
+			return
+		}
+		if req.Currency != "" && req.Currency != account.Currency {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Currency must match the account's currency (" + account.Currency + ")"})
+			return
+		}
+		journal.AccountID = account.ID
+		journal.Currency = account.Currency
+	}
+
 	// Update other fields
 	if req.Amount != nil && *req.Amount > 0 {
 		journal.Amount = *req.Amount
@@ -326,13 +427,16 @@ func (h *FinanceJournalHandler) UpdateJournal(c *gin.Context) {
 
 // DeleteJournal godoc
 // @Summary Delete a journal entry
-// @Description Delete a journal entry (soft delete)
+// @Description Delete a journal entry (soft delete). Transfer legs are deleted through their transfer.
 // @Tags Finance Journals
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce json
 // @Param id path int true "Journal ID"
 // @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 404 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /journals/{id} [delete]
@@ -350,6 +454,10 @@ func (h *FinanceJournalHandler) DeleteJournal(c *gin.Context) {
 		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Journal entry not found"})
 		return
 	}
+	if journal.TransferID != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "This entry is part of a transfer. Delete the transfer instead."})
+		return
+	}
 
 	if err := h.DB.Delete(&journal).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete journal entry"})
@@ -361,14 +469,16 @@ func (h *FinanceJournalHandler) DeleteJournal(c *gin.Context) {
 
 // GetSummary godoc
 // @Summary Get financial summary
-// @Description Get income, expense, and balance summary for a date range
+// @Description Get income, expense, and balance summary for a date range, in the user's base currency. Entries in other currencies are converted at the exchange rate on their date and also totalled per original currency. Transfers between the user's accounts are not income or expense and are left out.
 // @Tags Finance Journals
 // @Security BearerAuth
+// @Security ApiKeyAuth
 // @Produce json
 // @Param start_date query string false "Start date (YYYY-MM-DD, default: first day of current month)"
 // @Param end_date query string false "End date (YYYY-MM-DD, default: today)"
 // @Success 200 {object} JournalSummary
 // @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
 // @Failure 500 {object} ErrorResponse
 // @Router /journals/summary [get]
 func (h *FinanceJournalHandler) GetSummary(c *gin.Context) {
@@ -395,35 +505,88 @@ func (h *FinanceJournalHandler) GetSummary(c *gin.Context) {
 		}
 	}
 
-	// Calculate totals
-	var totalIncome float64
-	var totalExpense float64
-	var entryCount int64
+	var user models.User
+	if err := h.DB.Select("base_currency").First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to calculate summary"})
+		return
+	}
+
+	query := h.DB.Model(&models.FinanceJournal{}).
+		Where("user_id = ? AND date >= ? AND date <= ?", userID, startDate, endDate)
+	summary, err := summarizeJournals(h.DB, query, user.BaseCurrency, startDate, endDate)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to calculate summary"})
+		return
+	}
+	summary.StartDate = startDate.Format("2006-01-02")
+	summary.EndDate = endDate.Format("2006-01-02")
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// journalTotal is the sum of one day's entries of one type and currency
+type journalTotal struct {
+	Type     string
+	Currency string
+	Date     time.Time
+	Amount   money.Money
+	Entries  int64
+}
+
+// summarizeJournals totals the income and expenses matched by query in
+// baseCurrency, leaving out transfer legs. Postgres sums each day's entries per
+// currency exactly; each of those sums is then converted at that day's rate.
+func summarizeJournals(db, query *gorm.DB, baseCurrency string, start, end time.Time) (*JournalSummary, error) {
+	var totals []journalTotal
+	if err := query.Where("type IN ?", []string{models.JournalTypeIncome, models.JournalTypeExpense}).
+		Select("type, currency, date, SUM(amount) AS amount, COUNT(*) AS entries").
+		Group("type, currency, date").
+		Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+
+	var currencies []string
+	byCurrency := map[string]*CurrencySummary{}
+	for _, total := range totals {
+		if byCurrency[total.Currency] == nil {
+			byCurrency[total.Currency] = &CurrencySummary{Currency: total.Currency}
+			currencies = append(currencies, total.Currency)
+		}
+	}
+	sort.Strings(currencies)
 
-	h.DB.Model(&models.FinanceJournal{}).
-		Where("user_id = ? AND date >= ? AND date <= ? AND type = ?", userID, startDate, endDate, "income").
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&totalIncome)
+	converter, err := exchange.NewConverter(db, baseCurrency, currencies, start, end)
+	if err != nil {
+		return nil, err
+	}
 
-	h.DB.Model(&models.FinanceJournal{}).
-		Where("user_id = ? AND date >= ? AND date <= ? AND type = ?", userID, startDate, endDate, "expense").
-		Select("COALESCE(SUM(amount), 0)").
-		Scan(&totalExpense)
+	summary := &JournalSummary{BaseCurrency: baseCurrency, Currencies: []CurrencySummary{}}
+	for _, total := range totals {
+		entry := byCurrency[total.Currency]
+		entry.EntryCount += total.Entries
+		summary.EntryCount += total.Entries
 
-	h.DB.Model(&models.FinanceJournal{}).
-		Where("user_id = ? AND date >= ? AND date <= ?", userID, startDate, endDate).
-		Count(&entryCount)
+		converted, ok := converter.Convert(total.Amount, total.Currency, total.Date)
+		if !ok {
+			entry.UnconvertedCount += total.Entries
+		}
 
-	summary := JournalSummary{
-		TotalIncome:  totalIncome,
-		TotalExpense: totalExpense,
-		NetBalance:   totalIncome - totalExpense,
-		StartDate:    startDate.Format("2006-01-02"),
-		EndDate:      endDate.Format("2006-01-02"),
-		EntryCount:   entryCount,
+		if total.Type == models.JournalTypeIncome {
+			entry.TotalIncome += total.Amount
+			entry.ConvertedIncome += converted
+			summary.TotalIncome += converted
+		} else {
+			entry.TotalExpense += total.Amount
+			entry.ConvertedExpense += converted
+			summary.TotalExpense += converted
+		}
 	}
+	summary.NetBalance = summary.TotalIncome - summary.TotalExpense
 
-	c.JSON(http.StatusOK, summary)
+	for _, currency := range currencies {
+		summary.Currencies = append(summary.Currencies, *byCurrency[currency])
+	}
+	return summary, nil
 }
 
 // Helper function to parse int from string
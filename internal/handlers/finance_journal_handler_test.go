@@ -0,0 +1,204 @@
+// internal/handlers/finance_journal_handler_test.go
+package handlers_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/jedi116/kaizen-api/internal/handlers"
+	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+func createCategory(t *testing.T, api *testAPI, token, categoryType string) models.FinanceCategory {
+	t.Helper()
+	var category models.FinanceCategory
+	status := do(t, newClient(t), http.MethodPost, api.URL+"/api/categories", token,
+		handlers.CreateCategoryRequest{Name: "Test " + categoryType, Type: categoryType}, &category)
+	if status != http.StatusCreated {
+		t.Fatalf("creating a category returned %d", status)
+	}
+	return category
+}
+
+func createAccount(t *testing.T, api *testAPI, token string, req handlers.CreateAccountRequest) handlers.FinanceAccountResponse {
+	t.Helper()
+	var account handlers.FinanceAccountResponse
+	status := do(t, newClient(t), http.MethodPost, api.URL+"/api/accounts", token, req, &account)
+	if status != http.StatusCreated {
+		t.Fatalf("creating an account returned %d", status)
+	}
+	return account
+}
+
+func TestCreateJournalDefaultsToMainAccount(t *testing.T) {
+	api := startTestAPI(t, nil)
+	registered := register(t, api, uniqueEmail("journal"))
+	token := registered.AccessToken
+	category := createCategory(t, api, token, models.JournalTypeExpense)
+	client := newClient(t)
+
+	var first models.FinanceJournal
+	status := do(t, client, http.MethodPost, api.URL+"/api/journals", token, handlers.CreateJournalRequest{
+		CategoryID: category.ID,
+		Amount:     money.Money(2550),
+		Title:      "Groceries",
+	}, &first)
+	if status != http.StatusCreated {
+		t.Fatalf("creating an entry without an account returned %d, want 201", status)
+	}
+
+	var account models.FinanceAccount
+	if err := api.DB.First(&account, first.AccountID).Error; err != nil {
+		t.Fatalf("entry's account not found: %v", err)
+	}
+	if account.UserID != registered.User.ID || account.Currency != registered.User.BaseCurrency || !account.IsActive {
+		t.Errorf("entry went to %+v, want an active account of the user in %s", account, registered.User.BaseCurrency)
+	}
+	if first.Currency != account.Currency {
+		t.Errorf("entry currency = %s, want %s", first.Currency, account.Currency)
+	}
+
+	// The next entry goes to the same account
+	var second models.FinanceJournal
+	status = do(t, client, http.MethodPost, api.URL+"/api/journals", token, handlers.CreateJournalRequest{
+		CategoryID: category.ID,
+		Amount:     money.Money(1000),
+		Title:      "More groceries",
+	}, &second)
+	if status != http.StatusCreated || second.AccountID != first.AccountID {
+		t.Fatalf("second entry returned %d in account %d, want 201 in account %d", status, second.AccountID, first.AccountID)
+	}
+
+	// Another currency gets its own main account
+	var euros models.FinanceJournal
+	status = do(t, client, http.MethodPost, api.URL+"/api/journals", token, handlers.CreateJournalRequest{
+		CategoryID: category.ID,
+		Amount:     money.Money(1000),
+		Currency:   "EUR",
+		Title:      "Groceries abroad",
+	}, &euros)
+	if status != http.StatusCreated {
+		t.Fatalf("creating an entry in EUR returned %d, want 201", status)
+	}
+	if euros.AccountID == first.AccountID || euros.Currency != "EUR" {
+		t.Errorf("EUR entry went to account %d in %s", euros.AccountID, euros.Currency)
+	}
+}
+
+func TestClosedAccountRejectsEntries(t *testing.T) {
+	api := startTestAPI(t, nil)
+	token := register(t, api, uniqueEmail("closed")).AccessToken
+	category := createCategory(t, api, token, models.JournalTypeExpense)
+	client := newClient(t)
+
+	open := createAccount(t, api, token, handlers.CreateAccountRequest{Name: "Open", Type: models.AccountTypeChecking})
+	closed := createAccount(t, api, token, handlers.CreateAccountRequest{Name: "Closed", Type: models.AccountTypeChecking})
+
+	var entry models.FinanceJournal
+	status := do(t, client, http.MethodPost, api.URL+"/api/journals", token, handlers.CreateJournalRequest{
+		CategoryID: category.ID,
+		AccountID:  closed.ID,
+		Amount:     money.Money(500),
+		Title:      "Coffee",
+	}, &entry)
+	if status != http.StatusCreated {
+		t.Fatalf("creating an entry returned %d, want 201", status)
+	}
+
+	inactive := false
+	status = do(t, client, http.MethodPut, api.URL+"/api/accounts/"+strconv.Itoa(int(closed.ID)), token,
+		handlers.UpdateAccountRequest{IsActive: &inactive}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("closing the account returned %d, want 200", status)
+	}
+
+	status = do(t, client, http.MethodPost, api.URL+"/api/journals", token, handlers.CreateJournalRequest{
+		CategoryID: category.ID,
+		AccountID:  closed.ID,
+		Amount:     money.Money(500),
+		Title:      "Coffee",
+	}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("creating an entry in a closed account returned %d, want 400", status)
+	}
+
+	// Its existing entries can still be corrected, but nothing moves in
+	entryURL := api.URL + "/api/journals/" + strconv.Itoa(int(entry.ID))
+	status = do(t, client, http.MethodPut, entryURL, token, handlers.UpdateJournalRequest{Title: "Espresso"}, nil)
+	if status != http.StatusOK {
+		t.Errorf("updating an entry of a closed account returned %d, want 200", status)
+	}
+	status = do(t, client, http.MethodPut, entryURL, token, handlers.UpdateJournalRequest{AccountID: &open.ID}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("moving an entry out of a closed account returned %d, want 200", status)
+	}
+	status = do(t, client, http.MethodPut, entryURL, token, handlers.UpdateJournalRequest{AccountID: &closed.ID}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("moving an entry into a closed account returned %d, want 400", status)
+	}
+
+	status = do(t, client, http.MethodPost, api.URL+"/api/transfers", token, handlers.CreateTransferRequest{
+		FromAccountID: open.ID,
+		ToAccountID:   closed.ID,
+		Amount:        money.Money(500),
+	}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("transferring to a closed account returned %d, want 400", status)
+	}
+}
+
+func TestNetWorthLeavesOutAccountsNotYetOpened(t *testing.T) {
+	api := startTestAPI(t, nil)
+	token := register(t, api, uniqueEmail("networth")).AccessToken
+	client := newClient(t)
+
+	savings := createAccount(t, api, token, handlers.CreateAccountRequest{
+		Name:           "Savings",
+		Type:           models.AccountTypeSavings,
+		OpeningBalance: money.Money(100000),
+	})
+
+	netWorth := func(asOf time.Time) handlers.NetWorthResponse {
+		t.Helper()
+		var response handlers.NetWorthResponse
+		status := do(t, client, http.MethodGet, api.URL+"/api/accounts/net-worth?as_of="+asOf.Format("2006-01-02"), token, nil, &response)
+		if status != http.StatusOK {
+			t.Fatalf("net worth returned %d, want 200", status)
+		}
+		return response
+	}
+
+	if got := netWorth(time.Now()); got.NetWorth != money.Money(100000) || len(got.Accounts) != 1 {
+		t.Errorf("net worth today = %s over %d accounts, want 1000.00 over 1", got.NetWorth, len(got.Accounts))
+	}
+	lastYear := time.Now().AddDate(-1, 0, 0)
+	if got := netWorth(lastYear); got.NetWorth != 0 || len(got.Accounts) != 0 {
+		t.Errorf("net worth a year ago = %s over %d accounts, want 0 over none", got.NetWorth, len(got.Accounts))
+	}
+
+	var balance handlers.AccountBalanceResponse
+	status := do(t, client, http.MethodGet, api.URL+"/api/accounts/"+strconv.Itoa(int(savings.ID))+"/balance?as_of="+lastYear.Format("2006-01-02"),
+		token, nil, &balance)
+	if status != http.StatusOK || balance.Balance != 0 {
+		t.Errorf("balance a year ago returned %d with %s, want 200 with 0.00", status, balance.Balance)
+	}
+
+	// An entry backdated before the account was created opens it from then
+	category := createCategory(t, api, token, models.JournalTypeIncome)
+	status = do(t, client, http.MethodPost, api.URL+"/api/journals", token, handlers.CreateJournalRequest{
+		CategoryID: category.ID,
+		AccountID:  savings.ID,
+		Amount:     money.Money(5000),
+		Title:      "Interest",
+		Date:       lastYear.AddDate(0, 0, -1).Format("2006-01-02"),
+	}, nil)
+	if status != http.StatusCreated {
+		t.Fatalf("creating a backdated entry returned %d, want 201", status)
+	}
+	if got := netWorth(lastYear); got.NetWorth != money.Money(105000) || len(got.Accounts) != 1 {
+		t.Errorf("net worth a year ago = %s over %d accounts, want 1050.00 over 1", got.NetWorth, len(got.Accounts))
+	}
+}
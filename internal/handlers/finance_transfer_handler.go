@@ -0,0 +1,465 @@
+// internal/handlers/finance_transfer_handler.go
+package handlers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+type FinanceTransferHandler struct {
+	DB *gorm.DB
+}
+
+type CreateTransferRequest struct {
+	FromAccountID uint         `json:"from_account_id" binding:"required" example:"1"`
+	ToAccountID   uint         `json:"to_account_id" binding:"required" example:"2"`
+	Amount        money.Money  `json:"amount" binding:"required,gt=0" swaggertype:"number" example:"500.00"`
+	ToAmount      *money.Money `json:"to_amount" swaggertype:"number" example:"460.25"` // Required when the accounts have different currencies
+	Title         string       `json:"title" example:"Move to savings"`                 // Defaults to "Transfer to <account>"
+	Description   string       `json:"description" example:"Monthly savings"`
+	Date          string       `json:"date" example:"2025-01-15"`
+}
+
+type UpdateTransferRequest struct {
+	FromAccountID *uint        `json:"from_account_id" example:"1"`
+	ToAccountID   *uint        `json:"to_account_id" example:"2"`
+	Amount        *money.Money `json:"amount" swaggertype:"number" example:"500.00"`
+	ToAmount      *money.Money `json:"to_amount" swaggertype:"number" example:"460.25"` // Required when the accounts have different currencies and the accounts or amount change
+	Title         string       `json:"title" example:"Move to savings"`
+	Description   string       `json:"description" example:"Monthly savings"`
+	Date          string       `json:"date" example:"2025-01-15"`
+}
+
+type TransferListResponse struct {
+	Transfers  []models.FinanceTransfer `json:"transfers"`
+	TotalCount int64                    `json:"total_count"`
+	Page       int                      `json:"page"`
+	PageSize   int                      `json:"page_size"`
+}
+
+// CreateTransfer godoc
+// @Summary Create a transfer
+// @Description Move money between two of the user's accounts. Both journal legs are created together; transfers change account balances but are not counted as income or expense. Closed accounts can't be used.
+// @Tags Finance Transfers
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param request body CreateTransferRequest true "Transfer details"
+// @Success 201 {object} models.FinanceTransfer
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transfers [post]
+func (h *FinanceTransferHandler) CreateTransfer(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var req CreateTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	from, to, message := h.transferAccounts(userID, req.FromAccountID, req.ToAccountID, nil)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: message})
+		return
+	}
+
+	toAmount, message := transferToAmount(from, to, req.Amount, req.ToAmount, req.ToAmount != nil)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: message})
+		return
+	}
+
+	// Parse date
+	var transferDate time.Time
+	if req.Date != "" {
+		parsed, err := time.Parse("2006-01-02", req.Date)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid date format. Use YYYY-MM-DD"})
+			return
+		}
+		transferDate = parsed
+	} else {
+		transferDate = time.Now()
+	}
+
+	title := req.Title
+	if title == "" {
+		title = "Transfer to " + to.Name
+	}
+
+	transfer := models.FinanceTransfer{
+		UserID:        userID,
+		FromAccountID: from.ID,
+		ToAccountID:   to.ID,
+		Amount:        req.Amount,
+		ToAmount:      toAmount,
+		Title:         title,
+		Description:   req.Description,
+		Date:          transferDate,
+	}
+
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&transfer).Error; err != nil {
+			return err
+		}
+		legs := []models.FinanceJournal{
+			transferLeg(&transfer, models.JournalTypeTransferOut, from),
+			transferLeg(&transfer, models.JournalTypeTransferIn, to),
+		}
+		return tx.Create(&legs).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create transfer"})
+		return
+	}
+
+	// Load legs for response
+	h.DB.Preload("Journals").First(&transfer, transfer.ID)
+
+	c.JSON(http.StatusCreated, transfer)
+}
+
+// ListTransfers godoc
+// @Summary List transfers
+// @Description Get all transfers between the user's accounts with optional filters
+// @Tags Finance Transfers
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param account_id query int false "Filter by source or destination account ID"
+// @Param page query int false "Page number (default: 1)"
+// @Param page_size query int false "Items per page (default: 20, max: 100)"
+// @Success 200 {object} TransferListResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transfers [get]
+func (h *FinanceTransferHandler) ListTransfers(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	query := h.DB.Where("user_id = ?", userID)
+
+	// Filter by date range
+	if startDate := c.Query("start_date"); startDate != "" {
+		if parsed, err := time.Parse("2006-01-02", startDate); err == nil {
+			query = query.Where("date >= ?", parsed)
+		}
+	}
+	if endDate := c.Query("end_date"); endDate != "" {
+		if parsed, err := time.Parse("2006-01-02", endDate); err == nil {
+			query = query.Where("date <= ?", parsed)
+		}
+	}
+
+	// Filter by account on either side
+	if accountID := c.Query("account_id"); accountID != "" {
+		query = query.Where("from_account_id = ? OR to_account_id = ?", accountID, accountID)
+	}
+
+	// Get total count
+	var totalCount int64
+	query.Model(&models.FinanceTransfer{}).Count(&totalCount)
+
+	// Pagination
+	page := 1
+	pageSize := 20
+	if p := c.Query("page"); p != "" {
+		if parsed, err := parseInt(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+	if ps := c.Query("page_size"); ps != "" {
+		if parsed, err := parseInt(ps); err == nil && parsed > 0 && parsed <= 100 {
+			pageSize = parsed
+		}
+	}
+	offset := (page - 1) * pageSize
+
+	var transfers []models.FinanceTransfer
+	if err := query.Preload("Journals").Order("date DESC, created_at DESC").Offset(offset).Limit(pageSize).Find(&transfers).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to fetch transfers"})
+		return
+	}
+
+	c.JSON(http.StatusOK, TransferListResponse{
+		Transfers:  transfers,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+	})
+}
+
+// GetTransfer godoc
+// @Summary Get a transfer
+// @Description Get a single transfer by ID with both of its journal legs
+// @Tags Finance Transfers
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Transfer ID"
+// @Success 200 {object} models.FinanceTransfer
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Router /transfers/{id} [get]
+func (h *FinanceTransferHandler) GetTransfer(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var transfer models.FinanceTransfer
+	if err := h.DB.Preload("Journals").Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&transfer).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Transfer not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// UpdateTransfer godoc
+// @Summary Update a transfer
+// @Description Update an existing transfer. Both journal legs are updated together.
+// @Tags Finance Transfers
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "Transfer ID"
+// @Param request body UpdateTransferRequest true "Transfer data"
+// @Success 200 {object} models.FinanceTransfer
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transfers/{id} [put]
+func (h *FinanceTransferHandler) UpdateTransfer(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var transfer models.FinanceTransfer
+	if err := h.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&transfer).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Transfer not found"})
+		return
+	}
+
+	var req UpdateTransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	fromID, toID := transfer.FromAccountID, transfer.ToAccountID
+	if req.FromAccountID != nil {
+		fromID = *req.FromAccountID
+	}
+	if req.ToAccountID != nil {
+		toID = *req.ToAccountID
+	}
+	from, to, message := h.transferAccounts(userID, fromID, toID, &transfer)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: message})
+		return
+	}
+
+	amount := transfer.Amount
+	if req.Amount != nil {
+		if *req.Amount <= 0 {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Amount must be greater than 0"})
+			return
+		}
+		amount = *req.Amount
+	}
+
+	// The received amount of a cross-currency transfer only carries over if
+	// neither the accounts nor the amount changed
+	toAmount := req.ToAmount
+	changed := fromID != transfer.FromAccountID || toID != transfer.ToAccountID || amount != transfer.Amount
+	if toAmount == nil && !changed {
+		toAmount = &transfer.ToAmount
+	}
+	resolved, message := transferToAmount(from, to, amount, toAmount, req.ToAmount != nil)
+	if message != "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: message})
+		return
+	}
+
+	transfer.FromAccountID = from.ID
+	transfer.ToAccountID = to.ID
+	transfer.Amount = amount
+	transfer.ToAmount = resolved
+
+	// Update other fields
+	if req.Title != "" {
+		transfer.Title = req.Title
+	}
+	if req.Description != "" {
+		transfer.Description = req.Description
+	}
+	if req.Date != "" {
+		if parsed, err := time.Parse("2006-01-02", req.Date); err == nil {
+			transfer.Date = parsed
+		}
+	}
+
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&transfer).Error; err != nil {
+			return err
+		}
+
+		var legs []models.FinanceJournal
+		if err := tx.Where("transfer_id = ?", transfer.ID).Find(&legs).Error; err != nil {
+			return err
+		}
+		for _, leg := range legs {
+			account := from
+			if leg.Type == models.JournalTypeTransferIn {
+				account = to
+			}
+			updated := transferLeg(&transfer, leg.Type, account)
+			updated.Model = leg.Model
+			if err := tx.Save(&updated).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update transfer"})
+		return
+	}
+
+	// Load legs for response
+	h.DB.Preload("Journals").First(&transfer, transfer.ID)
+
+	c.JSON(http.StatusOK, transfer)
+}
+
+// DeleteTransfer godoc
+// @Summary Delete a transfer
+// @Description Delete a transfer and both of its journal legs (soft delete)
+// @Tags Finance Transfers
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Produce json
+// @Param id path int true "Transfer ID"
+// @Success 200 {object} MessageResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /transfers/{id} [delete]
+func (h *FinanceTransferHandler) DeleteTransfer(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var transfer models.FinanceTransfer
+	if err := h.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&transfer).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Transfer not found"})
+		return
+	}
+
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transfer_id = ?", transfer.ID).Delete(&models.FinanceJournal{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&transfer).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete transfer"})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Transfer deleted successfully"})
+}
+
+// transferAccounts loads the two accounts of a transfer, returning a message
+// for the client if they are the same, not the user's or closed. A closed
+// account that current, the transfer being updated, already uses is allowed.
+func (h *FinanceTransferHandler) transferAccounts(userID, fromID, toID uint, current *models.FinanceTransfer) (*models.FinanceAccount, *models.FinanceAccount, string) {
+	if fromID == toID {
+		return nil, nil, "A transfer needs two different accounts"
+	}
+
+	var from, to models.FinanceAccount
+	if err := h.DB.Where("id = ? AND user_id = ?", fromID, userID).First(&from).Error; err != nil {
+		return nil, nil, "Source account not found or doesn't belong to you"
+	}
+	if err := h.DB.Where("id = ? AND user_id = ?", toID, userID).First(&to).Error; err != nil {
+		return nil, nil, "Destination account not found or doesn't belong to you"
+	}
+	if !from.IsActive && (current == nil || current.FromAccountID != from.ID) {
+		return nil, nil, "Account " + from.Name + " is closed"
+	}
+	if !to.IsActive && (current == nil || current.ToAccountID != to.ID) {
+		return nil, nil, "Account " + to.Name + " is closed"
+	}
+	return &from, &to, ""
+}
+
+// transferToAmount works out the amount a transfer adds to the destination
+// account. Between accounts in the same currency it is always amount; otherwise
+// toAmount is required. explicit says whether the client sent toAmount.
+func transferToAmount(from, to *models.FinanceAccount, amount money.Money, toAmount *money.Money, explicit bool) (money.Money, string) {
+	if from.Currency == to.Currency {
+		if explicit && *toAmount != amount {
+			return 0, "to_amount must equal amount when both accounts are in " + from.Currency
+		}
+		return amount, ""
+	}
+	if toAmount == nil {
+		return 0, "to_amount is required for a transfer from " + from.Currency + " to " + to.Currency
+	}
+	if *toAmount <= 0 {
+		return 0, "to_amount must be greater than 0"
+	}
+	return *toAmount, ""
+}
+
+// transferLeg builds the journal recording one side of a transfer
+func transferLeg(transfer *models.FinanceTransfer, legType string, account *models.FinanceAccount) models.FinanceJournal {
+	amount := transfer.Amount
+	if legType == models.JournalTypeTransferIn {
+		amount = transfer.ToAmount
+	}
+	return models.FinanceJournal{
+		UserID:      transfer.UserID,
+		AccountID:   account.ID,
+		TransferID:  &transfer.ID,
+		Type:        legType,
+		Amount:      amount,
+		Currency:    account.Currency,
+		Title:       transfer.Title,
+		Description: transfer.Description,
+		Date:        transfer.Date,
+	}
+}
@@ -0,0 +1,178 @@
+// internal/handlers/finance_transfer_handler_test.go
+package handlers_test
+
+import (
+	"net/http"
+	"strconv"
+	"testing"
+
+	"github.com/jedi116/kaizen-api/internal/handlers"
+	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+func accountBalance(t *testing.T, api *testAPI, token string, accountID uint) money.Money {
+	t.Helper()
+	var balance handlers.AccountBalanceResponse
+	status := do(t, newClient(t), http.MethodGet, api.URL+"/api/accounts/"+strconv.Itoa(int(accountID))+"/balance", token, nil, &balance)
+	if status != http.StatusOK {
+		t.Fatalf("account balance returned %d", status)
+	}
+	return balance.Balance
+}
+
+func TestTransferLegsChangeTogether(t *testing.T) {
+	api := startTestAPI(t, nil)
+	token := register(t, api, uniqueEmail("transfer")).AccessToken
+	client := newClient(t)
+
+	checking := createAccount(t, api, token, handlers.CreateAccountRequest{
+		Name:           "Checking",
+		Type:           models.AccountTypeChecking,
+		OpeningBalance: money.Money(100000),
+	})
+	savings := createAccount(t, api, token, handlers.CreateAccountRequest{Name: "Savings", Type: models.AccountTypeSavings})
+
+	checkBalances := func(wantChecking, wantSavings money.Money) {
+		t.Helper()
+		if got := accountBalance(t, api, token, checking.ID); got != wantChecking {
+			t.Errorf("checking balance = %s, want %s", got, wantChecking)
+		}
+		if got := accountBalance(t, api, token, savings.ID); got != wantSavings {
+			t.Errorf("savings balance = %s, want %s", got, wantSavings)
+		}
+	}
+	checkLegs := func(transfer models.FinanceTransfer, amount money.Money) {
+		t.Helper()
+		var legs []models.FinanceJournal
+		if err := api.DB.Where("transfer_id = ?", transfer.ID).Order("type").Find(&legs).Error; err != nil {
+			t.Fatal(err)
+		}
+		if len(legs) != 2 {
+			t.Fatalf("transfer has %d legs, want 2", len(legs))
+		}
+		in, out := legs[0], legs[1]
+		if in.Type != models.JournalTypeTransferIn || in.AccountID != savings.ID || in.Amount != amount {
+			t.Errorf("incoming leg is %s of %s into account %d, want transfer_in of %s into %d", in.Type, in.Amount, in.AccountID, amount, savings.ID)
+		}
+		if out.Type != models.JournalTypeTransferOut || out.AccountID != checking.ID || out.Amount != amount {
+			t.Errorf("outgoing leg is %s of %s from account %d, want transfer_out of %s from %d", out.Type, out.Amount, out.AccountID, amount, checking.ID)
+		}
+	}
+
+	var transfer models.FinanceTransfer
+	status := do(t, client, http.MethodPost, api.URL+"/api/transfers", token, handlers.CreateTransferRequest{
+		FromAccountID: checking.ID,
+		ToAccountID:   savings.ID,
+		Amount:        money.Money(25000),
+	}, &transfer)
+	if status != http.StatusCreated {
+		t.Fatalf("creating a transfer returned %d, want 201", status)
+	}
+	checkLegs(transfer, money.Money(25000))
+	checkBalances(money.Money(75000), money.Money(25000))
+
+	transferURL := api.URL + "/api/transfers/" + strconv.Itoa(int(transfer.ID))
+	amount := money.Money(30000)
+	status = do(t, client, http.MethodPut, transferURL, token, handlers.UpdateTransferRequest{Amount: &amount}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("updating the transfer returned %d, want 200", status)
+	}
+	checkLegs(transfer, amount)
+	checkBalances(money.Money(70000), money.Money(30000))
+
+	status = do(t, client, http.MethodDelete, transferURL, token, nil, nil)
+	if status != http.StatusOK {
+		t.Fatalf("deleting the transfer returned %d, want 200", status)
+	}
+	var legs int64
+	api.DB.Model(&models.FinanceJournal{}).Where("transfer_id = ?", transfer.ID).Count(&legs)
+	if legs != 0 {
+		t.Errorf("%d legs left after deleting the transfer", legs)
+	}
+	checkBalances(money.Money(100000), 0)
+}
+
+func TestTransferLegsAreOnlyChangedThroughTheirTransfer(t *testing.T) {
+	api := startTestAPI(t, nil)
+	token := register(t, api, uniqueEmail("transferleg")).AccessToken
+	client := newClient(t)
+
+	checking := createAccount(t, api, token, handlers.CreateAccountRequest{Name: "Checking", Type: models.AccountTypeChecking})
+	savings := createAccount(t, api, token, handlers.CreateAccountRequest{Name: "Savings", Type: models.AccountTypeSavings})
+
+	var transfer models.FinanceTransfer
+	status := do(t, client, http.MethodPost, api.URL+"/api/transfers", token, handlers.CreateTransferRequest{
+		FromAccountID: checking.ID,
+		ToAccountID:   savings.ID,
+		Amount:        money.Money(1000),
+	}, &transfer)
+	if status != http.StatusCreated || len(transfer.Journals) != 2 {
+		t.Fatalf("creating a transfer returned %d with %d legs, want 201 with 2", status, len(transfer.Journals))
+	}
+
+	for _, leg := range transfer.Journals {
+		legURL := api.URL + "/api/journals/" + strconv.Itoa(int(leg.ID))
+		status = do(t, client, http.MethodPut, legURL, token, handlers.UpdateJournalRequest{Title: "Edited"}, nil)
+		if status != http.StatusBadRequest {
+			t.Errorf("updating %s leg through /journals returned %d, want 400", leg.Type, status)
+		}
+		status = do(t, client, http.MethodDelete, legURL, token, nil, nil)
+		if status != http.StatusBadRequest {
+			t.Errorf("deleting %s leg through /journals returned %d, want 400", leg.Type, status)
+		}
+
+		var stored models.FinanceJournal
+		if err := api.DB.First(&stored, leg.ID).Error; err != nil {
+			t.Errorf("%s leg gone after a rejected delete: %v", leg.Type, err)
+		} else if stored.Title != transfer.Title {
+			t.Errorf("%s leg title = %q after a rejected update, want %q", leg.Type, stored.Title, transfer.Title)
+		}
+	}
+}
+
+func TestSummaryLeavesOutTransfers(t *testing.T) {
+	api := startTestAPI(t, nil)
+	token := register(t, api, uniqueEmail("transfersummary")).AccessToken
+	client := newClient(t)
+
+	checking := createAccount(t, api, token, handlers.CreateAccountRequest{Name: "Checking", Type: models.AccountTypeChecking})
+	savings := createAccount(t, api, token, handlers.CreateAccountRequest{Name: "Savings", Type: models.AccountTypeSavings})
+
+	category := createCategory(t, api, token, models.JournalTypeIncome)
+	status := do(t, client, http.MethodPost, api.URL+"/api/journals", token, handlers.CreateJournalRequest{
+		CategoryID: category.ID,
+		AccountID:  checking.ID,
+		Amount:     money.Money(200000),
+		Title:      "Salary",
+	}, nil)
+	if status != http.StatusCreated {
+		t.Fatalf("creating income returned %d, want 201", status)
+	}
+	status = do(t, client, http.MethodPost, api.URL+"/api/transfers", token, handlers.CreateTransferRequest{
+		FromAccountID: checking.ID,
+		ToAccountID:   savings.ID,
+		Amount:        money.Money(50000),
+	}, nil)
+	if status != http.StatusCreated {
+		t.Fatalf("creating a transfer returned %d, want 201", status)
+	}
+
+	var summary handlers.JournalSummary
+	status = do(t, client, http.MethodGet, api.URL+"/api/journals/summary", token, nil, &summary)
+	if status != http.StatusOK {
+		t.Fatalf("summary returned %d, want 200", status)
+	}
+	if summary.TotalIncome != money.Money(200000) || summary.TotalExpense != 0 || summary.EntryCount != 1 {
+		t.Errorf("summary has income %s, expense %s over %d entries, want 2000.00, 0.00 over 1",
+			summary.TotalIncome, summary.TotalExpense, summary.EntryCount)
+	}
+
+	// Balances still move
+	if got := accountBalance(t, api, token, checking.ID); got != money.Money(150000) {
+		t.Errorf("checking balance = %s, want 1500.00", got)
+	}
+	if got := accountBalance(t, api, token, savings.ID); got != money.Money(50000) {
+		t.Errorf("savings balance = %s, want 500.00", got)
+	}
+}
@@ -0,0 +1,181 @@
+// internal/handlers/magic_link_handler.go
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jedi116/kaizen-api/internal/audit"
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+const magicLinkNonceCookie = "magic_link_nonce"
+
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email" example:"john@example.com"`
+}
+
+// MagicLinkResponse is the same whether or not the email has an account.
+// Clients that can't keep cookies, such as mobile apps, store the nonce and
+// send it back with the token.
+type MagicLinkResponse struct {
+	Message   string `json:"message" example:"If that email is registered, a sign-in link has been sent"`
+	Nonce     string `json:"nonce"`
+	ExpiresIn int    `json:"expires_in" example:"900"` // Seconds until the link expires
+}
+
+type MagicLinkVerifyRequest struct {
+	Token string `json:"token" binding:"required"`
+	Nonce string `json:"nonce"` // Defaults to the magic_link_nonce cookie
+}
+
+// RequestMagicLink godoc
+// @Summary Request a sign-in link
+// @Description Email a single-use sign-in link. The link only works together with the nonce returned here (also set as an HttpOnly cookie), so it must be opened on the requesting device. Requests are rate limited per email address. Earlier links stay valid until one is used, so a request by someone else doesn't cancel yours.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body MagicLinkRequest true "Email address"
+// @Success 202 {object} MagicLinkResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse "Too many links requested for this email; see Retry-After"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/magic-link [post]
+func (h *AuthHandler) RequestMagicLink(c *gin.Context) {
+	var req MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	wait, err := auth.MagicLinkRetryAfter(req.Email, h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to check sign-in link requests"})
+		return
+	}
+	if wait > 0 {
+		audit.Record(c, h.DB, audit.Event{Type: audit.EventMagicLinkRequested, Failed: true, Email: req.Email, Details: "Too many requests"})
+		seconds := int(math.Ceil(wait.Seconds()))
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		c.JSON(http.StatusTooManyRequests, ErrorResponse{
+			Error: fmt.Sprintf("Too many sign-in links requested. Try again in %d seconds.", seconds),
+		})
+		return
+	}
+	if err := auth.RecordMagicLinkRequest(req.Email, h.DB); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to check sign-in link requests"})
+		return
+	}
+
+	nonce, err := auth.NewMagicLinkNonce()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create sign-in link"})
+		return
+	}
+	h.setMagicLinkNonceCookie(c, nonce, int(auth.MagicLinkDuration.Seconds()))
+
+	response := MagicLinkResponse{
+		Message:   "If that email is registered, a sign-in link has been sent",
+		Nonce:     nonce,
+		ExpiresIn: int(auth.MagicLinkDuration.Seconds()),
+	}
+
+	var user models.User
+	if err := h.DB.Where("email = ?", req.Email).First(&user).Error; err != nil {
+		c.JSON(http.StatusAccepted, response)
+		return
+	}
+
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventMagicLinkRequested, UserID: user.ID})
+
+	token, err := auth.IssueMagicLinkToken(user.ID, nonce, h.DB)
+	if err != nil {
+		log.Printf("failed to issue magic link for user %d: %v", user.ID, err)
+		c.JSON(http.StatusAccepted, response)
+		return
+	}
+
+	// Send in the background so response timing doesn't reveal whether the account exists
+	msg := magicLinkEmail(&user, token)
+	go func() {
+		if err := h.Mailer.Send(context.Background(), msg); err != nil {
+			log.Printf("failed to send magic link email to user %d: %v", user.ID, err)
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, response)
+}
+
+// VerifyMagicLink godoc
+// @Summary Sign in with a magic link
+// @Description Exchange a sign-in link's token for a token pair. The nonce from the request must be sent in the body or the magic_link_nonce cookie. Users with two-factor authentication get a challenge instead.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body MagicLinkVerifyRequest true "Link token and device nonce"
+// @Success 200 {object} AuthResponse
+// @Success 202 {object} TwoFactorChallengeResponse "Two-factor code required"
+// @Failure 400 {object} ErrorResponse "Invalid or expired link, or opened on another device"
+// @Failure 403 {object} ErrorResponse "Account is disabled"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/magic-link/verify [post]
+func (h *AuthHandler) VerifyMagicLink(c *gin.Context) {
+	var req MagicLinkVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	nonce := req.Nonce
+	if nonce == "" {
+		nonce, _ = c.Cookie(magicLinkNonceCookie)
+	}
+	if nonce == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Open the sign-in link on the device where you requested it"})
+		return
+	}
+
+	token, err := auth.ConsumeMagicLinkToken(req.Token, nonce, h.DB)
+	if errors.Is(err, auth.ErrInvalidOneTimeToken) {
+		audit.Record(c, h.DB, audit.Event{Type: audit.EventLogin, Failed: true, Details: "Invalid magic link"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error: "Invalid or expired sign-in link. Open the link on the device where you requested it.",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify sign-in link"})
+		return
+	}
+	h.setMagicLinkNonceCookie(c, "", -1)
+
+	var user models.User
+	if err := h.DB.First(&user, token.UserID).Error; err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid or expired sign-in link"})
+		return
+	}
+
+	// The link replaces the password, not the second factor
+	if user.TOTPEnabled {
+		h.respondWithChallenge(c, &user)
+		return
+	}
+
+	h.completeLogin(c, &user, "magic link")
+}
+
+// setMagicLinkNonceCookie uses SameSite=Lax so the cookie is still sent when
+// the web app is opened from the link in the email
+func (h *AuthHandler) setMagicLinkNonceCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(magicLinkNonceCookie, value, maxAge, "/api/auth/magic-link", "", os.Getenv("ENV") == "production", true)
+}
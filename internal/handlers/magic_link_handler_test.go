@@ -0,0 +1,121 @@
+// internal/handlers/magic_link_handler_test.go
+package handlers_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jedi116/kaizen-api/internal/handlers"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+// requestMagicLink asks for a sign-in link from client and returns the response
+func requestMagicLink(t *testing.T, api *testAPI, client *http.Client, email string) handlers.MagicLinkResponse {
+	t.Helper()
+	var response handlers.MagicLinkResponse
+	status := do(t, client, http.MethodPost, api.URL+"/api/auth/magic-link", "", handlers.MagicLinkRequest{Email: email}, &response)
+	if status != http.StatusAccepted || response.Nonce == "" {
+		t.Fatalf("requesting a sign-in link returned %d, want 202 with a nonce", status)
+	}
+	return response
+}
+
+// magicLinkToken waits for the count-th sign-in email to email and returns the link's token
+func magicLinkToken(t *testing.T, api *testAPI, email string, count int) string {
+	t.Helper()
+	return emailedToken(t, api, "/magic-link", email, count)
+}
+
+func verifyMagicLink(t *testing.T, api *testAPI, client *http.Client, token, nonce string) int {
+	t.Helper()
+	return do(t, client, http.MethodPost, api.URL+"/api/auth/magic-link/verify", "",
+		handlers.MagicLinkVerifyRequest{Token: token, Nonce: nonce}, nil)
+}
+
+// allowAnotherMagicLink lets the next request for email through the rate limit
+func allowAnotherMagicLink(t *testing.T, api *testAPI, email string) {
+	t.Helper()
+	err := api.DB.Model(&models.LoginThrottle{}).Where("key = ?", "magic_link:"+email).
+		Update("last_failure_at", time.Now().Add(-time.Hour)).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMagicLinkSignIn(t *testing.T) {
+	api := startTestAPI(t, nil)
+	email := uniqueEmail("magic")
+	registered := register(t, api, email)
+
+	client := newClient(t)
+	requestMagicLink(t, api, client, email)
+	token := magicLinkToken(t, api, email, 1)
+
+	msg, err := api.Outbox.LastMessageTo(email)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(msg.Body, "expires in 15 minutes") {
+		t.Errorf("sign-in email doesn't state how long the link lasts:\n%s", msg.Body)
+	}
+
+	// The nonce comes from the cookie set by the request
+	var response handlers.AuthResponse
+	status := do(t, client, http.MethodPost, api.URL+"/api/auth/magic-link/verify", "",
+		handlers.MagicLinkVerifyRequest{Token: token}, &response)
+	if status != http.StatusOK || response.User.ID != registered.User.ID {
+		t.Fatalf("verifying the link returned %d for user %d, want 200 for user %d", status, response.User.ID, registered.User.ID)
+	}
+
+	if status := verifyMagicLink(t, api, client, token, ""); status != http.StatusBadRequest {
+		t.Fatalf("replaying the link returned %d, want 400", status)
+	}
+}
+
+func TestMagicLinkIsBoundToDevice(t *testing.T) {
+	api := startTestAPI(t, nil)
+	email := uniqueEmail("magic")
+	register(t, api, email)
+
+	client := newClient(t)
+	requested := requestMagicLink(t, api, client, email)
+	token := magicLinkToken(t, api, email, 1)
+
+	if status := verifyMagicLink(t, api, newClient(t), token, ""); status != http.StatusBadRequest {
+		t.Fatalf("opening the link on another device returned %d, want 400", status)
+	}
+	if status := verifyMagicLink(t, api, newClient(t), token, requested.Nonce+"x"); status != http.StatusBadRequest {
+		t.Fatalf("opening the link with another nonce returned %d, want 400", status)
+	}
+
+	// Failed attempts elsewhere leave the link working on the device that asked for it
+	if status := verifyMagicLink(t, api, newClient(t), token, requested.Nonce); status != http.StatusOK {
+		t.Fatalf("opening the link with the device's nonce returned %d, want 200", status)
+	}
+}
+
+func TestMagicLinkSurvivesLaterRequests(t *testing.T) {
+	api := startTestAPI(t, nil)
+	email := uniqueEmail("magic")
+	register(t, api, email)
+
+	victim := newClient(t)
+	requestMagicLink(t, api, victim, email)
+	victimToken := magicLinkToken(t, api, email, 1)
+
+	// Someone else asks for links to the same address
+	allowAnotherMagicLink(t, api, email)
+	other := requestMagicLink(t, api, newClient(t), email)
+	otherToken := magicLinkToken(t, api, email, 2)
+
+	if status := verifyMagicLink(t, api, victim, victimToken, ""); status != http.StatusOK {
+		t.Fatalf("the first link returned %d after another was requested, want 200", status)
+	}
+
+	// Signing in with one link cancels the others
+	if status := verifyMagicLink(t, api, newClient(t), otherToken, other.Nonce); status != http.StatusBadRequest {
+		t.Fatalf("another link returned %d after signing in, want 400", status)
+	}
+}
@@ -0,0 +1,320 @@
+// internal/handlers/oidc_handler.go
+package handlers
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/audit"
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/oidc"
+)
+
+const oidcStateCookie = "oidc_state"
+
+type OIDCProvidersResponse struct {
+	Providers []string `json:"providers" example:"google,okta"`
+}
+
+type OIDCLinkResponse struct {
+	AuthorizationURL string `json:"authorization_url" example:"https://accounts.example.com/authorize?client_id=kaizen&..."`
+}
+
+// ListOIDCProviders godoc
+// @Summary List OpenID Connect providers
+// @Description List the external providers users can sign in with
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} OIDCProvidersResponse
+// @Router /auth/oidc/providers [get]
+func (h *AuthHandler) ListOIDCProviders(c *gin.Context) {
+	names := make([]string, 0, len(h.OIDCProviders))
+	for name := range h.OIDCProviders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	c.JSON(http.StatusOK, OIDCProvidersResponse{Providers: names})
+}
+
+// OIDCLogin godoc
+// @Summary Sign in with an OpenID Connect provider
+// @Description Redirect the browser to the provider's login page (authorization code flow with PKCE). The provider redirects back to the callback endpoint.
+// @Tags Authentication
+// @Param provider path string true "Provider name"
+// @Success 302
+// @Failure 404 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /auth/oidc/{provider}/login [get]
+func (h *AuthHandler) OIDCLogin(c *gin.Context) {
+	provider, ok := h.oidcProvider(c)
+	if !ok {
+		return
+	}
+
+	authURL, ok := h.startOIDCFlow(c, provider, 0)
+	if !ok {
+		return
+	}
+
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// LinkOIDCIdentity godoc
+// @Summary Link an OpenID Connect identity
+// @Description Start linking an external identity to the current account. Send the browser to the returned URL; the callback links the identity instead of signing in.
+// @Tags Authentication
+// @Security BearerAuth
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Success 200 {object} OIDCLinkResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 502 {object} ErrorResponse
+// @Router /auth/oidc/{provider}/link [post]
+func (h *AuthHandler) LinkOIDCIdentity(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	provider, ok := h.oidcProvider(c)
+	if !ok {
+		return
+	}
+
+	authURL, ok := h.startOIDCFlow(c, provider, userID)
+	if !ok {
+		return
+	}
+
+	c.JSON(http.StatusOK, OIDCLinkResponse{AuthorizationURL: authURL})
+}
+
+// OIDCCallback godoc
+// @Summary OpenID Connect callback
+// @Description Complete sign-in after the provider redirects back. Users are matched by a linked identity, then by verified email; unknown emails get a new account. Users with two-factor authentication receive a challenge token instead. When the flow was started from the link endpoint, the identity is linked to that account.
+// @Tags Authentication
+// @Produce json
+// @Param provider path string true "Provider name"
+// @Param code query string true "Authorization code"
+// @Param state query string true "State"
+// @Success 200 {object} AuthResponse
+// @Success 202 {object} TwoFactorChallengeResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/oidc/{provider}/callback [get]
+func (h *AuthHandler) OIDCCallback(c *gin.Context) {
+	provider, ok := h.oidcProvider(c)
+	if !ok {
+		return
+	}
+
+	// The state cookie is single use
+	cookie, err := c.Cookie(oidcStateCookie)
+	h.setOIDCStateCookie(c, "", -1)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Sign-in session not found or expired"})
+		return
+	}
+	state, err := auth.ValidateOIDCState(cookie)
+	if err != nil || state.Provider != provider.Config.Name ||
+		subtle.ConstantTimeCompare([]byte(state.State), []byte(c.Query("state"))) != 1 {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid sign-in state"})
+		return
+	}
+
+	if providerError := c.Query("error"); providerError != "" {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Provider sign-in failed: " + providerError})
+		return
+	}
+	code := c.Query("code")
+	if code == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Authorization code is required"})
+		return
+	}
+
+	identity, err := provider.Exchange(c.Request.Context(), code, state.CodeVerifier, state.Nonce)
+	if err != nil {
+		log.Printf("OIDC code exchange with %s failed: %v", provider.Config.Name, err)
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Provider sign-in could not be verified"})
+		return
+	}
+
+	if state.LinkUserID != 0 {
+		h.linkOIDCIdentity(c, state.LinkUserID, provider.Config.Name, identity)
+		return
+	}
+
+	user, status, message := h.findOrCreateOIDCUser(provider.Config.Name, identity)
+	if user == nil {
+		c.JSON(status, ErrorResponse{Error: message})
+		return
+	}
+
+	// Two-factor authentication still applies to external sign-in
+	if user.TOTPEnabled {
+		h.respondWithChallenge(c, user)
+		return
+	}
+
+	h.completeLogin(c, user, provider.Config.Name)
+}
+
+// findOrCreateOIDCUser resolves the account for an external identity. On failure
+// the user is nil and status and message describe the error response.
+func (h *AuthHandler) findOrCreateOIDCUser(provider string, identity *oidc.Identity) (*models.User, int, string) {
+	// Already linked
+	var link models.UserIdentity
+	err := h.DB.Where("provider = ? AND subject = ?", provider, identity.Subject).First(&link).Error
+	if err == nil {
+		var user models.User
+		if err := h.DB.First(&user, link.UserID).Error; err != nil {
+			return nil, http.StatusInternalServerError, "Failed to fetch user"
+		}
+		return &user, 0, ""
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, http.StatusInternalServerError, "Failed to fetch identity"
+	}
+
+	// Matching by email is only safe when the provider vouches for it
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, http.StatusUnauthorized, "The provider did not return a verified email. Sign in with your password and link this provider instead."
+	}
+
+	var user models.User
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("email = ?", identity.Email).First(&user).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			name := identity.Name
+			if name == "" {
+				name = strings.Split(identity.Email, "@")[0]
+			}
+			// No password: the account signs in through the provider until one is set at /users/me/password
+			user = models.User{Name: name, Email: identity.Email, EmailVerified: true}
+			err = tx.Create(&user).Error
+		} else if err == nil && !user.EmailVerified {
+			// Whoever registered this address never proved they own it
+			return errUnverifiedAccount
+		}
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&models.UserIdentity{
+			UserID:   user.ID,
+			Provider: provider,
+			Subject:  identity.Subject,
+			Email:    identity.Email,
+		}).Error
+	})
+	if errors.Is(err, errUnverifiedAccount) {
+		return nil, http.StatusConflict, "An unverified account already uses this email. Verify it or sign in with your password, then link this provider."
+	}
+	if err != nil {
+		return nil, http.StatusInternalServerError, "Failed to sign in"
+	}
+
+	return &user, 0, ""
+}
+
+var errUnverifiedAccount = errors.New("account email is not verified")
+
+func (h *AuthHandler) linkOIDCIdentity(c *gin.Context, userID uint, provider string, identity *oidc.Identity) {
+	var existing models.UserIdentity
+	err := h.DB.Where("provider = ? AND subject = ?", provider, identity.Subject).First(&existing).Error
+	if err == nil {
+		if existing.UserID == userID {
+			c.JSON(http.StatusOK, MessageResponse{Message: "Identity already linked"})
+		} else {
+			c.JSON(http.StatusConflict, ErrorResponse{Error: "This identity is linked to another account"})
+		}
+		return
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to fetch identity"})
+		return
+	}
+
+	if err := h.DB.Create(&models.UserIdentity{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to link identity"})
+		return
+	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventIdentityLinked, UserID: userID, ActorID: userID, Details: "Linked " + provider})
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Identity linked successfully"})
+}
+
+// oidcProvider looks up the :provider path parameter, responding with 404 if it isn't configured
+func (h *AuthHandler) oidcProvider(c *gin.Context) (*oidc.Provider, bool) {
+	provider, ok := h.OIDCProviders[c.Param("provider")]
+	if !ok {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "Unknown sign-in provider"})
+		return nil, false
+	}
+	return provider, true
+}
+
+// startOIDCFlow stores a fresh state, nonce and PKCE verifier in the state cookie and
+// returns the provider's authorization URL
+func (h *AuthHandler) startOIDCFlow(c *gin.Context, provider *oidc.Provider, linkUserID uint) (string, bool) {
+	var values [3]string
+	for i := range values {
+		value, err := oidc.RandomString()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to start sign-in"})
+			return "", false
+		}
+		values[i] = value
+	}
+	state, nonce, verifier := values[0], values[1], values[2]
+
+	authURL, err := provider.AuthCodeURL(c.Request.Context(), state, nonce, verifier)
+	if err != nil {
+		log.Printf("OIDC discovery for %s failed: %v", provider.Config.Name, err)
+		c.JSON(http.StatusBadGateway, ErrorResponse{Error: "Sign-in provider is unavailable"})
+		return "", false
+	}
+
+	cookie, err := auth.GenerateOIDCState(auth.OIDCStateClaims{
+		Provider:     provider.Config.Name,
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		LinkUserID:   linkUserID,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to start sign-in"})
+		return "", false
+	}
+	h.setOIDCStateCookie(c, cookie, int(auth.OIDCStateDuration.Seconds()))
+
+	return authURL, true
+}
+
+// setOIDCStateCookie always uses SameSite=Lax: the callback is a cross-site
+// navigation from the provider, which Strict cookies would not be sent with
+func (h *AuthHandler) setOIDCStateCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, value, maxAge, "/api/auth/oidc", "", os.Getenv("ENV") == "production", true)
+}
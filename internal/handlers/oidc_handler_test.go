@@ -0,0 +1,292 @@
+// internal/handlers/oidc_handler_test.go
+package handlers_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/jedi116/kaizen-api/internal/handlers"
+	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/oidc"
+	"github.com/jedi116/kaizen-api/internal/oidc/oidctest"
+)
+
+// startOIDCTest runs the mock issuer and the API with it registered as "mock"
+func startOIDCTest(t *testing.T) (*testAPI, *oidctest.Issuer) {
+	t.Helper()
+	issuer, issuerServer, err := oidctest.Start("kaizen", "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(issuerServer.Close)
+	issuer.User.Email = uniqueEmail("oidc")
+
+	provider := oidc.NewProvider(oidc.ProviderConfig{
+		Name:         "mock",
+		Issuer:       issuer.URL,
+		ClientID:     "kaizen",
+		ClientSecret: "secret",
+	})
+	api := startTestAPI(t, map[string]*oidc.Provider{"mock": provider})
+	provider.Config.RedirectURL = api.URL + "/api/auth/oidc/mock/callback"
+
+	return api, issuer
+}
+
+// oidcSignIn follows the whole flow from the login endpoint through the
+// issuer back to the callback
+func oidcSignIn(t *testing.T, api *testAPI, client *http.Client) (int, handlers.AuthResponse) {
+	t.Helper()
+	var response handlers.AuthResponse
+	status := do(t, client, http.MethodGet, api.URL+"/api/auth/oidc/mock/login", "", nil, &response)
+	return status, response
+}
+
+// oidcCallbackURL starts a sign-in with client and returns the callback URL the
+// issuer redirects back to, without visiting it
+func oidcCallbackURL(t *testing.T, api *testAPI, client *http.Client) *url.URL {
+	t.Helper()
+	client = noRedirects(client)
+
+	resp, err := client.Get(api.URL + "/api/auth/oidc/mock/login")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("login returned %d, want 302", resp.StatusCode)
+	}
+
+	resp, err = client.Get(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusFound {
+		t.Fatalf("issuer returned %d, want 302", resp.StatusCode)
+	}
+
+	callback, err := url.Parse(resp.Header.Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return callback
+}
+
+func register(t *testing.T, api *testAPI, email string) handlers.AuthResponse {
+	t.Helper()
+	var response handlers.AuthResponse
+	status := do(t, newClient(t), http.MethodPost, api.URL+"/api/auth/register", "", handlers.RegisterRequest{
+		Name:     "Test User",
+		Email:    email,
+		Password: "staple-orbit-lantern-42",
+	}, &response)
+	if status != http.StatusCreated {
+		t.Fatalf("register returned %d", status)
+	}
+	return response
+}
+
+func TestOIDCSignInCreatesAccount(t *testing.T) {
+	api, issuer := startOIDCTest(t)
+
+	status, first := oidcSignIn(t, api, newClient(t))
+	if status != http.StatusOK {
+		t.Fatalf("sign-in returned %d, want 200", status)
+	}
+	if first.User.Email != issuer.User.Email || !first.User.EmailVerified || first.AccessToken == "" {
+		t.Fatalf("unexpected sign-in response %+v", first)
+	}
+
+	var user models.User
+	if err := api.DB.First(&user, first.User.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.HasPassword() {
+		t.Error("account created through a provider has a password")
+	}
+
+	var link models.UserIdentity
+	if err := api.DB.Where("provider = ? AND subject = ?", "mock", issuer.User.Subject).First(&link).Error; err != nil {
+		t.Fatalf("identity not linked: %v", err)
+	}
+	if link.UserID != user.ID {
+		t.Errorf("identity linked to user %d, want %d", link.UserID, user.ID)
+	}
+
+	// Signing in again finds the account through the linked identity
+	status, second := oidcSignIn(t, api, newClient(t))
+	if status != http.StatusOK || second.User.ID != user.ID {
+		t.Fatalf("second sign-in returned %d for user %d, want 200 for user %d", status, second.User.ID, user.ID)
+	}
+}
+
+func TestOIDCSignInMatchesVerifiedEmail(t *testing.T) {
+	api, issuer := startOIDCTest(t)
+
+	registered := register(t, api, issuer.User.Email)
+
+	// An unverified account is not taken over by whoever controls the provider account
+	status, _ := oidcSignIn(t, api, newClient(t))
+	if status != http.StatusConflict {
+		t.Fatalf("sign-in to unverified account returned %d, want 409", status)
+	}
+
+	if err := api.DB.Model(&models.User{}).Where("id = ?", registered.User.ID).Update("email_verified", true).Error; err != nil {
+		t.Fatal(err)
+	}
+	status, response := oidcSignIn(t, api, newClient(t))
+	if status != http.StatusOK || response.User.ID != registered.User.ID {
+		t.Fatalf("sign-in returned %d for user %d, want 200 for user %d", status, response.User.ID, registered.User.ID)
+	}
+}
+
+func TestOIDCSignInRequiresVerifiedProviderEmail(t *testing.T) {
+	api, issuer := startOIDCTest(t)
+	issuer.User.EmailVerified = false
+
+	status, _ := oidcSignIn(t, api, newClient(t))
+	if status != http.StatusUnauthorized {
+		t.Fatalf("sign-in with unverified provider email returned %d, want 401", status)
+	}
+
+	var count int64
+	api.DB.Model(&models.User{}).Where("email = ?", issuer.User.Email).Count(&count)
+	if count != 0 {
+		t.Error("account created for an unverified provider email")
+	}
+}
+
+func TestOIDCLinkIdentity(t *testing.T) {
+	api, issuer := startOIDCTest(t)
+	issuer.User.EmailVerified = false // Linking doesn't depend on the provider's email
+
+	registered := register(t, api, uniqueEmail("link"))
+	client := newClient(t)
+
+	var link handlers.OIDCLinkResponse
+	status := do(t, client, http.MethodPost, api.URL+"/api/auth/oidc/mock/link", registered.AccessToken, nil, &link)
+	if status != http.StatusOK || link.AuthorizationURL == "" {
+		t.Fatalf("link returned %d, want 200 with an authorization URL", status)
+	}
+
+	var message handlers.MessageResponse
+	status = do(t, client, http.MethodGet, link.AuthorizationURL, "", nil, &message)
+	if status != http.StatusOK {
+		t.Fatalf("link callback returned %d (%s), want 200", status, message.Message)
+	}
+
+	var identity models.UserIdentity
+	if err := api.DB.Where("provider = ? AND subject = ?", "mock", issuer.User.Subject).First(&identity).Error; err != nil {
+		t.Fatalf("identity not linked: %v", err)
+	}
+	if identity.UserID != registered.User.ID {
+		t.Errorf("identity linked to user %d, want %d", identity.UserID, registered.User.ID)
+	}
+
+	// The linked identity now signs in to the account
+	status, response := oidcSignIn(t, api, newClient(t))
+	if status != http.StatusOK || response.User.ID != registered.User.ID {
+		t.Fatalf("sign-in returned %d for user %d, want 200 for user %d", status, response.User.ID, registered.User.ID)
+	}
+
+	// Another account can't claim the same identity
+	other := register(t, api, uniqueEmail("other"))
+	client = newClient(t)
+	status = do(t, client, http.MethodPost, api.URL+"/api/auth/oidc/mock/link", other.AccessToken, nil, &link)
+	if status != http.StatusOK {
+		t.Fatalf("link returned %d, want 200", status)
+	}
+	status = do(t, client, http.MethodGet, link.AuthorizationURL, "", nil, nil)
+	if status != http.StatusConflict {
+		t.Fatalf("linking an identity of another account returned %d, want 409", status)
+	}
+}
+
+func TestOIDCCallbackValidatesState(t *testing.T) {
+	api, _ := startOIDCTest(t)
+
+	t.Run("valid", func(t *testing.T) {
+		client := newClient(t)
+		callback := oidcCallbackURL(t, api, client)
+		if status := do(t, client, http.MethodGet, callback.String(), "", nil, nil); status != http.StatusOK {
+			t.Fatalf("callback returned %d, want 200", status)
+		}
+
+		// The state cookie is cleared, so the callback can't be replayed
+		if status := do(t, client, http.MethodGet, callback.String(), "", nil, nil); status != http.StatusBadRequest {
+			t.Fatalf("replayed callback returned %d, want 400", status)
+		}
+	})
+
+	t.Run("tampered state", func(t *testing.T) {
+		client := newClient(t)
+		callback := oidcCallbackURL(t, api, client)
+		query := callback.Query()
+		query.Set("state", query.Get("state")+"x")
+		callback.RawQuery = query.Encode()
+		if status := do(t, client, http.MethodGet, callback.String(), "", nil, nil); status != http.StatusBadRequest {
+			t.Fatalf("callback with tampered state returned %d, want 400", status)
+		}
+	})
+
+	t.Run("other browser", func(t *testing.T) {
+		callback := oidcCallbackURL(t, api, newClient(t))
+		if status := do(t, newClient(t), http.MethodGet, callback.String(), "", nil, nil); status != http.StatusBadRequest {
+			t.Fatalf("callback without the state cookie returned %d, want 400", status)
+		}
+	})
+
+	t.Run("provider error", func(t *testing.T) {
+		client := newClient(t)
+		callback := oidcCallbackURL(t, api, client)
+		query := callback.Query()
+		query.Del("code")
+		query.Set("error", "access_denied")
+		callback.RawQuery = query.Encode()
+		if status := do(t, client, http.MethodGet, callback.String(), "", nil, nil); status != http.StatusUnauthorized {
+			t.Fatalf("callback with a provider error returned %d, want 401", status)
+		}
+	})
+}
+
+func TestPasswordlessUserConfirmsBySigningIn(t *testing.T) {
+	api, _ := startOIDCTest(t)
+
+	status, signedIn := oidcSignIn(t, api, newClient(t))
+	if status != http.StatusOK {
+		t.Fatalf("sign-in returned %d, want 200", status)
+	}
+	client := newClient(t)
+
+	// Right after signing in, a password can be set without the current one
+	status = do(t, client, http.MethodPut, api.URL+"/api/users/me/password", signedIn.AccessToken,
+		handlers.ChangePasswordRequest{NewPassword: "staple-orbit-lantern-42"}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("setting a password returned %d, want 200", status)
+	}
+	if err := api.DB.Model(&models.User{}).Where("id = ?", signedIn.User.ID).Update("password", "").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	// A session that began long ago has to sign in again first
+	if err := api.DB.Model(&models.Session{}).Where("user_id = ?", signedIn.User.ID).
+		Update("created_at", time.Now().Add(-time.Hour)).Error; err != nil {
+		t.Fatal(err)
+	}
+	status = do(t, client, http.MethodDelete, api.URL+"/api/users/me", signedIn.AccessToken, handlers.DeleteAccountRequest{}, nil)
+	if status != http.StatusForbidden {
+		t.Fatalf("deleting from a stale session returned %d, want 403", status)
+	}
+
+	status, fresh := oidcSignIn(t, api, newClient(t))
+	if status != http.StatusOK {
+		t.Fatalf("sign-in returned %d, want 200", status)
+	}
+	status = do(t, client, http.MethodDelete, api.URL+"/api/users/me", fresh.AccessToken, handlers.DeleteAccountRequest{}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("deleting from a fresh session returned %d, want 200", status)
+	}
+}
@@ -0,0 +1,56 @@
+// internal/handlers/reauth.go
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+var (
+	errPasswordRequired = errors.New("password is required")
+	errWrongPassword    = errors.New("password is incorrect")
+	errReauthRequired   = errors.New("sign in again to confirm this change")
+)
+
+// confirmIdentity checks that a sensitive change comes from the account owner.
+// Users with a password must give it. Users without one, who sign in through a
+// provider or a link, must instead have signed in within auth.ReauthWindow.
+func confirmIdentity(c *gin.Context, db *gorm.DB, user *models.User, password string) error {
+	if user.HasPassword() {
+		if password == "" {
+			return errPasswordRequired
+		}
+		if !auth.CheckPassword(user.Password, password) {
+			return errWrongPassword
+		}
+		return nil
+	}
+
+	recent, err := auth.SessionStartedWithin(user.ID, auth.GetSessionID(c), auth.ReauthWindow, db)
+	if err != nil {
+		return err
+	}
+	if !recent {
+		return errReauthRequired
+	}
+	return nil
+}
+
+// respondIdentityError answers a request that failed confirmIdentity for a
+// reason other than a wrong password
+func respondIdentityError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, errPasswordRequired):
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Password is required"})
+	case errors.Is(err, errReauthRequired):
+		c.JSON(http.StatusForbidden, ErrorResponse{Error: "Sign in again to confirm this change"})
+	default:
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to confirm your identity"})
+	}
+}
@@ -1,6 +1,15 @@
 // internal/handlers/response.go
 package handlers
 
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/jedi116/kaizen-api/internal/auth"
+)
+
 type ErrorResponse struct {
 	Error string `json:"error" example:"Something went wrong"`
 }
@@ -8,3 +17,31 @@ type ErrorResponse struct {
 type MessageResponse struct {
 	Message string `json:"message" example:"Success"`
 }
+
+// FieldError explains why one request field was rejected
+type FieldError struct {
+	Field   string `json:"field" example:"password"`
+	Code    string `json:"code" example:"too_weak"`
+	Message string `json:"message" example:"Password is too easy to guess: common words and passwords are easy to guess"`
+}
+
+// ValidationErrorResponse lists the reasons each rejected field was rejected
+type ValidationErrorResponse struct {
+	Error  string       `json:"error" example:"Password does not meet the requirements"`
+	Fields []FieldError `json:"fields"`
+}
+
+// respondPasswordError reports a password that failed auth.ValidatePassword
+func respondPasswordError(c *gin.Context, field string, err error) {
+	var policyErr *auth.PasswordPolicyError
+	if !errors.As(err, &policyErr) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	response := ValidationErrorResponse{Error: "Password does not meet the requirements"}
+	for _, violation := range policyErr.Violations {
+		response.Fields = append(response.Fields, FieldError{Field: field, Code: violation.Code, Message: violation.Message})
+	}
+	c.JSON(http.StatusBadRequest, response)
+}
@@ -0,0 +1,194 @@
+// internal/handlers/server_test.go
+package handlers_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/jedi116/kaizen-api/internal/auth"
+	kaizenhttp "github.com/jedi116/kaizen-api/internal/http"
+	"github.com/jedi116/kaizen-api/internal/mail"
+	"github.com/jedi116/kaizen-api/internal/oidc"
+)
+
+var configureKeys sync.Once
+
+// testAPI is the API served on an httptest server for one test
+type testAPI struct {
+	*httptest.Server
+	DB     *gorm.DB
+	Outbox *mail.OutboxMailer
+}
+
+// startTestAPI serves the API against TEST_DATABASE_URL (a migrated database).
+// Everything a test writes happens in a transaction that is rolled back when it ends.
+func startTestAPI(t *testing.T, providers map[string]*oidc.Provider) *testAPI {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	configureKeys.Do(func() {
+		key, err := auth.GenerateEd25519Key("test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		ks, err := auth.NewKeySet([]*auth.JWTKey{key}, key.ID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		auth.ConfigureKeys(ks)
+		gin.SetMode(gin.TestMode)
+	})
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := db.Begin()
+	t.Cleanup(func() { tx.Rollback() })
+
+	outbox, err := mail.NewOutboxMailer(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	engine := gin.New()
+	if err := kaizenhttp.ConfigureTrustedProxies(engine); err != nil {
+		t.Fatal(err)
+	}
+	server := kaizenhttp.KaizenServer{
+		GinEngine:     engine,
+		DB:            tx,
+		Mailer:        outbox,
+		OIDCProviders: providers,
+	}
+	server.RegisterRoutes()
+
+	ts := httptest.NewServer(server.GinEngine)
+	t.Cleanup(ts.Close)
+	return &testAPI{Server: ts, DB: tx, Outbox: outbox}
+}
+
+// newClient returns a client with its own cookie jar, like a separate browser
+func newClient(t *testing.T) *http.Client {
+	t.Helper()
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &http.Client{Jar: jar, Timeout: 10 * time.Second}
+}
+
+// noRedirects stops a client following redirects, so each hop can be inspected
+func noRedirects(client *http.Client) *http.Client {
+	copied := *client
+	copied.CheckRedirect = func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+	return &copied
+}
+
+// do sends a request with an optional JSON body and bearer token and decodes
+// the JSON response into out, returning the status code
+func do(t *testing.T, client *http.Client, method, url, token string, body, out interface{}) int {
+	t.Helper()
+	headers := map[string]string{}
+	if token != "" {
+		headers["Authorization"] = "Bearer " + token
+	}
+	return doWithHeaders(t, client, method, url, headers, body, out)
+}
+
+// doWithHeaders is do with arbitrary request headers instead of a bearer token
+func doWithHeaders(t *testing.T, client *http.Client, method, url string, headers map[string]string, body, out interface{}) int {
+	t.Helper()
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			t.Fatalf("%s %s: decoding %q: %v", method, url, data, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+// uniqueEmail returns an address no other test run uses
+func uniqueEmail(name string) string {
+	return fmt.Sprintf("%s-%d@example.com", name, time.Now().UnixNano())
+}
+
+// emailedToken waits for the count-th email to email with a link to path and
+// returns the link's token
+func emailedToken(t *testing.T, api *testAPI, path, email string, count int) string {
+	t.Helper()
+	pattern := regexp.MustCompile(`https?://\S+` + regexp.QuoteMeta(path) + `\?\S+`)
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		var links []string
+		messages, err := api.Outbox.Messages()
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, msg := range messages {
+			if link := pattern.FindString(msg.Body); link != "" && strings.EqualFold(msg.To, email) {
+				links = append(links, link)
+			}
+		}
+		if len(links) >= count {
+			link, err := url.Parse(links[count-1])
+			if err != nil {
+				t.Fatal(err)
+			}
+			return link.Query().Get("token")
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("email %d to %s with a %s link was not sent", count, email, path)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
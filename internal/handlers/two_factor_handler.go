@@ -0,0 +1,406 @@
+// internal/handlers/two_factor_handler.go
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/audit"
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+const recoveryCodeCount = 10
+
+type TwoFactorChallengeResponse struct {
+	TwoFactorRequired bool   `json:"two_factor_required" example:"true"`
+	ChallengeToken    string `json:"challenge_token"`
+}
+
+type TwoFactorEnrollResponse struct {
+	Secret     string `json:"secret" example:"JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP"`
+	OTPAuthURI string `json:"otpauth_uri" example:"otpauth://totp/Kaizen:john@example.com?secret=JBSWY3DPEHPK3PXPJBSWY3DPEHPK3PXP&issuer=Kaizen"`
+}
+
+type TwoFactorEnrollRequest struct {
+	Password string `json:"password" example:"password123"` // Not needed by users without a password who signed in within the last 10 minutes
+}
+
+type TwoFactorConfirmRequest struct {
+	Password string `json:"password" example:"password123"` // Not needed by users without a password who signed in within the last 10 minutes
+	Code     string `json:"code" binding:"required" example:"123456"`
+}
+
+type RecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type TwoFactorDisableRequest struct {
+	Password string `json:"password" example:"password123"` // Not needed by users without a password who signed in within the last 10 minutes
+	Code     string `json:"code" binding:"required" example:"123456"`
+}
+
+type TwoFactorVerifyRequest struct {
+	ChallengeToken string `json:"challenge_token" binding:"required"`
+	Code           string `json:"code" binding:"required" example:"123456"`
+}
+
+// EnrollTwoFactor godoc
+// @Summary Start two-factor enrollment
+// @Description Generate a TOTP secret for the current user. Two-factor authentication is enabled once a code is confirmed. Requires the password, or for users without one, a sign-in within the last 10 minutes.
+// @Tags Two-Factor Authentication
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body TwoFactorEnrollRequest true "Password"
+// @Success 200 {object} TwoFactorEnrollResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "No password and not signed in recently"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/2fa/enroll [post]
+func (h *AuthHandler) EnrollTwoFactor(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var req TwoFactorEnrollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := h.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	if user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Two-factor authentication is already enabled"})
+		return
+	}
+
+	if err := confirmIdentity(c, h.DB, &user, req.Password); errors.Is(err, errWrongPassword) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid password"})
+		return
+	} else if err != nil {
+		respondIdentityError(c, err)
+		return
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to generate secret"})
+		return
+	}
+
+	sealed, err := auth.SealTOTPSecret(secret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to start enrollment"})
+		return
+	}
+
+	if err := h.DB.Model(&user).Updates(map[string]interface{}{"totp_secret": sealed, "totp_last_step": 0}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to start enrollment"})
+		return
+	}
+
+	c.JSON(http.StatusOK, TwoFactorEnrollResponse{
+		Secret:     secret,
+		OTPAuthURI: auth.TOTPURI(secret, user.Email),
+	})
+}
+
+// ConfirmTwoFactor godoc
+// @Summary Confirm two-factor enrollment
+// @Description Enable two-factor authentication with a code from the authenticator app. Returns one-time recovery codes, shown only once. Requires the password, or for users without one, a sign-in within the last 10 minutes. Every other session is signed out.
+// @Tags Two-Factor Authentication
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body TwoFactorConfirmRequest true "Password and code from the authenticator app"
+// @Success 200 {object} RecoveryCodesResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "No password and not signed in recently"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/2fa/confirm [post]
+func (h *AuthHandler) ConfirmTwoFactor(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var req TwoFactorConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := h.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	if user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Two-factor authentication is already enabled"})
+		return
+	}
+	if user.TOTPSecret == "" {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Start enrollment first"})
+		return
+	}
+
+	if err := confirmIdentity(c, h.DB, &user, req.Password); errors.Is(err, errWrongPassword) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid password"})
+		return
+	} else if err != nil {
+		respondIdentityError(c, err)
+		return
+	}
+
+	secret, err := auth.OpenTOTPSecret(user.TOTPSecret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify code"})
+		return
+	}
+
+	step, ok := auth.ValidateTOTP(secret, req.Code, time.Now(), user.TOTPLastStep)
+	if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid code"})
+		return
+	}
+
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to generate recovery codes"})
+		return
+	}
+
+	err = h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+
+		recoveryCodes := make([]models.RecoveryCode, len(codes))
+		for i, code := range codes {
+			recoveryCodes[i] = models.RecoveryCode{UserID: user.ID, CodeHash: auth.HashRecoveryCode(code)}
+		}
+		if err := tx.Create(&recoveryCodes).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&user).Updates(map[string]interface{}{"totp_enabled": true, "totp_last_step": step}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to enable two-factor authentication"})
+		return
+	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventTwoFactorEnabled, UserID: user.ID})
+
+	// Sessions that began with the password alone have to sign in again with a code
+	if err := auth.RevokeOtherSessions(user.ID, auth.GetSessionID(c), h.DB); err != nil {
+		log.Printf("failed to revoke other sessions after enabling two-factor authentication for user %d: %v", user.ID, err)
+	}
+
+	c.JSON(http.StatusOK, RecoveryCodesResponse{RecoveryCodes: codes})
+}
+
+// DisableTwoFactor godoc
+// @Summary Disable two-factor authentication
+// @Description Turn off two-factor authentication. Requires the password and a current code or recovery code. Users without a password must have signed in within the last 10 minutes instead.
+// @Tags Two-Factor Authentication
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body TwoFactorDisableRequest true "Password and code"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "No password and not signed in recently"
+// @Failure 500 {object} ErrorResponse
+// @Router /auth/2fa/disable [post]
+func (h *AuthHandler) DisableTwoFactor(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var req TwoFactorDisableRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := h.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	if !user.TOTPEnabled {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Two-factor authentication is not enabled"})
+		return
+	}
+
+	if err := confirmIdentity(c, h.DB, &user, req.Password); errors.Is(err, errWrongPassword) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid password"})
+		return
+	} else if err != nil {
+		respondIdentityError(c, err)
+		return
+	}
+
+	if ok, err := h.checkSecondFactor(&user, req.Code); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify code"})
+		return
+	} else if !ok {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Invalid code"})
+		return
+	}
+
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", user.ID).Delete(&models.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&user).Updates(map[string]interface{}{
+			"totp_enabled":   false,
+			"totp_secret":    "",
+			"totp_last_step": 0,
+		}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to disable two-factor authentication"})
+		return
+	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventTwoFactorDisabled, UserID: user.ID})
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Two-factor authentication disabled"})
+}
+
+// VerifyTwoFactor godoc
+// @Summary Complete a two-factor login
+// @Description Exchange the challenge token from /auth/login and a TOTP or recovery code for a token pair. A challenge accepts at most five codes and signs in once.
+// @Tags Two-Factor Authentication
+// @Accept json
+// @Produce json
+// @Param request body TwoFactorVerifyRequest true "Challenge token and code"
+// @Success 200 {object} AuthResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Failure 429 {object} ErrorResponse
+// @Router /auth/2fa/verify [post]
+func (h *AuthHandler) VerifyTwoFactor(c *gin.Context) {
+	var req TwoFactorVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	// Every code tried counts against the challenge, whatever the outcome
+	challenge, err := auth.AttemptTwoFactorChallenge(req.ChallengeToken, h.DB)
+	if errors.Is(err, auth.ErrInvalidOneTimeToken) {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid or expired challenge"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify code"})
+		return
+	}
+
+	var user models.User
+	if err := h.DB.First(&user, challenge.UserID).Error; err != nil || !user.TOTPEnabled {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid or expired challenge"})
+		return
+	}
+
+	// Wrong codes count towards the same lockout as wrong passwords
+	if !h.reserveLoginAttempt(c, user.Email) {
+		return
+	}
+
+	if ok, err := h.checkSecondFactor(&user, req.Code); err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify code"})
+		return
+	} else if !ok {
+		audit.Record(c, h.DB, audit.Event{Type: audit.EventTwoFactorVerify, Failed: true, UserID: user.ID, Details: "Invalid code"})
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid code"})
+		return
+	}
+	h.releaseLoginAttempt(c)
+
+	// A challenge signs in once, even if its code could be replayed
+	if err := auth.CompleteTwoFactorChallenge(challenge, h.DB); errors.Is(err, auth.ErrInvalidOneTimeToken) {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "Invalid or expired challenge"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to verify code"})
+		return
+	}
+
+	h.completeLogin(c, &user, "two-factor code")
+}
+
+// respondWithChallenge answers a login by a user with two-factor
+// authentication with a challenge token instead of tokens
+func (h *AuthHandler) respondWithChallenge(c *gin.Context, user *models.User) {
+	challenge, err := auth.IssueTwoFactorChallenge(user.ID, h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to generate tokens"})
+		return
+	}
+	c.JSON(http.StatusAccepted, TwoFactorChallengeResponse{
+		TwoFactorRequired: true,
+		ChallengeToken:    challenge,
+	})
+}
+
+// checkSecondFactor accepts either a TOTP code or an unused recovery code.
+// Accepted codes are burned so they cannot be replayed.
+func (h *AuthHandler) checkSecondFactor(user *models.User, code string) (bool, error) {
+	secret, err := auth.OpenTOTPSecret(user.TOTPSecret)
+	if err != nil {
+		return false, err
+	}
+
+	if step, ok := auth.ValidateTOTP(secret, code, time.Now(), user.TOTPLastStep); ok {
+		updates := map[string]interface{}{"totp_last_step": step}
+		// Secrets stored before encryption was configured are encrypted on first use
+		if !auth.IsSealedTOTPSecret(user.TOTPSecret) {
+			if updates["totp_secret"], err = auth.SealTOTPSecret(secret); err != nil {
+				return false, err
+			}
+		}
+
+		// Conditional update so two requests can't both use the same step
+		result := h.DB.Model(&models.User{}).
+			Where("id = ? AND totp_last_step < ?", user.ID, step).
+			Updates(updates)
+		if result.Error != nil {
+			return false, result.Error
+		}
+		user.TOTPLastStep = step
+		return result.RowsAffected == 1, nil
+	}
+
+	result := h.DB.Model(&models.RecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", user.ID, auth.HashRecoveryCode(code)).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
@@ -0,0 +1,161 @@
+// internal/handlers/two_factor_handler_test.go
+package handlers_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/handlers"
+)
+
+// totpCode returns the authenticator code for secret at now
+func totpCode(t *testing.T, secret string, now time.Time) string {
+	t.Helper()
+	code, err := auth.TOTPCode(secret, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+// enableTwoFactor enrolls and confirms two-factor authentication for the user
+// of token, returning the secret and recovery codes
+func enableTwoFactor(t *testing.T, api *testAPI, token string) (string, []string) {
+	t.Helper()
+	client := newClient(t)
+
+	var enrolled handlers.TwoFactorEnrollResponse
+	status := do(t, client, http.MethodPost, api.URL+"/api/auth/2fa/enroll", token,
+		handlers.TwoFactorEnrollRequest{Password: "staple-orbit-lantern-42"}, &enrolled)
+	if status != http.StatusOK {
+		t.Fatalf("enrolling returned %d, want 200", status)
+	}
+
+	var confirmed handlers.RecoveryCodesResponse
+	status = do(t, client, http.MethodPost, api.URL+"/api/auth/2fa/confirm", token, handlers.TwoFactorConfirmRequest{
+		Password: "staple-orbit-lantern-42",
+		Code:     totpCode(t, enrolled.Secret, time.Now()),
+	}, &confirmed)
+	if status != http.StatusOK {
+		t.Fatalf("confirming returned %d, want 200", status)
+	}
+	return enrolled.Secret, confirmed.RecoveryCodes
+}
+
+func TestEnrollTwoFactorRequiresPassword(t *testing.T) {
+	api := startTestAPI(t, nil)
+	token := register(t, api, uniqueEmail("enroll")).AccessToken
+	client := newClient(t)
+	enrollURL := api.URL + "/api/auth/2fa/enroll"
+
+	if status := do(t, client, http.MethodPost, enrollURL, token, handlers.TwoFactorEnrollRequest{}, nil); status != http.StatusBadRequest {
+		t.Errorf("enrolling without a password returned %d, want 400", status)
+	}
+	if status := do(t, client, http.MethodPost, enrollURL, token, handlers.TwoFactorEnrollRequest{Password: "wrong"}, nil); status != http.StatusBadRequest {
+		t.Errorf("enrolling with a wrong password returned %d, want 400", status)
+	}
+
+	var enrolled handlers.TwoFactorEnrollResponse
+	status := do(t, client, http.MethodPost, enrollURL, token, handlers.TwoFactorEnrollRequest{Password: "staple-orbit-lantern-42"}, &enrolled)
+	if status != http.StatusOK {
+		t.Fatalf("enrolling returned %d, want 200", status)
+	}
+	if enrolled.Secret == "" || enrolled.OTPAuthURI == "" {
+		t.Errorf("enrolling returned %+v, want a secret and URI", enrolled)
+	}
+}
+
+func TestConfirmTwoFactor(t *testing.T) {
+	api := startTestAPI(t, nil)
+	email := uniqueEmail("confirm")
+	token := register(t, api, email).AccessToken
+	var other handlers.AuthResponse
+	if status := login(t, api, email, &other); status != http.StatusOK {
+		t.Fatalf("signing in returned %d, want 200", status)
+	}
+	client := newClient(t)
+
+	var enrolled handlers.TwoFactorEnrollResponse
+	status := do(t, client, http.MethodPost, api.URL+"/api/auth/2fa/enroll", token,
+		handlers.TwoFactorEnrollRequest{Password: "staple-orbit-lantern-42"}, &enrolled)
+	if status != http.StatusOK {
+		t.Fatalf("enrolling returned %d, want 200", status)
+	}
+
+	confirmURL := api.URL + "/api/auth/2fa/confirm"
+	code := totpCode(t, enrolled.Secret, time.Now())
+	status = do(t, client, http.MethodPost, confirmURL, token, handlers.TwoFactorConfirmRequest{Password: "wrong", Code: code}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("confirming with a wrong password returned %d, want 400", status)
+	}
+	status = do(t, client, http.MethodPost, confirmURL, token, handlers.TwoFactorConfirmRequest{Password: "staple-orbit-lantern-42", Code: "000000"}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("confirming with a wrong code returned %d, want 400", status)
+	}
+
+	var confirmed handlers.RecoveryCodesResponse
+	status = do(t, client, http.MethodPost, confirmURL, token, handlers.TwoFactorConfirmRequest{Password: "staple-orbit-lantern-42", Code: code}, &confirmed)
+	if status != http.StatusOK {
+		t.Fatalf("confirming returned %d, want 200", status)
+	}
+	if len(confirmed.RecoveryCodes) != 10 {
+		t.Errorf("confirming returned %d recovery codes, want 10", len(confirmed.RecoveryCodes))
+	}
+
+	// Only the session that enabled it stays signed in
+	if status := do(t, client, http.MethodGet, api.URL+"/api/users/me", token, nil, nil); status != http.StatusOK {
+		t.Errorf("the confirming session got %d, want 200", status)
+	}
+	if status := do(t, client, http.MethodGet, api.URL+"/api/users/me", other.AccessToken, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("another session got %d after enabling two-factor, want 401", status)
+	}
+
+	// Signing in now takes a code as well
+	var challenge handlers.TwoFactorChallengeResponse
+	if status := login(t, api, email, &challenge); status != http.StatusAccepted || challenge.ChallengeToken == "" {
+		t.Fatalf("signing in returned %d, want 202 with a challenge", status)
+	}
+	status = do(t, client, http.MethodPost, api.URL+"/api/auth/2fa/verify", "", handlers.TwoFactorVerifyRequest{
+		ChallengeToken: challenge.ChallengeToken,
+		Code:           code,
+	}, nil)
+	if status != http.StatusUnauthorized {
+		t.Errorf("verifying with the code used to confirm returned %d, want 401", status)
+	}
+	var signedIn handlers.AuthResponse
+	status = do(t, client, http.MethodPost, api.URL+"/api/auth/2fa/verify", "", handlers.TwoFactorVerifyRequest{
+		ChallengeToken: challenge.ChallengeToken,
+		Code:           totpCode(t, enrolled.Secret, time.Now().Add(30*time.Second)),
+	}, &signedIn)
+	if status != http.StatusOK || signedIn.AccessToken == "" {
+		t.Errorf("verifying with the next code returned %d, want 200 with tokens", status)
+	}
+}
+
+func TestDisableTwoFactor(t *testing.T) {
+	api := startTestAPI(t, nil)
+	email := uniqueEmail("disable")
+	token := register(t, api, email).AccessToken
+	_, recoveryCodes := enableTwoFactor(t, api, token)
+	client := newClient(t)
+	disableURL := api.URL + "/api/auth/2fa/disable"
+
+	status := do(t, client, http.MethodPost, disableURL, token, handlers.TwoFactorDisableRequest{Password: "wrong", Code: recoveryCodes[0]}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("disabling with a wrong password returned %d, want 400", status)
+	}
+	status = do(t, client, http.MethodPost, disableURL, token, handlers.TwoFactorDisableRequest{Password: "staple-orbit-lantern-42", Code: "000000"}, nil)
+	if status != http.StatusBadRequest {
+		t.Errorf("disabling with a wrong code returned %d, want 400", status)
+	}
+	status = do(t, client, http.MethodPost, disableURL, token, handlers.TwoFactorDisableRequest{Password: "staple-orbit-lantern-42", Code: recoveryCodes[0]}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("disabling with a recovery code returned %d, want 200", status)
+	}
+
+	if status := login(t, api, email, nil); status != http.StatusOK {
+		t.Errorf("signing in after disabling returned %d, want 200", status)
+	}
+}
@@ -2,35 +2,96 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
+	"github.com/jedi116/kaizen-api/internal/audit"
 	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/mail"
 	"github.com/jedi116/kaizen-api/internal/models"
 )
 
 type UserHandler struct {
-	DB *gorm.DB
+	DB     *gorm.DB
+	Mailer mail.Mailer
 }
 
 type UpdateProfileRequest struct {
-	Name string `json:"name" binding:"required" example:"John Doe"`
+	Name         string `json:"name" binding:"required" example:"John Doe"`
+	BaseCurrency string `json:"base_currency" binding:"omitempty,iso4217" example:"USD"` // Currency reports are converted to; unchanged if empty
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" example:"password123"` // Not needed by users without a password who signed in within the last 10 minutes
+	NewPassword     string `json:"new_password" binding:"required" example:"staple-orbit-lantern"`
+}
+
+type ChangeEmailRequest struct {
+	NewEmail string `json:"new_email" binding:"required,email" example:"john.new@example.com"`
+	Password string `json:"password" example:"password123"` // Not needed by users without a password who signed in within the last 10 minutes
 }
 
 type CreateAPIKeyRequest struct {
-	Name      string     `json:"name" binding:"required" example:"Mobile App"`
-	ExpiresAt *time.Time `json:"expires_at" example:"2025-12-31T23:59:59Z"`
+	Name         string     `json:"name" binding:"required" example:"Mobile App"`
+	Scopes       []string   `json:"scopes" binding:"required,min=1,dive,oneof=journals:read journals:write categories:read categories:write accounts:read accounts:write budgets:read budgets:write" example:"journals:read,categories:read"`
+	ExpiresAt    *time.Time `json:"expires_at" example:"2025-12-31T23:59:59Z"`
+	AllowedCIDRs []string   `json:"allowed_cidrs" example:"203.0.113.0/24,2001:db8::/32"` // Optional; the key only works from these addresses
 }
 
+type UpdateAPIKeyRequest struct {
+	Name         string   `json:"name" binding:"required" example:"Mobile App"`
+	AllowedCIDRs []string `json:"allowed_cidrs" example:"203.0.113.0/24,2001:db8::/32"` // Replaces the allowlist; empty allows any address
+}
+
+type RotateAPIKeyRequest struct {
+	GracePeriodSeconds *int `json:"grace_period_seconds" binding:"omitempty,min=0" example:"3600"` // How long the old secret keeps working (default: server setting, max 7 days)
+}
+
+// APIKeyResponse is returned once on creation or rotation and is the only response that carries the full key
 type APIKeyResponse struct {
-	ID        uint       `json:"id"`
-	Name      string     `json:"name"`
-	Key       string     `json:"key"`
-	CreatedAt time.Time  `json:"created_at"`
-	ExpiresAt *time.Time `json:"expires_at"`
+	ID                   uint       `json:"id"`
+	Name                 string     `json:"name"`
+	Key                  string     `json:"key" example:"kz_1a2b3c4d5e6f_9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08"`
+	Scopes               []string   `json:"scopes" example:"journals:read,categories:read"`
+	AllowedCIDRs         []string   `json:"allowed_cidrs" example:"203.0.113.0/24"`
+	CreatedAt            time.Time  `json:"created_at"`
+	ExpiresAt            *time.Time `json:"expires_at"`
+	PreviousKeyExpiresAt *time.Time `json:"previous_key_expires_at,omitempty"` // After a rotation, when the old secret stops working
+}
+
+// APIKeyInfo describes an existing API key without revealing its secret
+type APIKeyInfo struct {
+	ID                   uint       `json:"id"`
+	Name                 string     `json:"name"`
+	KeyPrefix            string     `json:"key_prefix" example:"kz_1a2b3c4d5e6f..."`
+	Scopes               []string   `json:"scopes" example:"journals:read,categories:read"`
+	AllowedCIDRs         []string   `json:"allowed_cidrs" example:"203.0.113.0/24"`
+	IsActive             bool       `json:"is_active"`
+	CreatedAt            time.Time  `json:"created_at"`
+	LastUsedAt           *time.Time `json:"last_used_at"`
+	ExpiresAt            *time.Time `json:"expires_at"`
+	RotatedAt            *time.Time `json:"rotated_at"`
+	PreviousKeyExpiresAt *time.Time `json:"previous_key_expires_at"`
+}
+
+// APIKeyUsageResponse lists an API key's requests per day
+type APIKeyUsageResponse struct {
+	APIKeyID      uint               `json:"api_key_id"`
+	TotalRequests int64              `json:"total_requests"`
+	Days          []APIKeyDailyUsage `json:"days"`
+}
+
+type APIKeyDailyUsage struct {
+	Date     string `json:"date" example:"2026-10-16"`
+	Requests int64  `json:"requests" example:"1520"`
 }
 
 // GetProfile godoc
@@ -61,7 +122,7 @@ func (h *UserHandler) GetProfile(c *gin.Context) {
 
 // UpdateProfile godoc
 // @Summary Update user profile
-// @Description Update current user profile
+// @Description Update current user profile, including the base currency reports are converted to
 // @Tags Users
 // @Security BearerAuth
 // @Accept json
@@ -90,18 +151,165 @@ func (h *UserHandler) UpdateProfile(c *gin.Context) {
 	}
 
 	user.Name = req.Name
+	if req.BaseCurrency != "" {
+		user.BaseCurrency = req.BaseCurrency
+	}
 
 	if err := h.DB.Save(&user).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update profile"})
 		return
 	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventProfileUpdated, UserID: userID})
 
 	c.JSON(http.StatusOK, user)
 }
 
+// ChangePassword godoc
+// @Summary Change password
+// @Description Change the current user's password. Every other session is signed out; the current one stays signed in. Users without a password, who signed up through a provider, can set one without current_password within 10 minutes of signing in.
+// @Tags Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} MessageResponse
+// @Failure 400 {object} ValidationErrorResponse "Wrong current password or new password rejected by the password policy"
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "No password and not signed in recently"
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/password [put]
+func (h *UserHandler) ChangePassword(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := h.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	if err := confirmIdentity(c, h.DB, &user, req.CurrentPassword); errors.Is(err, errWrongPassword) {
+		audit.Record(c, h.DB, audit.Event{Type: audit.EventPasswordChanged, Failed: true, UserID: userID, Details: "Wrong current password"})
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Current password is incorrect"})
+		return
+	} else if err != nil {
+		respondIdentityError(c, err)
+		return
+	}
+	if err := auth.ValidatePassword(req.NewPassword, user.Name, user.Email); err != nil {
+		respondPasswordError(c, "new_password", err)
+		return
+	}
+	if req.NewPassword == req.CurrentPassword {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: auth.ErrPasswordUnchanged.Error()})
+		return
+	}
+
+	hashedPassword, err := auth.HashPassword(req.NewPassword)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to hash password"})
+		return
+	}
+
+	if err := h.DB.Model(&user).Update("password", hashedPassword).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to change password"})
+		return
+	}
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventPasswordChanged, UserID: userID})
+
+	// Sign out every other device, in case the old password was compromised
+	if err := auth.RevokeOtherSessions(userID, auth.GetSessionID(c), h.DB); err != nil {
+		log.Printf("failed to revoke other sessions after password change for user %d: %v", userID, err)
+	}
+
+	if err := h.Mailer.Send(c.Request.Context(), passwordChangedNotice(&user)); err != nil {
+		log.Printf("failed to send password change notice to user %d: %v", userID, err)
+	}
+
+	c.JSON(http.StatusOK, MessageResponse{Message: "Password changed successfully"})
+}
+
+// ChangeEmail godoc
+// @Summary Change email address
+// @Description Start changing the current user's email address. A confirmation link is sent to the new address, and the email is only changed once it is confirmed at /auth/confirm-email-change. Users without a password must have signed in within the last 10 minutes instead.
+// @Tags Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param request body ChangeEmailRequest true "New email and current password"
+// @Success 202 {object} MessageResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 403 {object} ErrorResponse "No password and not signed in recently"
+// @Failure 409 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/me/email [post]
+func (h *UserHandler) ChangeEmail(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var req ChangeEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var user models.User
+	if err := h.DB.First(&user, userID).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	if err := confirmIdentity(c, h.DB, &user, req.Password); errors.Is(err, errWrongPassword) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "Password is incorrect"})
+		return
+	} else if err != nil {
+		respondIdentityError(c, err)
+		return
+	}
+	if strings.EqualFold(req.NewEmail, user.Email) {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: "New email must be different from the current email"})
+		return
+	}
+
+	var existingUser models.User
+	if err := h.DB.Where("email = ?", req.NewEmail).First(&existingUser).Error; err == nil {
+		c.JSON(http.StatusConflict, ErrorResponse{Error: "Email already registered"})
+		return
+	}
+
+	token, err := auth.IssueEmailChangeToken(userID, req.NewEmail, h.DB)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to start email change"})
+		return
+	}
+
+	if err := h.Mailer.Send(c.Request.Context(), emailChangeEmail(&user, req.NewEmail, token)); err != nil {
+		log.Printf("failed to send email change confirmation to user %d: %v", userID, err)
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to send confirmation email"})
+		return
+	}
+
+	audit.Record(c, h.DB, audit.Event{Type: audit.EventEmailChangeRequested, UserID: userID, Details: "New address " + req.NewEmail})
+
+	c.JSON(http.StatusAccepted, MessageResponse{Message: "A confirmation link has been sent to the new email address"})
+}
+
 // CreateAPIKey godoc
 // @Summary Create API key
-// @Description Create a new API key for the user
+// @Description Create a new API key for the user. The full key is only returned in this response.
 // @Tags Users
 // @Security BearerAuth
 // @Accept json
@@ -129,8 +337,14 @@ func (h *UserHandler) CreateAPIKey(c *gin.Context) {
 		ExpiresAt: req.ExpiresAt,
 		IsActive:  true,
 	}
+	apiKey.SetScopes(req.Scopes)
+	if err := apiKey.SetAllowedCIDRs(req.AllowedCIDRs); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
 
-	if err := apiKey.GenerateKey(); err != nil {
+	key, err := apiKey.GenerateKey()
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to generate API key"})
 		return
 	}
@@ -139,14 +353,13 @@ func (h *UserHandler) CreateAPIKey(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to create API key"})
 		return
 	}
-
-	c.JSON(http.StatusCreated, APIKeyResponse{
-		ID:        apiKey.ID,
-		Name:      apiKey.Name,
-		Key:       apiKey.Key,
-		CreatedAt: apiKey.CreatedAt,
-		ExpiresAt: apiKey.ExpiresAt,
+	audit.Record(c, h.DB, audit.Event{
+		Type:    audit.EventAPIKeyCreated,
+		UserID:  userID,
+		Details: fmt.Sprintf("Key %d (%s) with scopes %s", apiKey.ID, apiKey.Name, strings.Join(apiKey.ScopeList(), ", ")),
 	})
+
+	c.JSON(http.StatusCreated, apiKeyResponse(&apiKey, key))
 }
 
 // ListAPIKeys godoc
@@ -155,7 +368,7 @@ func (h *UserHandler) CreateAPIKey(c *gin.Context) {
 // @Tags Users
 // @Security BearerAuth
 // @Produce json
-// @Success 200 {array} models.APIKey
+// @Success 200 {array} APIKeyInfo
 // @Router /users/api-keys [get]
 func (h *UserHandler) ListAPIKeys(c *gin.Context) {
 	userID, exists := auth.GetUserID(c)
@@ -170,7 +383,12 @@ func (h *UserHandler) ListAPIKeys(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, apiKeys)
+	keys := make([]APIKeyInfo, 0, len(apiKeys))
+	for _, apiKey := range apiKeys {
+		keys = append(keys, apiKeyInfo(&apiKey))
+	}
+
+	c.JSON(http.StatusOK, keys)
 }
 
 // DeleteAPIKey godoc
@@ -191,10 +409,238 @@ func (h *UserHandler) DeleteAPIKey(c *gin.Context) {
 
 	id := c.Param("id")
 
-	if err := h.DB.Where("id = ? AND user_id = ?", id, userID).Delete(&models.APIKey{}).Error; err != nil {
+	result := h.DB.Where("id = ? AND user_id = ?", id, userID).Delete(&models.APIKey{})
+	if result.Error != nil {
 		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to delete API key"})
 		return
 	}
+	if result.RowsAffected > 0 {
+		audit.Record(c, h.DB, audit.Event{Type: audit.EventAPIKeyDeleted, UserID: userID, Details: "Key " + id})
+	}
 
 	c.JSON(http.StatusOK, MessageResponse{Message: "API key deleted successfully"})
 }
+
+// UpdateAPIKey godoc
+// @Summary Update API key
+// @Description Rename an API key and replace its IP allowlist. Requests from addresses outside the allowlist are refused with 403.
+// @Tags Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "API Key ID"
+// @Param request body UpdateAPIKeyRequest true "API key details"
+// @Success 200 {object} APIKeyInfo
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/api-keys/{id} [put]
+func (h *UserHandler) UpdateAPIKey(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var req UpdateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	var apiKey models.APIKey
+	if err := h.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&apiKey).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "API key not found"})
+		return
+	}
+
+	apiKey.Name = req.Name
+	if err := apiKey.SetAllowedCIDRs(req.AllowedCIDRs); err != nil {
+		c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	if err := h.DB.Model(&apiKey).Updates(map[string]interface{}{
+		"name":          apiKey.Name,
+		"allowed_cidrs": apiKey.AllowedCIDRs,
+	}).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to update API key"})
+		return
+	}
+	audit.Record(c, h.DB, audit.Event{
+		Type:    audit.EventAPIKeyUpdated,
+		UserID:  userID,
+		Details: fmt.Sprintf("Key %d (%s) allowed from %s", apiKey.ID, apiKey.Name, allowedCIDRsDetail(&apiKey)),
+	})
+
+	c.JSON(http.StatusOK, apiKeyInfo(&apiKey))
+}
+
+// RotateAPIKey godoc
+// @Summary Rotate API key
+// @Description Issue a new secret for an API key, keeping its ID, scopes, allowlist and usage history. The old secret keeps working for the grace period so clients can be updated; a secret replaced by an earlier rotation stops working at once. The new key is only returned in this response.
+// @Tags Users
+// @Security BearerAuth
+// @Accept json
+// @Produce json
+// @Param id path int true "API Key ID"
+// @Param request body RotateAPIKeyRequest false "Grace period"
+// @Success 200 {object} APIKeyResponse
+// @Failure 400 {object} ErrorResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/api-keys/{id}/rotate [post]
+func (h *UserHandler) RotateAPIKey(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	// The body is optional
+	var req RotateAPIKeyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+			return
+		}
+	}
+
+	grace := auth.APIKeyRotationGrace
+	if req.GracePeriodSeconds != nil {
+		grace = time.Duration(*req.GracePeriodSeconds) * time.Second
+		if grace > auth.MaxAPIKeyRotationGrace {
+			c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error: fmt.Sprintf("grace_period_seconds must be at most %d", int(auth.MaxAPIKeyRotationGrace.Seconds())),
+			})
+			return
+		}
+	}
+
+	var apiKey models.APIKey
+	var key string
+	err := h.DB.Transaction(func(tx *gorm.DB) error {
+		// Lock the key so concurrent rotations can't both keep the same old secret
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND user_id = ? AND is_active = ?", c.Param("id"), userID, true).
+			First(&apiKey).Error; err != nil {
+			return err
+		}
+
+		var err error
+		if key, err = apiKey.Rotate(grace); err != nil {
+			return err
+		}
+		return tx.Model(&apiKey).Updates(map[string]interface{}{
+			"prefix":              apiKey.Prefix,
+			"key_hash":            apiKey.KeyHash,
+			"previous_prefix":     apiKey.PreviousPrefix,
+			"previous_key_hash":   apiKey.PreviousKeyHash,
+			"previous_expires_at": apiKey.PreviousExpiresAt,
+			"rotated_at":          apiKey.RotatedAt,
+		}).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "API key not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to rotate API key"})
+		return
+	}
+	audit.Record(c, h.DB, audit.Event{
+		Type:    audit.EventAPIKeyRotated,
+		UserID:  userID,
+		Details: fmt.Sprintf("Key %d (%s), old secret valid for %s", apiKey.ID, apiKey.Name, grace),
+	})
+
+	c.JSON(http.StatusOK, apiKeyResponse(&apiKey, key))
+}
+
+// GetAPIKeyUsage godoc
+// @Summary API key usage
+// @Description Get the number of requests made with an API key per day (UTC), newest first. Counts are written in batches, so the last few seconds may be missing.
+// @Tags Users
+// @Security BearerAuth
+// @Produce json
+// @Param id path int true "API Key ID"
+// @Param days query int false "Number of days to include (default: 30, max: 366)"
+// @Success 200 {object} APIKeyUsageResponse
+// @Failure 401 {object} ErrorResponse
+// @Failure 404 {object} ErrorResponse
+// @Failure 500 {object} ErrorResponse
+// @Router /users/api-keys/{id}/usage [get]
+func (h *UserHandler) GetAPIKeyUsage(c *gin.Context) {
+	userID, exists := auth.GetUserID(c)
+	if !exists {
+		c.JSON(http.StatusUnauthorized, ErrorResponse{Error: "User not found"})
+		return
+	}
+
+	var apiKey models.APIKey
+	if err := h.DB.Where("id = ? AND user_id = ?", c.Param("id"), userID).First(&apiKey).Error; err != nil {
+		c.JSON(http.StatusNotFound, ErrorResponse{Error: "API key not found"})
+		return
+	}
+
+	days := 30
+	if d := c.Query("days"); d != "" {
+		if parsed, err := parseInt(d); err == nil && parsed > 0 && parsed <= 366 {
+			days = parsed
+		}
+	}
+	since := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+
+	var usages []models.APIKeyUsage
+	if err := h.DB.Where("api_key_id = ? AND day >= ?", apiKey.ID, since).Order("day DESC").Find(&usages).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, ErrorResponse{Error: "Failed to fetch API key usage"})
+		return
+	}
+
+	response := APIKeyUsageResponse{APIKeyID: apiKey.ID, Days: make([]APIKeyDailyUsage, 0, len(usages))}
+	for _, usage := range usages {
+		response.TotalRequests += usage.RequestCount
+		response.Days = append(response.Days, APIKeyDailyUsage{Date: usage.Day.Format("2006-01-02"), Requests: usage.RequestCount})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+func apiKeyInfo(apiKey *models.APIKey) APIKeyInfo {
+	return APIKeyInfo{
+		ID:                   apiKey.ID,
+		Name:                 apiKey.Name,
+		KeyPrefix:            apiKey.MaskedKey(),
+		Scopes:               apiKey.ScopeList(),
+		AllowedCIDRs:         apiKey.AllowedCIDRList(),
+		IsActive:             apiKey.IsActive,
+		CreatedAt:            apiKey.CreatedAt,
+		LastUsedAt:           apiKey.LastUsedAt,
+		ExpiresAt:            apiKey.ExpiresAt,
+		RotatedAt:            apiKey.RotatedAt,
+		PreviousKeyExpiresAt: apiKey.PreviousExpiresAt,
+	}
+}
+
+func apiKeyResponse(apiKey *models.APIKey, key string) APIKeyResponse {
+	return APIKeyResponse{
+		ID:                   apiKey.ID,
+		Name:                 apiKey.Name,
+		Key:                  key,
+		Scopes:               apiKey.ScopeList(),
+		AllowedCIDRs:         apiKey.AllowedCIDRList(),
+		CreatedAt:            apiKey.CreatedAt,
+		ExpiresAt:            apiKey.ExpiresAt,
+		PreviousKeyExpiresAt: apiKey.PreviousExpiresAt,
+	}
+}
+
+// allowedCIDRsDetail describes a key's allowlist for the audit log
+func allowedCIDRsDetail(apiKey *models.APIKey) string {
+	if apiKey.AllowedCIDRs == "" {
+		return "any address"
+	}
+	return strings.Join(apiKey.AllowedCIDRList(), ", ")
+}
@@ -0,0 +1,257 @@
+// internal/handlers/user_handler_test.go
+package handlers_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/jedi116/kaizen-api/internal/auth"
+	"github.com/jedi116/kaizen-api/internal/handlers"
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+func createAPIKey(t *testing.T, api *testAPI, token string, scopes ...string) handlers.APIKeyResponse {
+	t.Helper()
+	var created handlers.APIKeyResponse
+	status := do(t, newClient(t), http.MethodPost, api.URL+"/api/users/api-keys", token,
+		handlers.CreateAPIKeyRequest{Name: "Test key", Scopes: scopes}, &created)
+	if status != http.StatusCreated {
+		t.Fatalf("creating an API key returned %d", status)
+	}
+	return created
+}
+
+// doWithAPIKey sends a request authenticated with an API key instead of a JWT
+func doWithAPIKey(t *testing.T, api *testAPI, method, path, key string, body, out interface{}) int {
+	t.Helper()
+	return doWithHeaders(t, newClient(t), method, api.URL+path, map[string]string{"X-API-Key": key}, body, out)
+}
+
+func TestAPIKeySecretIsOnlyShownOnce(t *testing.T) {
+	api := startTestAPI(t, nil)
+	token := register(t, api, uniqueEmail("apikey")).AccessToken
+
+	created := createAPIKey(t, api, token, auth.ScopeJournalsRead)
+	prefix, ok := models.APIKeyPrefix(created.Key)
+	if !ok || !strings.HasPrefix(created.Key, prefix+"_") {
+		t.Fatalf("created key %q has no lookup prefix", created.Key)
+	}
+
+	// Only the prefix and a hash are stored
+	var stored models.APIKey
+	if err := api.DB.First(&stored, created.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if stored.Prefix != prefix || stored.KeyHash != models.HashAPIKey(created.Key) {
+		t.Errorf("stored prefix %q and hash %q, want %q and the key's hash", stored.Prefix, stored.KeyHash, prefix)
+	}
+
+	// Listing shows the prefix but never the secret
+	var listed json.RawMessage
+	status := do(t, newClient(t), http.MethodGet, api.URL+"/api/users/api-keys", token, nil, &listed)
+	if status != http.StatusOK || !strings.Contains(string(listed), prefix) {
+		t.Fatalf("listing keys returned %d without the prefix: %s", status, listed)
+	}
+	if secret := strings.TrimPrefix(created.Key, prefix+"_"); strings.Contains(string(listed), secret) {
+		t.Error("listing keys revealed the secret")
+	}
+
+	if status := doWithAPIKey(t, api, http.MethodGet, "/api/journals", created.Key, nil, nil); status != http.StatusOK {
+		t.Errorf("request with the key returned %d, want 200", status)
+	}
+
+	// The prefix alone, or with another secret, is not enough
+	forged := prefix + "_" + strings.Repeat("0", len(created.Key)-len(prefix)-1)
+	for _, key := range []string{prefix, forged} {
+		if status := doWithAPIKey(t, api, http.MethodGet, "/api/journals", key, nil, nil); status != http.StatusUnauthorized {
+			t.Errorf("request with key %q returned %d, want 401", key, status)
+		}
+	}
+}
+
+func TestAPIKeyScopes(t *testing.T) {
+	api := startTestAPI(t, nil)
+	token := register(t, api, uniqueEmail("scopes")).AccessToken
+	category := createCategory(t, api, token, models.JournalTypeExpense)
+
+	journalsOnly := createAPIKey(t, api, token, auth.ScopeJournalsRead)
+	categoriesWrite := createAPIKey(t, api, token, auth.ScopeCategoriesRead, auth.ScopeCategoriesWrite)
+	entry := handlers.CreateJournalRequest{CategoryID: category.ID, Amount: 1000, Title: "Lunch"}
+	newCategory := handlers.CreateCategoryRequest{Name: "Travel", Type: models.JournalTypeExpense}
+
+	for _, tc := range []struct {
+		name   string
+		key    string
+		method string
+		path   string
+		body   interface{}
+		want   int
+	}{
+		{"read journals", journalsOnly.Key, http.MethodGet, "/api/journals", nil, http.StatusOK},
+		{"write journals without journals:write", journalsOnly.Key, http.MethodPost, "/api/journals", entry, http.StatusForbidden},
+		{"read categories without categories:read", journalsOnly.Key, http.MethodGet, "/api/categories", nil, http.StatusForbidden},
+		{"read categories", categoriesWrite.Key, http.MethodGet, "/api/categories", nil, http.StatusOK},
+		{"write categories", categoriesWrite.Key, http.MethodPost, "/api/categories", newCategory, http.StatusCreated},
+		{"read journals without journals:read", categoriesWrite.Key, http.MethodGet, "/api/journals", nil, http.StatusForbidden},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var response json.RawMessage
+			status := doWithAPIKey(t, api, tc.method, tc.path, tc.key, tc.body, &response)
+			if status != tc.want {
+				t.Fatalf("%s %s returned %d, want %d", tc.method, tc.path, status, tc.want)
+			}
+			if status == http.StatusForbidden && !strings.Contains(string(response), `"missing_scope"`) {
+				t.Errorf("403 doesn't name the missing scope: %s", response)
+			}
+		})
+	}
+
+	// Keys can't manage the account, whatever their scopes
+	if status := doWithAPIKey(t, api, http.MethodGet, "/api/users/api-keys", categoriesWrite.Key, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("listing API keys with an API key returned %d, want 401", status)
+	}
+
+	// A JWT acts as the user and needs no scopes
+	if status := do(t, newClient(t), http.MethodPost, api.URL+"/api/journals", token, entry, nil); status != http.StatusCreated {
+		t.Errorf("creating an entry with a JWT returned %d, want 201", status)
+	}
+}
+
+// lastSubjectTo returns the subject of the last email sent to address
+func lastSubjectTo(t *testing.T, api *testAPI, address string) string {
+	t.Helper()
+	msg, err := api.Outbox.LastMessageTo(address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msg == nil {
+		return ""
+	}
+	return msg.Subject
+}
+
+func TestChangePassword(t *testing.T) {
+	api := startTestAPI(t, nil)
+	email := uniqueEmail("password")
+	token := register(t, api, email).AccessToken
+	var other handlers.AuthResponse
+	if status := login(t, api, email, &other); status != http.StatusOK {
+		t.Fatalf("signing in on another device returned %d", status)
+	}
+	client := newClient(t)
+	passwordURL := api.URL + "/api/users/me/password"
+
+	for _, tc := range []struct {
+		name string
+		req  handlers.ChangePasswordRequest
+	}{
+		{"wrong current password", handlers.ChangePasswordRequest{CurrentPassword: "wrong-password-1", NewPassword: "violet-harbor-compass-17"}},
+		{"weak new password", handlers.ChangePasswordRequest{CurrentPassword: "staple-orbit-lantern-42", NewPassword: "short"}},
+		{"unchanged password", handlers.ChangePasswordRequest{CurrentPassword: "staple-orbit-lantern-42", NewPassword: "staple-orbit-lantern-42"}},
+	} {
+		if status := do(t, client, http.MethodPut, passwordURL, token, tc.req, nil); status != http.StatusBadRequest {
+			t.Errorf("%s returned %d, want 400", tc.name, status)
+		}
+	}
+
+	status := do(t, client, http.MethodPut, passwordURL, token, handlers.ChangePasswordRequest{
+		CurrentPassword: "staple-orbit-lantern-42",
+		NewPassword:     "violet-harbor-compass-17",
+	}, nil)
+	if status != http.StatusOK {
+		t.Fatalf("changing the password returned %d, want 200", status)
+	}
+
+	// This device stays signed in, the others are signed out
+	if status := do(t, client, http.MethodGet, api.URL+"/api/users/me", token, nil, nil); status != http.StatusOK {
+		t.Errorf("the changing session got %d afterwards, want 200", status)
+	}
+	if status := do(t, client, http.MethodGet, api.URL+"/api/users/me", other.AccessToken, nil, nil); status != http.StatusUnauthorized {
+		t.Errorf("another session got %d afterwards, want 401", status)
+	}
+	if subject := lastSubjectTo(t, api, email); subject != "Your Kaizen password was changed" {
+		t.Errorf("last email to the user is %q, want the password change notice", subject)
+	}
+
+	if status := login(t, api, email, nil); status != http.StatusUnauthorized {
+		t.Errorf("signing in with the old password returned %d, want 401", status)
+	}
+	status = do(t, client, http.MethodPost, api.URL+"/api/auth/login", "", handlers.LoginRequest{Email: email, Password: "violet-harbor-compass-17"}, nil)
+	if status != http.StatusOK {
+		t.Errorf("signing in with the new password returned %d, want 200", status)
+	}
+}
+
+func TestChangeEmail(t *testing.T) {
+	api := startTestAPI(t, nil)
+	oldEmail, newEmail := uniqueEmail("old"), uniqueEmail("new")
+	registered := register(t, api, oldEmail)
+	token := registered.AccessToken
+	taken := uniqueEmail("taken")
+	register(t, api, taken)
+	client := newClient(t)
+	emailURL := api.URL + "/api/users/me/email"
+
+	for _, tc := range []struct {
+		name string
+		req  handlers.ChangeEmailRequest
+		want int
+	}{
+		{"wrong password", handlers.ChangeEmailRequest{NewEmail: newEmail, Password: "wrong-password-1"}, http.StatusBadRequest},
+		{"same address", handlers.ChangeEmailRequest{NewEmail: oldEmail, Password: "staple-orbit-lantern-42"}, http.StatusBadRequest},
+		{"taken address", handlers.ChangeEmailRequest{NewEmail: taken, Password: "staple-orbit-lantern-42"}, http.StatusConflict},
+	} {
+		if status := do(t, client, http.MethodPost, emailURL, token, tc.req, nil); status != tc.want {
+			t.Errorf("%s returned %d, want %d", tc.name, status, tc.want)
+		}
+	}
+
+	status := do(t, client, http.MethodPost, emailURL, token, handlers.ChangeEmailRequest{NewEmail: newEmail, Password: "staple-orbit-lantern-42"}, nil)
+	if status != http.StatusAccepted {
+		t.Fatalf("changing the email returned %d, want 202", status)
+	}
+
+	// Nothing changes until the new address confirms
+	var user models.User
+	if err := api.DB.First(&user, registered.User.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != oldEmail {
+		t.Fatalf("email changed to %s before confirming", user.Email)
+	}
+
+	changeToken := emailedToken(t, api, "/confirm-email-change", newEmail, 1)
+	confirmURL := api.URL + "/api/auth/confirm-email-change"
+	if status := do(t, client, http.MethodPost, confirmURL, "", handlers.ConfirmEmailChangeRequest{Token: changeToken}, nil); status != http.StatusOK {
+		t.Fatalf("confirming returned %d, want 200", status)
+	}
+	if err := api.DB.First(&user, registered.User.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	if user.Email != newEmail || !user.EmailVerified {
+		t.Errorf("after confirming the user has %s (verified %v), want %s verified", user.Email, user.EmailVerified, newEmail)
+	}
+	if subject := lastSubjectTo(t, api, oldEmail); subject != "Your Kaizen email address was changed" {
+		t.Errorf("last email to the old address is %q, want the change notice", subject)
+	}
+	if status := do(t, client, http.MethodPost, confirmURL, "", handlers.ConfirmEmailChangeRequest{Token: changeToken}, nil); status != http.StatusBadRequest {
+		t.Errorf("reusing the confirmation returned %d, want 400", status)
+	}
+	if status := login(t, api, newEmail, nil); status != http.StatusOK {
+		t.Errorf("signing in with the new address returned %d, want 200", status)
+	}
+
+	// An address taken while the confirmation was pending can't be claimed
+	contested := uniqueEmail("contested")
+	status = do(t, client, http.MethodPost, emailURL, token, handlers.ChangeEmailRequest{NewEmail: contested, Password: "staple-orbit-lantern-42"}, nil)
+	if status != http.StatusAccepted {
+		t.Fatalf("changing the email again returned %d, want 202", status)
+	}
+	register(t, api, contested)
+	contestedToken := emailedToken(t, api, "/confirm-email-change", contested, 1)
+	if status := do(t, client, http.MethodPost, confirmURL, "", handlers.ConfirmEmailChangeRequest{Token: contestedToken}, nil); status != http.StatusConflict {
+		t.Errorf("confirming an address taken meanwhile returned %d, want 409", status)
+	}
+}
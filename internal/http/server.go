@@ -1,6 +1,11 @@
 package http
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	nethttp "net/http"
 	"os"
 	"strings"
 	"time"
@@ -14,15 +19,70 @@ import (
 	_ "github.com/jedi116/kaizen-api/docs"
 	"github.com/jedi116/kaizen-api/internal/auth"
 	"github.com/jedi116/kaizen-api/internal/handlers"
+	"github.com/jedi116/kaizen-api/internal/mail"
+	"github.com/jedi116/kaizen-api/internal/models"
+	"github.com/jedi116/kaizen-api/internal/oidc"
 )
 
 type KaizenServer struct {
 	GinEngine *gin.Engine
 	DB        *gorm.DB
+	Mailer    mail.Mailer
+
+	// External sign-in providers, keyed by the name used in /api/auth/oidc/:provider
+	OIDCProviders map[string]*oidc.Provider
 }
 
-func (s KaizenServer) Start() error {
-	return s.GinEngine.Run()
+// ShutdownTimeout is how long in-flight requests get to finish after shutdown starts
+var ShutdownTimeout = 15 * time.Second
+
+// Start serves requests on PORT (default 8080) until ctx is done, then shuts down gracefully
+func (s KaizenServer) Start(ctx context.Context) error {
+	port := os.Getenv("PORT")
+	if port == "" {
+		port = "8080"
+	}
+	srv := &nethttp.Server{Addr: ":" + port, Handler: s.GinEngine}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Println("Shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	if err := <-errCh; !errors.Is(err, nethttp.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// ConfigureTrustedProxies makes c.ClientIP() honour X-Forwarded-For and X-Real-IP
+// only from the proxies listed in TRUSTED_PROXIES, a comma-separated list of IPs
+// or CIDRs (e.g. "10.0.0.0/8,192.168.1.10"). By default no proxy is trusted and
+// the connection's address is used, since a client can set those headers itself
+// to get around API key IP allowlists and per-IP login limits.
+func ConfigureTrustedProxies(engine *gin.Engine) error {
+	var proxies []string
+	for _, proxy := range strings.Split(os.Getenv("TRUSTED_PROXIES"), ",") {
+		if trimmed := strings.TrimSpace(proxy); trimmed != "" {
+			proxies = append(proxies, trimmed)
+		}
+	}
+	if err := engine.SetTrustedProxies(proxies); err != nil {
+		return fmt.Errorf("TRUSTED_PROXIES: %w", err)
+	}
+	return nil
 }
 
 // configureCORS sets up CORS middleware with origins from ALLOWED_ORIGINS env var
@@ -47,8 +107,8 @@ func (s KaizenServer) configureCORS() {
 	config := cors.Config{
 		AllowOrigins:     allowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept", "Accept-Encoding", "Authorization", "X-API-Key", "X-Requested-With"},
-		ExposeHeaders:    []string{"Content-Length", "Content-Type"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Content-Length", "Accept", "Accept-Encoding", "Authorization", "X-API-Key", "X-Requested-With", auth.CSRFHeaderName},
+		ExposeHeaders:    []string{"Content-Length", "Content-Type", auth.CSRFHeaderName},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}
@@ -67,14 +127,25 @@ func (s KaizenServer) RegisterRoutes() {
 		})
 	})
 
+	// Public signing keys, so other services can verify Kaizen access tokens.
+	// They must check the audience is auth.AudienceAPI.
+	s.GinEngine.GET("/.well-known/jwks.json", func(c *gin.Context) {
+		c.Header("Cache-Control", "public, max-age=300")
+		c.JSON(200, auth.PublicJWKS())
+	})
+
 	// Swagger documentation
 	s.GinEngine.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// Initialize handlers
-	authHandler := &handlers.AuthHandler{DB: s.DB}
-	userHandler := &handlers.UserHandler{DB: s.DB}
+	authHandler := &handlers.AuthHandler{DB: s.DB, Mailer: s.Mailer, OIDCProviders: s.OIDCProviders}
+	userHandler := &handlers.UserHandler{DB: s.DB, Mailer: s.Mailer}
 	categoryHandler := &handlers.FinanceCategoryHandler{DB: s.DB}
 	journalHandler := &handlers.FinanceJournalHandler{DB: s.DB}
+	accountHandler := &handlers.FinanceAccountHandler{DB: s.DB}
+	transferHandler := &handlers.FinanceTransferHandler{DB: s.DB}
+	budgetHandler := &handlers.FinanceBudgetHandler{DB: s.DB}
+	adminHandler := &handlers.AdminHandler{DB: s.DB}
 
 	// API routes
 	api := s.GinEngine.Group("/api")
@@ -85,6 +156,16 @@ func (s KaizenServer) RegisterRoutes() {
 		authGroup.POST("/register", authHandler.Register)
 		authGroup.POST("/login", authHandler.Login)
 		authGroup.POST("/refresh", authHandler.RefreshToken)
+		authGroup.POST("/verify-email", authHandler.VerifyEmail)
+		authGroup.POST("/confirm-email-change", authHandler.ConfirmEmailChange)
+		authGroup.POST("/forgot-password", authHandler.ForgotPassword)
+		authGroup.POST("/reset-password", authHandler.ResetPassword)
+		authGroup.POST("/magic-link", authHandler.RequestMagicLink)
+		authGroup.POST("/magic-link/verify", authHandler.VerifyMagicLink)
+		authGroup.POST("/2fa/verify", authHandler.VerifyTwoFactor)
+		authGroup.GET("/oidc/providers", authHandler.ListOIDCProviders)
+		authGroup.GET("/oidc/:provider/login", authHandler.OIDCLogin)
+		authGroup.GET("/oidc/:provider/callback", authHandler.OIDCCallback)
 	}
 
 	// Auth routes (protected - requires JWT)
@@ -93,6 +174,13 @@ func (s KaizenServer) RegisterRoutes() {
 	{
 		authProtected.POST("/logout", authHandler.Logout)
 		authProtected.POST("/logout-all", authHandler.LogoutAllDevices)
+		authProtected.POST("/resend-verification", authHandler.ResendVerification)
+		authProtected.POST("/2fa/enroll", authHandler.EnrollTwoFactor)
+		authProtected.POST("/2fa/confirm", authHandler.ConfirmTwoFactor)
+		authProtected.POST("/2fa/disable", authHandler.DisableTwoFactor)
+		authProtected.GET("/sessions", authHandler.ListSessions)
+		authProtected.DELETE("/sessions/:id", authHandler.RevokeSession)
+		authProtected.POST("/oidc/:provider/link", authHandler.LinkOIDCIdentity)
 	}
 
 	// User routes (protected - requires JWT)
@@ -101,31 +189,100 @@ func (s KaizenServer) RegisterRoutes() {
 	{
 		usersGroup.GET("/me", userHandler.GetProfile)
 		usersGroup.PUT("/me", userHandler.UpdateProfile)
+		usersGroup.DELETE("/me", userHandler.DeleteAccount)
+		usersGroup.GET("/me/export", userHandler.ExportData)
+		usersGroup.PUT("/me/password", userHandler.ChangePassword)
+		usersGroup.POST("/me/email", userHandler.ChangeEmail)
+		usersGroup.GET("/me/security-events", userHandler.ListSecurityEvents)
 		usersGroup.POST("/api-keys", userHandler.CreateAPIKey)
 		usersGroup.GET("/api-keys", userHandler.ListAPIKeys)
+		usersGroup.PUT("/api-keys/:id", userHandler.UpdateAPIKey)
 		usersGroup.DELETE("/api-keys/:id", userHandler.DeleteAPIKey)
+		usersGroup.POST("/api-keys/:id/rotate", userHandler.RotateAPIKey)
+		usersGroup.GET("/api-keys/:id/usage", userHandler.GetAPIKeyUsage)
+	}
+
+	// Finance routes accept a JWT or a scoped API key, and can be limited to verified users
+	financeAuth := []gin.HandlerFunc{auth.OptionalAuth(s.DB)}
+	if os.Getenv("REQUIRE_EMAIL_VERIFICATION") == "true" {
+		financeAuth = append(financeAuth, auth.RequireVerifiedEmail(s.DB))
 	}
 
-	// Finance Category routes (protected - requires JWT)
+	// Finance Category routes (protected - requires JWT or a scoped API key)
 	categoriesGroup := api.Group("/categories")
-	categoriesGroup.Use(auth.JWTAuthMiddleware(s.DB))
+	categoriesGroup.Use(financeAuth...)
+	{
+		categoriesGroup.POST("", auth.RequireScope(auth.ScopeCategoriesWrite), categoryHandler.CreateCategory)
+		categoriesGroup.GET("", auth.RequireScope(auth.ScopeCategoriesRead), categoryHandler.ListCategories)
+		categoriesGroup.GET("/:id", auth.RequireScope(auth.ScopeCategoriesRead), categoryHandler.GetCategory)
+		categoriesGroup.PUT("/:id", auth.RequireScope(auth.ScopeCategoriesWrite), categoryHandler.UpdateCategory)
+		categoriesGroup.DELETE("/:id", auth.RequireScope(auth.ScopeCategoriesWrite), categoryHandler.DeleteCategory)
+	}
+
+	// Finance Account routes (protected - requires JWT or a scoped API key)
+	accountsGroup := api.Group("/accounts")
+	accountsGroup.Use(financeAuth...)
 	{
-		categoriesGroup.POST("", categoryHandler.CreateCategory)
-		categoriesGroup.GET("", categoryHandler.ListCategories)
-		categoriesGroup.GET("/:id", categoryHandler.GetCategory)
-		categoriesGroup.PUT("/:id", categoryHandler.UpdateCategory)
-		categoriesGroup.DELETE("/:id", categoryHandler.DeleteCategory)
+		accountsGroup.POST("", auth.RequireScope(auth.ScopeAccountsWrite), accountHandler.CreateAccount)
+		accountsGroup.GET("", auth.RequireScope(auth.ScopeAccountsRead), accountHandler.ListAccounts)
+		accountsGroup.GET("/net-worth", auth.RequireScope(auth.ScopeAccountsRead), accountHandler.GetNetWorth)
+		accountsGroup.GET("/:id", auth.RequireScope(auth.ScopeAccountsRead), accountHandler.GetAccount)
+		accountsGroup.GET("/:id/balance", auth.RequireScope(auth.ScopeAccountsRead), accountHandler.GetAccountBalance)
+		accountsGroup.PUT("/:id", auth.RequireScope(auth.ScopeAccountsWrite), accountHandler.UpdateAccount)
+		accountsGroup.DELETE("/:id", auth.RequireScope(auth.ScopeAccountsWrite), accountHandler.DeleteAccount)
 	}
 
-	// Finance Journal routes (protected - requires JWT)
+	// Finance Journal routes (protected - requires JWT or a scoped API key)
 	journalsGroup := api.Group("/journals")
-	journalsGroup.Use(auth.JWTAuthMiddleware(s.DB))
+	journalsGroup.Use(financeAuth...)
+	{
+		journalsGroup.POST("", auth.RequireScope(auth.ScopeJournalsWrite), journalHandler.CreateJournal)
+		journalsGroup.GET("", auth.RequireScope(auth.ScopeJournalsRead), journalHandler.ListJournals)
+		journalsGroup.GET("/summary", auth.RequireScope(auth.ScopeJournalsRead), journalHandler.GetSummary)
+		journalsGroup.GET("/:id", auth.RequireScope(auth.ScopeJournalsRead), journalHandler.GetJournal)
+		journalsGroup.PUT("/:id", auth.RequireScope(auth.ScopeJournalsWrite), journalHandler.UpdateJournal)
+		journalsGroup.DELETE("/:id", auth.RequireScope(auth.ScopeJournalsWrite), journalHandler.DeleteJournal)
+	}
+
+	// Finance Transfer routes (protected - requires JWT or a scoped API key)
+	// Transfers are pairs of journal entries, so they share the journal scopes
+	transfersGroup := api.Group("/transfers")
+	transfersGroup.Use(financeAuth...)
+	{
+		transfersGroup.POST("", auth.RequireScope(auth.ScopeJournalsWrite), transferHandler.CreateTransfer)
+		transfersGroup.GET("", auth.RequireScope(auth.ScopeJournalsRead), transferHandler.ListTransfers)
+		transfersGroup.GET("/:id", auth.RequireScope(auth.ScopeJournalsRead), transferHandler.GetTransfer)
+		transfersGroup.PUT("/:id", auth.RequireScope(auth.ScopeJournalsWrite), transferHandler.UpdateTransfer)
+		transfersGroup.DELETE("/:id", auth.RequireScope(auth.ScopeJournalsWrite), transferHandler.DeleteTransfer)
+	}
+
+	// Finance Budget routes (protected - requires JWT or a scoped API key)
+	budgetsGroup := api.Group("/budgets")
+	budgetsGroup.Use(financeAuth...)
+	{
+		budgetsGroup.POST("", auth.RequireScope(auth.ScopeBudgetsWrite), budgetHandler.CreateBudget)
+		budgetsGroup.GET("", auth.RequireScope(auth.ScopeBudgetsRead), budgetHandler.ListBudgets)
+		budgetsGroup.GET("/progress", auth.RequireScope(auth.ScopeBudgetsRead), budgetHandler.GetBudgetProgress)
+		budgetsGroup.GET("/:id", auth.RequireScope(auth.ScopeBudgetsRead), budgetHandler.GetBudget)
+		budgetsGroup.PUT("/:id", auth.RequireScope(auth.ScopeBudgetsWrite), budgetHandler.UpdateBudget)
+		budgetsGroup.DELETE("/:id", auth.RequireScope(auth.ScopeBudgetsWrite), budgetHandler.DeleteBudget)
+	}
+
+	// Admin routes (protected - requires a JWT with the admin role)
+	adminGroup := api.Group("/admin")
+	adminGroup.Use(auth.JWTAuthMiddleware(s.DB), auth.RequireRole(models.RoleAdmin))
 	{
-		journalsGroup.POST("", journalHandler.CreateJournal)
-		journalsGroup.GET("", journalHandler.ListJournals)
-		journalsGroup.GET("/summary", journalHandler.GetSummary)
-		journalsGroup.GET("/:id", journalHandler.GetJournal)
-		journalsGroup.PUT("/:id", journalHandler.UpdateJournal)
-		journalsGroup.DELETE("/:id", journalHandler.DeleteJournal)
+		adminGroup.GET("/users", adminHandler.ListUsers)
+		adminGroup.GET("/users/:id", adminHandler.GetUser)
+		adminGroup.POST("/users/:id/disable", adminHandler.DisableUser)
+		adminGroup.POST("/users/:id/enable", adminHandler.EnableUser)
+		adminGroup.POST("/users/:id/logout", adminHandler.LogoutUser)
+		adminGroup.POST("/users/:id/unlock", adminHandler.UnlockUser)
+		adminGroup.PUT("/users/:id/role", adminHandler.SetUserRole)
+		adminGroup.GET("/stats", adminHandler.GetStats)
+		adminGroup.GET("/jobs", adminHandler.ListJobs)
+		adminGroup.GET("/audit-events", adminHandler.ListAuditEvents)
+		adminGroup.GET("/exchange-rates", adminHandler.ListExchangeRates)
+		adminGroup.POST("/exchange-rates", adminHandler.ImportExchangeRates)
 	}
 }
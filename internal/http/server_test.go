@@ -0,0 +1,50 @@
+package http
+
+import (
+	nethttp "net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestConfigureTrustedProxies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name    string
+		proxies string
+		remote  string
+		want    string
+	}{
+		{"forwarded header ignored by default", "", "203.0.113.7:4000", "203.0.113.7"},
+		{"forwarded header from a trusted proxy", "10.0.0.0/8", "10.1.2.3:4000", "198.51.100.1"},
+		{"forwarded header from another address", "10.0.0.0/8", "203.0.113.7:4000", "203.0.113.7"},
+		{"trusted proxy by address", "203.0.113.7, 10.0.0.1", "203.0.113.7:4000", "198.51.100.1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("TRUSTED_PROXIES", tt.proxies)
+			engine := gin.New()
+			if err := ConfigureTrustedProxies(engine); err != nil {
+				t.Fatal(err)
+			}
+			engine.GET("/ip", func(c *gin.Context) { c.String(nethttp.StatusOK, c.ClientIP()) })
+
+			req := httptest.NewRequest(nethttp.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remote
+			req.Header.Set("X-Forwarded-For", "198.51.100.1")
+			req.Header.Set("X-Real-IP", "198.51.100.1")
+			rec := httptest.NewRecorder()
+			engine.ServeHTTP(rec, req)
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	t.Setenv("TRUSTED_PROXIES", "not-an-ip")
+	if err := ConfigureTrustedProxies(gin.New()); err == nil {
+		t.Error("invalid TRUSTED_PROXIES accepted")
+	}
+}
@@ -0,0 +1,53 @@
+// internal/mail/mailer.go
+package mail
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Message is a plain text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers outgoing email
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// NewFromEnv builds the mailer selected by MAIL_DRIVER ("smtp" or "outbox").
+// Outside production it defaults to an outbox in MAIL_OUTBOX_DIR (default "./tmp/outbox").
+func NewFromEnv() (Mailer, error) {
+	driver := os.Getenv("MAIL_DRIVER")
+	if driver == "" && os.Getenv("ENV") != "production" {
+		driver = "outbox"
+	}
+
+	switch driver {
+	case "smtp":
+		port, err := strconv.Atoi(os.Getenv("SMTP_PORT"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid SMTP_PORT: %w", err)
+		}
+		return &SMTPMailer{
+			Host:     os.Getenv("SMTP_HOST"),
+			Port:     port,
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			From:     os.Getenv("MAIL_FROM"),
+		}, nil
+	case "outbox":
+		dir := os.Getenv("MAIL_OUTBOX_DIR")
+		if dir == "" {
+			dir = "./tmp/outbox"
+		}
+		return NewOutboxMailer(dir)
+	default:
+		return nil, fmt.Errorf("MAIL_DRIVER must be \"smtp\" or \"outbox\", got %q", driver)
+	}
+}
@@ -0,0 +1,115 @@
+// internal/mail/outbox.go
+package mail
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OutboxMailer writes each message to a file instead of sending it.
+// Used for local development and tests.
+type OutboxMailer struct {
+	Dir string
+}
+
+// NewOutboxMailer creates an outbox, making sure its directory exists
+func NewOutboxMailer(dir string) (*OutboxMailer, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+	return &OutboxMailer{Dir: dir}, nil
+}
+
+// Send writes msg to a new .eml file in the outbox directory
+func (m *OutboxMailer) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), hex.EncodeToString(suffix))
+
+	content := fmt.Sprintf("To: %s\nSubject: %s\n\n%s", msg.To, msg.Subject, msg.Body)
+	return os.WriteFile(filepath.Join(m.Dir, name), []byte(content), 0o644)
+}
+
+// Messages reads back every message in the outbox, oldest first
+func (m *OutboxMailer) Messages() ([]Message, error) {
+	paths, err := filepath.Glob(filepath.Join(m.Dir, "*.eml"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	messages := make([]Message, 0, len(paths))
+	for _, path := range paths {
+		msg, err := readMessage(path)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+// LastMessageTo returns the most recent message sent to address
+func (m *OutboxMailer) LastMessageTo(address string) (*Message, error) {
+	messages, err := m.Messages()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(messages) - 1; i >= 0; i-- {
+		if strings.EqualFold(messages[i].To, address) {
+			return &messages[i], nil
+		}
+	}
+	return nil, fmt.Errorf("no message sent to %s", address)
+}
+
+func readMessage(path string) (Message, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Message{}, err
+	}
+	defer f.Close()
+
+	var msg Message
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			break
+		}
+		if key, value, ok := strings.Cut(line, ": "); ok {
+			switch key {
+			case "To":
+				msg.To = value
+			case "Subject":
+				msg.Subject = value
+			}
+		}
+		if err != nil {
+			return msg, nil
+		}
+	}
+
+	body, err := io.ReadAll(reader)
+	if err != nil {
+		return Message{}, err
+	}
+	msg.Body = string(body)
+	return msg, nil
+}
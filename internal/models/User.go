@@ -7,6 +7,12 @@ import (
 	"gorm.io/gorm"
 )
 
+// User roles
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	gorm.Model
 	Name          string     `gorm:"not null;size:255" json:"name"`
@@ -14,12 +20,39 @@ type User struct {
 	Password      string     `gorm:"not null" json:"-"`
 	EmailVerified bool       `gorm:"default:false" json:"email_verified"`
 	LastLoginAt   *time.Time `json:"last_login_at"`
+	Role          string     `gorm:"not null;size:20;default:'user'" json:"role"`        // RoleUser or RoleAdmin
+	DisabledAt    *time.Time `json:"disabled_at,omitempty"`                              // Set while an admin has disabled the account
+	BaseCurrency  string     `gorm:"not null;size:3;default:'USD'" json:"base_currency"` // ISO 4217 code that reports are converted to
+
+	// Two-factor authentication (TOTP)
+	TOTPSecret   string `gorm:"size:255" json:"-"`                       // Base32 secret, set during enrollment and encrypted at rest
+	TOTPEnabled  bool   `gorm:"default:false" json:"two_factor_enabled"` // True once enrollment is confirmed
+	TOTPLastStep int64  `gorm:"default:0" json:"-"`                      // Last accepted time step, prevents code replay
+
+	// Relationships (deleting a user deletes everything they own)
+	Categories    []FinanceCategory `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"categories,omitempty"`
+	Journals      []FinanceJournal  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"journals,omitempty"`
+	Accounts      []FinanceAccount  `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"accounts,omitempty"`
+	Transfers     []FinanceTransfer `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"transfers,omitempty"`
+	Budgets       []FinanceBudget   `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"budgets,omitempty"`
+	APIKeys       []APIKey          `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"api_keys,omitempty"`
+	Tokens        []Token           `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	Sessions      []Session         `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	OneTimeTokens []OneTimeToken    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	RecoveryCodes []RecoveryCode    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+	AuditEvents   []AuditEvent      `gorm:"foreignKey:UserID;constraint:OnDelete:SET NULL" json:"-"` // Outlive the account
+	Identities    []UserIdentity    `gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// HasPassword reports whether the user can sign in with a password. Accounts
+// created through an external provider have none until one is set.
+func (u *User) HasPassword() bool {
+	return u.Password != ""
+}
 
-	// Relationships
-	Categories []FinanceCategory `gorm:"foreignKey:UserID" json:"categories,omitempty"`
-	Journals   []FinanceJournal  `gorm:"foreignKey:UserID" json:"journals,omitempty"`
-	APIKeys    []APIKey          `gorm:"foreignKey:UserID" json:"api_keys,omitempty"`
-	Tokens     []Token           `gorm:"foreignKey:UserID" json:"-"`
+// IsDisabled reports whether an admin has disabled the account
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
 }
 
 // TableName overrides the default table name
@@ -3,36 +3,198 @@ package models
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
 	"encoding/hex"
+	"fmt"
+	"net/netip"
+	"strings"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+const (
+	apiKeyTag          = "kz_"
+	apiKeyPrefixBytes  = 6
+	apiKeySecretBytes  = 32
+	legacyAPIKeyLength = 64
+)
+
 type APIKey struct {
 	gorm.Model
 	UserID     uint       `gorm:"not null;index" json:"user_id"`
 	Name       string     `gorm:"not null;size:100" json:"name"`
-	Key        string     `gorm:"uniqueIndex;not null;size:64" json:"key"`
+	Prefix     string     `gorm:"uniqueIndex;not null;size:16" json:"-"` // Lookup prefix, e.g. "kz_1a2b3c4d5e6f"
+	KeyHash    string     `gorm:"not null;size:64" json:"-"`             // SHA-256 of the full key, hex encoded
+	Scopes     string     `gorm:"not null;size:255;default:''" json:"-"` // Space separated, e.g. "journals:read categories:read"
 	LastUsedAt *time.Time `json:"last_used_at"`
 	ExpiresAt  *time.Time `json:"expires_at"`
 	IsActive   bool       `gorm:"default:true" json:"is_active"`
 
+	// Rotation: the replaced secret keeps working until PreviousExpiresAt
+	PreviousPrefix    string     `gorm:"size:16;index" json:"-"`
+	PreviousKeyHash   string     `gorm:"size:64" json:"-"`
+	PreviousExpiresAt *time.Time `json:"previous_key_expires_at"`
+	RotatedAt         *time.Time `json:"rotated_at"`
+
+	AllowedCIDRs string `gorm:"type:text;not null;default:''" json:"-"` // Space separated, e.g. "203.0.113.0/24 2001:db8::/32"; empty allows any address
+
 	// Relationships
-	User User `gorm:"foreignKey:UserID" json:"-"`
+	User   User          `gorm:"foreignKey:UserID" json:"-"`
+	Usages []APIKeyUsage `gorm:"foreignKey:APIKeyID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// GenerateKey creates a new random API key and stores its prefix and hash.
+// The returned plaintext key is never persisted, so it can only be shown once.
+func (a *APIKey) GenerateKey() (string, error) {
+	prefix := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(prefix); err != nil {
+		return "", err
+	}
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+
+	a.Prefix = apiKeyTag + hex.EncodeToString(prefix)
+	key := a.Prefix + "_" + hex.EncodeToString(secret)
+	a.KeyHash = HashAPIKey(key)
+	return key, nil
+}
+
+// Rotate replaces the key's secret, returning the new plaintext key. The old
+// secret keeps working for grace (not at all if grace is 0); a secret replaced
+// by an earlier rotation stops working immediately.
+func (a *APIKey) Rotate(grace time.Duration) (string, error) {
+	previousPrefix, previousHash := a.Prefix, a.KeyHash
+	key, err := a.GenerateKey()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	a.RotatedAt = &now
+	a.PreviousPrefix, a.PreviousKeyHash, a.PreviousExpiresAt = "", "", nil
+	if grace > 0 {
+		expiresAt := now.Add(grace)
+		a.PreviousPrefix, a.PreviousKeyHash, a.PreviousExpiresAt = previousPrefix, previousHash, &expiresAt
+	}
+	return key, nil
 }
 
-// GenerateKey creates a new random API key
-func (a *APIKey) GenerateKey() error {
-	bytes := make([]byte, 32)
-	if _, err := rand.Read(bytes); err != nil {
-		return err
+// MatchesKey reports whether key hashes to the stored hash, or to the hash of
+// the secret replaced by the last rotation while its grace period lasts (constant time)
+func (a *APIKey) MatchesKey(key string) bool {
+	hash := []byte(HashAPIKey(key))
+	if subtle.ConstantTimeCompare(hash, []byte(a.KeyHash)) == 1 {
+		return true
 	}
-	a.Key = hex.EncodeToString(bytes)
+	return a.PreviousKeyHash != "" && a.PreviousExpiresAt != nil && time.Now().Before(*a.PreviousExpiresAt) &&
+		subtle.ConstantTimeCompare(hash, []byte(a.PreviousKeyHash)) == 1
+}
+
+// SetScopes stores the scopes granted to the key
+func (a *APIKey) SetScopes(scopes []string) {
+	a.Scopes = strings.Join(scopes, " ")
+}
+
+// ScopeList returns the scopes granted to the key
+func (a *APIKey) ScopeList() []string {
+	return strings.Fields(a.Scopes)
+}
+
+// SetAllowedCIDRs restricts the key to client addresses in the given ranges.
+// Plain addresses are accepted as single-address ranges; no ranges allows any address.
+func (a *APIKey) SetAllowedCIDRs(cidrs []string) error {
+	normalized := make([]string, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefix, err := ParseCIDR(cidr)
+		if err != nil {
+			return err
+		}
+		normalized = append(normalized, prefix.String())
+	}
+	a.AllowedCIDRs = strings.Join(normalized, " ")
 	return nil
 }
 
+// AllowedCIDRList returns the address ranges the key is restricted to
+func (a *APIKey) AllowedCIDRList() []string {
+	return strings.Fields(a.AllowedCIDRs)
+}
+
+// AllowsIP reports whether the key may be used from ip
+func (a *APIKey) AllowsIP(ip string) bool {
+	cidrs := a.AllowedCIDRList()
+	if len(cidrs) == 0 {
+		return true
+	}
+
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, cidr := range cidrs {
+		if prefix, err := netip.ParsePrefix(cidr); err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCIDR parses an address range such as "203.0.113.0/24", or a single address
+func ParseCIDR(s string) (netip.Prefix, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, fmt.Errorf("invalid IP address or CIDR range %q", s)
+		}
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+
+	prefix, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, fmt.Errorf("invalid IP address or CIDR range %q", s)
+	}
+	if prefix.Addr().Is4In6() && prefix.Bits() >= 96 {
+		prefix = netip.PrefixFrom(prefix.Addr().Unmap(), prefix.Bits()-96)
+	}
+	return prefix.Masked(), nil
+}
+
+// MaskedKey returns the key prefix with the secret part hidden
+func (a *APIKey) MaskedKey() string {
+	return a.Prefix + "..."
+}
+
 // TableName overrides the default table name
 func (APIKey) TableName() string {
 	return "api_keys"
 }
+
+// HashAPIKey returns the hex encoded SHA-256 digest of an API key
+func HashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// APIKeyPrefix extracts the lookup prefix from a presented API key.
+// Keys issued before prefixes existed are 64 hex characters; their prefix is
+// the first 12 characters, matching how the migration backfilled them.
+func APIKeyPrefix(key string) (string, bool) {
+	prefixLength := len(apiKeyTag) + apiKeyPrefixBytes*2
+	if strings.HasPrefix(key, apiKeyTag) {
+		if len(key) != prefixLength+1+apiKeySecretBytes*2 || key[prefixLength] != '_' {
+			return "", false
+		}
+		return key[:prefixLength], true
+	}
+	if len(key) == legacyAPIKeyLength {
+		return key[:apiKeyPrefixBytes*2], true
+	}
+	return "", false
+}
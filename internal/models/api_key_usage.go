@@ -0,0 +1,18 @@
+// internal/models/api_key_usage.go
+package models
+
+import (
+	"time"
+)
+
+// APIKeyUsage counts the requests made with an API key on one day (UTC)
+type APIKeyUsage struct {
+	APIKeyID     uint      `gorm:"primaryKey" json:"-"`
+	Day          time.Time `gorm:"primaryKey;type:date" json:"day"`
+	RequestCount int64     `gorm:"not null;default:0" json:"request_count"`
+}
+
+// TableName overrides the default table name
+func (APIKeyUsage) TableName() string {
+	return "api_key_usages"
+}
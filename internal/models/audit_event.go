@@ -0,0 +1,34 @@
+// internal/models/audit_event.go
+package models
+
+import (
+	"time"
+)
+
+// Audit event outcomes
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// AuditEvent records an authentication or account event, successful or not
+type AuditEvent struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	UserID     *uint     `gorm:"index" json:"user_id"`                  // Account the event concerns, if known
+	ActorID    *uint     `gorm:"index" json:"actor_id"`                 // User who performed it, if authenticated; an admin for admin actions
+	AuthMethod string    `gorm:"size:20" json:"auth_method,omitempty"`  // How the actor authenticated: "jwt" or "api_key"
+	Email      string    `gorm:"size:255;index" json:"email,omitempty"` // Email named by the request, e.g. in a failed login, or of a deleted account
+	Type       string    `gorm:"not null;size:50;index" json:"type"`    // e.g. "login", "api_key_created"
+	Outcome    string    `gorm:"not null;size:20" json:"outcome"`       // "success" or "failure"
+	IPAddress  string    `gorm:"size:45;index" json:"ip_address"`
+	UserAgent  string    `gorm:"size:255" json:"user_agent"`
+	Details    string    `gorm:"type:text" json:"details,omitempty"`
+	CreatedAt  time.Time `gorm:"index" json:"created_at"`
+
+	User *User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName overrides the default table name
+func (AuditEvent) TableName() string {
+	return "audit_events"
+}
@@ -0,0 +1,24 @@
+// internal/models/exchange_rate.go
+package models
+
+import (
+	"time"
+
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+// ExchangeRate is the rate between two ISO 4217 currencies on one day:
+// one unit of Base buys Rate units of Quote. Rates are shared by all users.
+type ExchangeRate struct {
+	Base      string     `gorm:"primaryKey;size:3" json:"base" example:"EUR"`
+	Quote     string     `gorm:"primaryKey;size:3" json:"quote" example:"USD"`
+	Date      time.Time  `gorm:"primaryKey;type:date" json:"date"`
+	Rate      money.Rate `gorm:"type:numeric(20,10);not null" json:"rate" swaggertype:"number" example:"1.0305"`
+	Source    string     `gorm:"size:100" json:"source" example:"eurofxref-hist.xml"` // File the rate was imported from
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// TableName overrides the default table name
+func (ExchangeRate) TableName() string {
+	return "exchange_rates"
+}
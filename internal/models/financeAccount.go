@@ -0,0 +1,47 @@
+package models
+
+import (
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+// Finance account types
+const (
+	AccountTypeChecking   = "checking"
+	AccountTypeSavings    = "savings"
+	AccountTypeCash       = "cash"
+	AccountTypeCreditCard = "credit_card"
+	AccountTypeLoan       = "loan"
+)
+
+// FinanceAccount is where money is kept or owed: a bank account, wallet, credit card or loan.
+// Its balance is the opening balance plus the income and transfers in, minus the expenses
+// and transfers out recorded against it, all in the account's currency. Money owed (credit cards, loans) is a negative balance.
+type FinanceAccount struct {
+	gorm.Model
+	UserID         uint        `gorm:"not null;index" json:"user_id"`                                                                    // Which user owns this account
+	Name           string      `gorm:"not null;size:100" json:"name"`                                                                    // e.g., "Everyday checking", "Visa"
+	Type           string      `gorm:"not null;size:20" json:"type"`                                                                     // "checking", "savings", "cash", "credit_card" or "loan"
+	Currency       string      `gorm:"not null;size:3" json:"currency" example:"USD"`                                                    // ISO 4217 code of the balance and every journal in the account
+	OpeningBalance money.Money `gorm:"type:decimal(15,2);not null;default:0" json:"opening_balance" swaggertype:"number" example:"0.00"` // Balance before the first journal, negative for debts
+	IsActive       bool        `gorm:"default:true" json:"is_active"`                                                                    // Closed accounts are kept for their history
+
+	// Relationships
+	User     User             `gorm:"foreignKey:UserID" json:"-"`                     // Belongs to a user
+	Journals []FinanceJournal `gorm:"foreignKey:AccountID" json:"journals,omitempty"` // Has many journals
+}
+
+// TableName overrides the default table name
+func (FinanceAccount) TableName() string {
+	return "finance_accounts"
+}
+
+// IsValidAccountType reports whether t is one of the finance account types
+func IsValidAccountType(t string) bool {
+	switch t {
+	case AccountTypeChecking, AccountTypeSavings, AccountTypeCash, AccountTypeCreditCard, AccountTypeLoan:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,38 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+// FinanceBudget is a monthly spending limit for an expense category. A budget covers
+// the months from StartMonth to EndMonth; a one-off budget starts and ends in the same
+// month, and a repeating one has no EndMonth. When several budgets cover a month, the
+// one that started last applies, so a one-off budget overrides a repeating one.
+type FinanceBudget struct {
+	gorm.Model
+	UserID     uint        `gorm:"not null;index" json:"user_id"`                                                   // Which user set this budget
+	CategoryID uint        `gorm:"not null;index" json:"category_id"`                                               // Which expense category it limits
+	Amount     money.Money `gorm:"type:decimal(15,2);not null" json:"amount" swaggertype:"number" example:"400.00"` // Spending limit per month
+	Currency   string      `gorm:"not null;size:3" json:"currency" example:"USD"`                                   // ISO 4217 code of Amount; spending in other currencies is converted
+	StartMonth time.Time   `gorm:"not null;index;type:date" json:"start_month"`                                     // First day of the first month covered
+	EndMonth   *time.Time  `gorm:"type:date" json:"end_month"`                                                      // First day of the last month covered, null to repeat indefinitely
+	Rollover   bool        `gorm:"default:false" json:"rollover"`                                                   // Carry the previous month's unspent or overspent amount into each month
+
+	// Relationships
+	User     User             `gorm:"foreignKey:UserID" json:"-"`                      // Belongs to a user
+	Category *FinanceCategory `gorm:"foreignKey:CategoryID" json:"category,omitempty"` // Belongs to a category
+}
+
+// TableName overrides the default table name
+func (FinanceBudget) TableName() string {
+	return "finance_budgets"
+}
+
+// Covers reports whether the budget applies in the month starting on month
+func (fb *FinanceBudget) Covers(month time.Time) bool {
+	return !fb.StartMonth.After(month) && (fb.EndMonth == nil || !fb.EndMonth.Before(month))
+}
@@ -4,28 +4,42 @@ import (
 	"time"
 
 	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+// Journal types. Transfer legs move money between the user's own accounts and
+// are not counted as income or expense.
+const (
+	JournalTypeIncome      = "income"
+	JournalTypeExpense     = "expense"
+	JournalTypeTransferOut = "transfer_out"
+	JournalTypeTransferIn  = "transfer_in"
 )
 
-// FinanceJournal represents individual income/expense transactions
+// FinanceJournal represents individual income/expense transactions and the legs of transfers
 type FinanceJournal struct {
 	gorm.Model
-	UserID        uint      `gorm:"not null;index" json:"user_id"`             // Which user made this transaction
-	CategoryID    uint      `gorm:"not null;index" json:"category_id"`         // Which category (Food, Rent, etc.)
-	Type          string    `gorm:"not null;size:20;index" json:"type"`        // "income" or "expense"
-	Amount        float64   `gorm:"type:decimal(15,2);not null" json:"amount"` // Transaction amount (always positive)
-	Title         string    `gorm:"not null;size:255" json:"title"`            // e.g., "Grocery shopping at Walmart"
-	Description   string    `gorm:"type:text" json:"description"`              // Optional detailed notes
-	Date          time.Time `gorm:"not null;index;type:date" json:"date"`      // Transaction date (for daily tracking)
-	PaymentMethod string    `gorm:"size:50" json:"payment_method"`             // "cash", "credit_card", "bank_transfer", etc.
-	Location      string    `gorm:"size:255" json:"location"`                  // Where transaction occurred (optional)
-	IsRecurring   bool      `gorm:"default:false" json:"is_recurring"`         // Is this a recurring transaction?
+	UserID        uint        `gorm:"not null;index" json:"user_id"`                                                  // Which user made this transaction
+	CategoryID    *uint       `gorm:"index" json:"category_id"`                                                       // Which category (Food, Rent, etc.), null for transfer legs
+	AccountID     uint        `gorm:"not null;index" json:"account_id"`                                               // Which account the money moved in or out of
+	TransferID    *uint       `gorm:"index" json:"transfer_id,omitempty"`                                             // The transfer this entry is a leg of, if any
+	Type          string      `gorm:"not null;size:20;index" json:"type"`                                             // "income", "expense", "transfer_out" or "transfer_in"
+	Amount        money.Money `gorm:"type:decimal(15,2);not null" json:"amount" swaggertype:"number" example:"25.50"` // Transaction amount (always positive)
+	Currency      string      `gorm:"not null;size:3;default:'USD'" json:"currency" example:"USD"`                    // ISO 4217 code of Amount, always the account's currency
+	Title         string      `gorm:"not null;size:255" json:"title"`                                                 // e.g., "Grocery shopping at Walmart"
+	Description   string      `gorm:"type:text" json:"description"`                                                   // Optional detailed notes
+	Date          time.Time   `gorm:"not null;index;type:date" json:"date"`                                           // Transaction date (for daily tracking)
+	PaymentMethod string      `gorm:"size:50" json:"payment_method"`                                                  // "cash", "credit_card", "bank_transfer", etc.
+	Location      string      `gorm:"size:255" json:"location"`                                                       // Where transaction occurred (optional)
+	IsRecurring   bool        `gorm:"default:false" json:"is_recurring"`                                              // Is this a recurring transaction?
 
 	// Attachments (optional - for receipts)
 	ReceiptURL string `gorm:"size:500" json:"receipt_url"` // URL to uploaded receipt image
 
 	// Relationships
-	User     User            `gorm:"foreignKey:UserID" json:"-"`                      // Belongs to a user
-	Category FinanceCategory `gorm:"foreignKey:CategoryID" json:"category,omitempty"` // Belongs to a category
+	User     User             `gorm:"foreignKey:UserID" json:"-"`                      // Belongs to a user
+	Category *FinanceCategory `gorm:"foreignKey:CategoryID" json:"category,omitempty"` // Belongs to a category
 }
 
 // TableName overrides the default table name
@@ -36,16 +50,16 @@ func (FinanceJournal) TableName() string {
 // BeforeCreate hook to auto-set type from category and validate
 func (fj *FinanceJournal) BeforeCreate(tx *gorm.DB) error {
 	// Auto-set type from category if not set
-	if fj.Type == "" {
+	if fj.Type == "" && fj.CategoryID != nil {
 		var category FinanceCategory
-		if err := tx.First(&category, fj.CategoryID).Error; err == nil {
+		if err := tx.First(&category, *fj.CategoryID).Error; err == nil {
 			fj.Type = category.Type
 		}
 	}
 
 	// Validate type
-	if fj.Type != "income" && fj.Type != "expense" {
-		fj.Type = "expense" // Default to expense
+	if !fj.IsTransfer() && fj.Type != JournalTypeIncome {
+		fj.Type = JournalTypeExpense // Default to expense
 	}
 
 	// Ensure amount is positive
@@ -60,3 +74,8 @@ func (fj *FinanceJournal) BeforeCreate(tx *gorm.DB) error {
 
 	return nil
 }
+
+// IsTransfer reports whether the entry is a leg of a transfer
+func (fj *FinanceJournal) IsTransfer() bool {
+	return fj.Type == JournalTypeTransferOut || fj.Type == JournalTypeTransferIn
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/jedi116/kaizen-api/internal/money"
+)
+
+// FinanceTransfer moves money between two of a user's accounts. It is recorded as two
+// journals, a transfer_out leg on the source account and a transfer_in leg on the
+// destination, which are always created, changed and deleted together. Transfers change
+// account balances but are neither income nor expense.
+type FinanceTransfer struct {
+	gorm.Model
+	UserID        uint        `gorm:"not null;index" json:"user_id"`                                                      // Which user made this transfer
+	FromAccountID uint        `gorm:"not null;index" json:"from_account_id"`                                              // Account the money leaves
+	ToAccountID   uint        `gorm:"not null;index" json:"to_account_id"`                                                // Account the money arrives in
+	Amount        money.Money `gorm:"type:decimal(15,2);not null" json:"amount" swaggertype:"number" example:"500.00"`    // Taken from the source account, in its currency
+	ToAmount      money.Money `gorm:"type:decimal(15,2);not null" json:"to_amount" swaggertype:"number" example:"460.25"` // Added to the destination account, in its currency; equals Amount unless the currencies differ
+	Title         string      `gorm:"not null;size:255" json:"title"`                                                     // e.g., "Move to savings"
+	Description   string      `gorm:"type:text" json:"description"`                                                       // Optional detailed notes
+	Date          time.Time   `gorm:"not null;index;type:date" json:"date"`                                               // Transfer date
+
+	// Relationships
+	User        User             `gorm:"foreignKey:UserID" json:"-"`                      // Belongs to a user
+	FromAccount FinanceAccount   `gorm:"foreignKey:FromAccountID" json:"-"`               // Source account
+	ToAccount   FinanceAccount   `gorm:"foreignKey:ToAccountID" json:"-"`                 // Destination account
+	Journals    []FinanceJournal `gorm:"foreignKey:TransferID" json:"journals,omitempty"` // The two legs
+}
+
+// TableName overrides the default table name
+func (FinanceTransfer) TableName() string {
+	return "finance_transfers"
+}
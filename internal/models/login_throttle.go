@@ -0,0 +1,25 @@
+// internal/models/login_throttle.go
+package models
+
+import (
+	"time"
+)
+
+// LoginThrottle counts recent failed logins for one key, such as an account
+// ("account:john@example.com") or a client IP ("ip:203.0.113.7"). A login is
+// counted when it starts and given back once its credentials turn out right.
+// Magic link and password reset requests are counted the same way, under keys
+// such as "magic_link:john@example.com" and "password_reset_ip:203.0.113.7".
+// It is stored in the database so limits survive restarts and apply across instances.
+type LoginThrottle struct {
+	Key           string     `gorm:"primaryKey;size:300"`
+	Failures      int        `gorm:"not null;default:0"`
+	LastFailureAt time.Time  `gorm:"not null"`
+	LockedUntil   *time.Time // Set once failures reach the lockout threshold
+	UpdatedAt     time.Time  `gorm:"index"`
+}
+
+// TableName overrides the default table name
+func (LoginThrottle) TableName() string {
+	return "login_throttles"
+}
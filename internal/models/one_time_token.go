@@ -0,0 +1,27 @@
+// internal/models/one_time_token.go
+package models
+
+import (
+	"time"
+)
+
+// OneTimeToken is a single-use secret delivered to the user, e.g. in a password reset link.
+// Only the SHA-256 hash of the secret is stored.
+type OneTimeToken struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null;index"`
+	Purpose   string     `gorm:"not null;size:30"` // e.g. "password_reset"
+	TokenHash string     `gorm:"uniqueIndex;not null;size:64"`
+	Payload   string     `gorm:"size:255"`           // Purpose specific data, e.g. the new address for an email change or the hashed device nonce of a magic link
+	Attempts  int        `gorm:"not null;default:0"` // Codes tried against a two-factor challenge
+	ExpiresAt time.Time  `gorm:"not null;index"`
+	UsedAt    *time.Time // Set when the token is consumed
+	CreatedAt time.Time
+
+	User User `gorm:"foreignKey:UserID"`
+}
+
+// TableName overrides the default table name
+func (OneTimeToken) TableName() string {
+	return "one_time_tokens"
+}
@@ -0,0 +1,23 @@
+// internal/models/recovery_code.go
+package models
+
+import (
+	"time"
+)
+
+// RecoveryCode is a single-use backup code for two-factor authentication.
+// Only the SHA-256 hash of the code is stored.
+type RecoveryCode struct {
+	ID        uint       `gorm:"primaryKey"`
+	UserID    uint       `gorm:"not null;index"`
+	CodeHash  string     `gorm:"not null;size:64"`
+	UsedAt    *time.Time // Set when the code is redeemed
+	CreatedAt time.Time
+
+	User User `gorm:"foreignKey:UserID"`
+}
+
+// TableName overrides the default table name
+func (RecoveryCode) TableName() string {
+	return "recovery_codes"
+}
@@ -0,0 +1,35 @@
+// internal/models/scheduled_job.go
+package models
+
+import (
+	"time"
+)
+
+// Scheduled job statuses
+const (
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+)
+
+// ScheduledJob is the shared state of a background job, used to coordinate
+// instances and to report when the job last ran
+type ScheduledJob struct {
+	Name           string     `gorm:"primaryKey;size:100" json:"name"`
+	Schedule       string     `gorm:"not null;size:100" json:"schedule"`
+	LastDueAt      *time.Time `json:"-"`                           // Scheduled time of the last claimed run, so instances don't repeat it
+	LastStartedAt  *time.Time `json:"last_started_at"`             // When the last run started
+	LastFinishedAt *time.Time `json:"last_finished_at"`            // When the last run finished
+	LastStatus     string     `gorm:"size:20" json:"last_status"`  // "running", "succeeded" or "failed"
+	LastError      string     `gorm:"type:text" json:"last_error"` // Error of the last failed run
+	LastRunBy      string     `gorm:"size:255" json:"last_run_by"` // Instance that ran it last
+	NextRunAt      *time.Time `json:"next_run_at"`                 // Next scheduled time, before jitter
+	RunCount       int64      `gorm:"not null;default:0" json:"run_count"`
+	FailureCount   int64      `gorm:"not null;default:0" json:"failure_count"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// TableName overrides the default table name
+func (ScheduledJob) TableName() string {
+	return "scheduled_jobs"
+}
@@ -0,0 +1,25 @@
+// internal/models/session.go
+package models
+
+import (
+	"time"
+)
+
+// Session is a signed-in device. Its ID is the family ID shared by every
+// access and refresh token issued to that device since it logged in.
+type Session struct {
+	ID         string    `gorm:"primaryKey;size:100" json:"id"`
+	UserID     uint      `gorm:"not null;index" json:"-"`
+	UserAgent  string    `gorm:"size:255" json:"user_agent"`
+	IPAddress  string    `gorm:"size:45" json:"ip_address"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastSeenAt time.Time `json:"last_seen_at"`                     // Updated on login and every token refresh
+	ExpiresAt  time.Time `gorm:"not null;index" json:"expires_at"` // Expiry of the latest refresh token
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName overrides the default table name
+func (Session) TableName() string {
+	return "sessions"
+}
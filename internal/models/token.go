@@ -5,10 +5,12 @@ import (
 )
 
 type Token struct {
-	ID        string    `gorm:"primaryKey;size:100"`
-	UserID    uint      `gorm:"not null;index"`
-	Type      string    `gorm:"not null;size:20"` // "access" or "refresh"
-	ExpiresAt time.Time `gorm:"not null;index"`
+	ID        string     `gorm:"primaryKey;size:100"`
+	UserID    uint       `gorm:"not null;index"`
+	FamilyID  string     `gorm:"not null;size:100;index"` // Tokens descended from the same login share a family
+	Type      string     `gorm:"not null;size:20"`        // "access" or "refresh"
+	ExpiresAt time.Time  `gorm:"not null;index"`
+	RotatedAt *time.Time // Set when a refresh token is exchanged; kept to detect reuse
 	CreatedAt time.Time
 
 	User User `gorm:"foreignKey:UserID"`
@@ -0,0 +1,23 @@
+// internal/models/user_identity.go
+package models
+
+import (
+	"time"
+)
+
+// UserIdentity links a user to an account at an external OpenID Connect provider
+type UserIdentity struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	UserID    uint      `gorm:"not null;index" json:"-"`
+	Provider  string    `gorm:"not null;size:50;uniqueIndex:idx_user_identities_provider_subject" json:"provider"`
+	Subject   string    `gorm:"not null;size:255;uniqueIndex:idx_user_identities_provider_subject" json:"-"` // The provider's stable "sub" claim
+	Email     string    `gorm:"size:255" json:"email"`                                                       // Email reported by the provider when linked
+	CreatedAt time.Time `json:"created_at"`
+
+	User User `gorm:"foreignKey:UserID" json:"-"`
+}
+
+// TableName overrides the default table name
+func (UserIdentity) TableName() string {
+	return "user_identities"
+}
@@ -0,0 +1,194 @@
+// internal/money/money.go
+package money
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Scale is the number of decimal places amounts are kept to, matching the
+// decimal(15,2) columns they are stored in
+const Scale = 2
+
+// Max is the largest amount a decimal(15,2) column can hold
+const Max Money = 999_999_999_999_999
+
+const unit = 100 // 10^Scale
+
+var (
+	ErrInvalid    = errors.New("amount must be a decimal number such as 25.50")
+	ErrScale      = fmt.Errorf("amount has more than %d decimal places", Scale)
+	ErrOutOfRange = errors.New("amount is out of range")
+)
+
+// Money is an exact amount in minor units (cents). Sums and differences are
+// plain integer arithmetic, so they never drift the way float64 totals do.
+//
+// In JSON it is written as a number with exactly two decimals (25.50) and read
+// from either a number or a string ("25.50"). In the database it is read from
+// and written to numeric columns as decimal text.
+type Money int64
+
+// FromCents returns the amount for a number of minor units
+func FromCents(cents int64) Money {
+	return Money(cents)
+}
+
+// Cents returns the amount in minor units
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Parse reads a plain decimal such as "25.5", "-3.05" or "100". Digits past
+// Scale are only accepted when they are zeros, so nothing is ever rounded away.
+func Parse(s string) (Money, error) {
+	if s == "" {
+		return 0, ErrInvalid
+	}
+
+	negative := s[0] == '-'
+	if negative {
+		s = s[1:]
+	}
+
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if whole == "" || (hasPoint && frac == "") || !isDigits(whole) || !isDigits(frac) {
+		return 0, ErrInvalid
+	}
+	if len(frac) > Scale {
+		if strings.Trim(frac[Scale:], "0") != "" {
+			return 0, ErrScale
+		}
+		frac = frac[:Scale]
+	}
+	frac += strings.Repeat("0", Scale-len(frac))
+
+	whole = strings.TrimLeft(whole, "0")
+	if whole == "" {
+		whole = "0"
+	}
+	units, err := strconv.ParseInt(whole+frac, 10, 64)
+	if err != nil {
+		return 0, ErrOutOfRange
+	}
+
+	if negative {
+		units = -units
+	}
+	return Money(units), nil
+}
+
+// MustParse is like Parse but panics on error. It is meant for constants.
+func MustParse(s string) Money {
+	m, err := Parse(s)
+	if err != nil {
+		panic(fmt.Sprintf("money: %q: %v", s, err))
+	}
+	return m
+}
+
+// String formats the amount with exactly Scale decimals, e.g. "25.50" or "-0.05"
+func (m Money) String() string {
+	units := uint64(m)
+	sign := ""
+	if m < 0 {
+		sign = "-"
+		units = -units // Also correct for math.MinInt64
+	}
+	return fmt.Sprintf("%s%d.%0*d", sign, units/unit, Scale, units%unit)
+}
+
+// Abs returns the absolute value of m
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// MarshalJSON writes the amount as a JSON number with exactly Scale decimals
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or string. Numbers are read from their
+// text, never through float64. null leaves the amount unchanged.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	text := string(data)
+	if len(data) > 0 && data[0] == '"' {
+		if err := json.Unmarshal(data, &text); err != nil {
+			return ErrInvalid
+		}
+		text = strings.TrimSpace(text)
+	}
+
+	parsed, err := Parse(text)
+	if err != nil {
+		return err
+	}
+	if parsed.Abs() > Max {
+		return ErrOutOfRange
+	}
+	*m = parsed
+	return nil
+}
+
+// Scan implements sql.Scanner for numeric columns. NULL, as returned by SUM
+// over no rows, scans as zero.
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case string:
+		return m.scanText(v)
+	case []byte:
+		return m.scanText(string(v))
+	case int64:
+		if v > math.MaxInt64/unit || v < math.MinInt64/unit {
+			return ErrOutOfRange
+		}
+		*m = Money(v * unit)
+		return nil
+	case float64:
+		// Only seen when a query casts to float; the scale check rejects values
+		// that float64 can't represent exactly at two decimals
+		return m.scanText(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return fmt.Errorf("money: cannot scan %T", src)
+	}
+}
+
+func (m *Money) scanText(s string) error {
+	parsed, err := Parse(s)
+	if err != nil {
+		return fmt.Errorf("money: cannot scan %q: %w", s, err)
+	}
+	*m = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, writing the amount as decimal text
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
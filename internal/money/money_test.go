@@ -0,0 +1,228 @@
+// internal/money/money_test.go
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// amount generates values within the range of a decimal(15,2) column
+type amount Money
+
+func (amount) Generate(r *rand.Rand, size int) reflect.Value {
+	var cents int64
+	switch r.Intn(3) {
+	case 0: // Small everyday amounts, where float drift is most visible
+		cents = r.Int63n(100_000)
+	case 1:
+		cents = r.Int63n(int64(Max) + 1)
+	default:
+		cents = r.Int63n(100)
+	}
+	if r.Intn(2) == 0 {
+		cents = -cents
+	}
+	return reflect.ValueOf(amount(cents))
+}
+
+func toMoney(amounts []amount) []Money {
+	values := make([]Money, len(amounts))
+	for i, a := range amounts {
+		values[i] = Money(a)
+	}
+	return values
+}
+
+// decimalSum adds the formatted amounts as arbitrary-precision decimals,
+// independently of Money's integer representation
+func decimalSum(values []Money) *big.Rat {
+	sum := new(big.Rat)
+	for _, v := range values {
+		r, ok := new(big.Rat).SetString(v.String())
+		if !ok {
+			panic("unparseable amount " + v.String())
+		}
+		sum.Add(sum, r)
+	}
+	return sum
+}
+
+func TestSumIsExact(t *testing.T) {
+	property := func(amounts []amount) bool {
+		values := toMoney(amounts)
+		var sum Money
+		for _, v := range values {
+			sum += v
+		}
+		return decimalSum(values).FloatString(Scale) == sum.String()
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestSumIsOrderIndependent(t *testing.T) {
+	property := func(amounts []amount, seed int64) bool {
+		values := toMoney(amounts)
+		var forward, shuffled Money
+		for _, v := range values {
+			forward += v
+		}
+		rand.New(rand.NewSource(seed)).Shuffle(len(values), func(i, j int) {
+			values[i], values[j] = values[j], values[i]
+		})
+		for _, v := range values {
+			shuffled += v
+		}
+		return forward == shuffled
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRepeatedCentsSumExactly(t *testing.T) {
+	// Summing 0.10 ten thousand times is the classic float64 failure
+	var sum Money
+	for i := 0; i < 10_000; i++ {
+		sum += MustParse("0.10")
+	}
+	if sum.String() != "1000.00" {
+		t.Errorf("sum = %s, want 1000.00", sum)
+	}
+}
+
+func TestStringParseRoundTrip(t *testing.T) {
+	property := func(a amount) bool {
+		parsed, err := Parse(Money(a).String())
+		return err == nil && parsed == Money(a)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	property := func(a amount) bool {
+		data, err := json.Marshal(Money(a))
+		if err != nil {
+			return false
+		}
+
+		var fromNumber, fromString Money
+		if err := json.Unmarshal(data, &fromNumber); err != nil {
+			return false
+		}
+		quoted, _ := json.Marshal(string(data))
+		if err := json.Unmarshal(quoted, &fromString); err != nil {
+			return false
+		}
+		return fromNumber == Money(a) && fromString == Money(a)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestScanValueRoundTrip(t *testing.T) {
+	property := func(a amount) bool {
+		value, err := Money(a).Value()
+		if err != nil {
+			return false
+		}
+
+		var fromText, fromBytes Money
+		if err := fromText.Scan(value); err != nil {
+			return false
+		}
+		if err := fromBytes.Scan([]byte(value.(string))); err != nil {
+			return false
+		}
+		return fromText == Money(a) && fromBytes == Money(a)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseRejectsExtraScale(t *testing.T) {
+	property := func(a amount, extra uint8) bool {
+		digit := extra%9 + 1 // A non-zero third decimal
+		_, err := Parse(fmt.Sprintf("%s%d", Money(a).String(), digit))
+		return errors.Is(err, ErrScale)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Money
+		err  error
+	}{
+		{"25.50", 2550, nil},
+		{"25.5", 2550, nil},
+		{"25", 2500, nil},
+		{"-3.05", -305, nil},
+		{"0.01", 1, nil},
+		{"007.10", 710, nil},
+		{"1.500", 150, nil},
+		{"1.505", 0, ErrScale},
+		{"", 0, ErrInvalid},
+		{"-", 0, ErrInvalid},
+		{".5", 0, ErrInvalid},
+		{"5.", 0, ErrInvalid},
+		{"+5", 0, ErrInvalid},
+		{"1e3", 0, ErrInvalid},
+		{"1,000.00", 0, ErrInvalid},
+		{"99999999999999999999", 0, ErrOutOfRange},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.in)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("Parse(%q) = %v, %v; want %v, %v", tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestUnmarshalJSON(t *testing.T) {
+	var req struct {
+		Amount Money `json:"amount"`
+	}
+	for _, body := range []string{`{"amount": 25.50}`, `{"amount": "25.50"}`, `{"amount": "25.5"}`} {
+		req.Amount = 0
+		if err := json.Unmarshal([]byte(body), &req); err != nil || req.Amount != 2550 {
+			t.Errorf("%s: got %v, %v", body, req.Amount, err)
+		}
+	}
+
+	for _, body := range []string{`{"amount": 25.505}`, `{"amount": "abc"}`, `{"amount": 1e3}`, `{"amount": 10000000000000.00}`, `{"amount": true}`} {
+		if err := json.Unmarshal([]byte(body), &req); err == nil {
+			t.Errorf("%s: expected an error", body)
+		}
+	}
+}
+
+func TestString(t *testing.T) {
+	tests := map[Money]string{
+		0:     "0.00",
+		5:     "0.05",
+		-5:    "-0.05",
+		2550:  "25.50",
+		-2550: "-25.50",
+		Max:   "9999999999999.99",
+	}
+	for m, want := range tests {
+		if got := m.String(); got != want {
+			t.Errorf("Money(%d).String() = %q, want %q", int64(m), got, want)
+		}
+	}
+}
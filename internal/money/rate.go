@@ -0,0 +1,117 @@
+// internal/money/rate.go
+package money
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// RateScale is the number of decimal places rates are stored with, matching
+// the numeric(20,10) exchange_rates.rate column
+const RateScale = 10
+
+var ErrInvalidRate = fmt.Errorf("rate must be a positive decimal number with at most %d decimal places, such as 1.0845", RateScale)
+
+// Rate is an exact exchange rate: one unit of the base currency buys Rate
+// units of the quote currency. The zero value is not a valid rate.
+type Rate struct {
+	rat *big.Rat
+}
+
+// One is the rate between a currency and itself
+var One = Rate{rat: big.NewRat(1, 1)}
+
+// ParseRate reads a positive plain decimal such as "1.0845" or "162.5" that
+// fits a numeric(20,10) column
+func ParseRate(s string) (Rate, error) {
+	s = strings.TrimSpace(s)
+	whole, frac, hasPoint := strings.Cut(s, ".")
+	if whole == "" || (hasPoint && frac == "") || !isDigits(whole) || !isDigits(frac) {
+		return Rate{}, ErrInvalidRate
+	}
+	if len(strings.TrimLeft(whole, "0")) > 20-RateScale || len(strings.TrimRight(frac, "0")) > RateScale {
+		return Rate{}, ErrInvalidRate
+	}
+	rat, ok := new(big.Rat).SetString(s)
+	if !ok || rat.Sign() <= 0 {
+		return Rate{}, ErrInvalidRate
+	}
+	return Rate{rat: rat}, nil
+}
+
+// IsValid reports whether r holds a positive rate
+func (r Rate) IsValid() bool {
+	return r.rat != nil && r.rat.Sign() > 0
+}
+
+// Inverse returns the rate in the opposite direction
+func (r Rate) Inverse() Rate {
+	return Rate{rat: new(big.Rat).Inv(r.rat)}
+}
+
+// Mul chains two rates, e.g. GBP→EUR times EUR→USD gives GBP→USD
+func (r Rate) Mul(other Rate) Rate {
+	return Rate{rat: new(big.Rat).Mul(r.rat, other.rat)}
+}
+
+// Convert multiplies m by the rate, rounding half away from zero to whole
+// minor units. Only the result is rounded, so chained rates lose nothing.
+// Returns ErrOutOfRange if the result doesn't fit a decimal(15,2) column.
+func (r Rate) Convert(m Money) (Money, error) {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(m)), r.rat)
+
+	quotient, remainder := new(big.Int).QuoRem(product.Num(), product.Denom(), new(big.Int))
+	// Round up when the remainder is at least half the denominator
+	if remainder.Sign() != 0 && new(big.Int).Mul(new(big.Int).Abs(remainder), big.NewInt(2)).Cmp(product.Denom()) >= 0 {
+		quotient.Add(quotient, big.NewInt(int64(remainder.Sign())))
+	}
+	if quotient.CmpAbs(big.NewInt(int64(Max))) > 0 {
+		return 0, ErrOutOfRange
+	}
+	return Money(quotient.Int64()), nil
+}
+
+// String formats the rate with up to RateScale decimals, without trailing zeros
+func (r Rate) String() string {
+	if r.rat == nil {
+		return "0"
+	}
+	s := r.rat.FloatString(RateScale)
+	s = strings.TrimRight(s, "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+// MarshalJSON writes the rate as a JSON number
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+// Scan implements sql.Scanner for numeric columns
+func (r *Rate) Scan(src interface{}) error {
+	var s string
+	switch v := src.(type) {
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("money: cannot scan %T into a rate", src)
+	}
+
+	parsed, err := ParseRate(s)
+	if err != nil {
+		return fmt.Errorf("money: cannot scan rate %q: %w", s, err)
+	}
+	*r = parsed
+	return nil
+}
+
+// Value implements driver.Valuer, writing the rate as decimal text
+func (r Rate) Value() (driver.Value, error) {
+	if !r.IsValid() {
+		return nil, ErrInvalidRate
+	}
+	return r.rat.FloatString(RateScale), nil
+}
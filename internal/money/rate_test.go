@@ -0,0 +1,141 @@
+// internal/money/rate_test.go
+package money
+
+import (
+	"errors"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+)
+
+// rate generates valid rates, from tiny ones like JPY→BTC to large ones like BTC→IDR
+type rate Rate
+
+func (rate) Generate(r *rand.Rand, size int) reflect.Value {
+	var s string
+	switch r.Intn(3) {
+	case 0:
+		s = "0.0000" + string(rune('1'+r.Intn(9)))
+	case 1:
+		s = string(rune('1'+r.Intn(9))) + "." + string(rune('0'+r.Intn(10))) + string(rune('0'+r.Intn(10)))
+	default:
+		s = string(rune('1'+r.Intn(9))) + "00000000.5"
+	}
+	parsed, err := ParseRate(s)
+	if err != nil {
+		panic(err)
+	}
+	return reflect.ValueOf(rate(parsed))
+}
+
+func mustParseRate(t *testing.T, s string) Rate {
+	t.Helper()
+	r, err := ParseRate(s)
+	if err != nil {
+		t.Fatalf("ParseRate(%q): %v", s, err)
+	}
+	return r
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"1.0845", "1.0845"},
+		{"162.5", "162.5"},
+		{" 0.91 ", "0.91"},
+		{"007.10", "7.1"},
+		{"1.1000000000000", "1.1"},
+		{"9999999999.9999999999", "9999999999.9999999999"},
+		{"0.0000000001", "0.0000000001"},
+	}
+	for _, tt := range tests {
+		r, err := ParseRate(tt.in)
+		if err != nil || r.String() != tt.want {
+			t.Errorf("ParseRate(%q) = %v, %v; want %s", tt.in, r, err, tt.want)
+		}
+	}
+
+	for _, in := range []string{
+		"", "0", "0.0", "-1.5", "+1.5", ".5", "5.", "1e3", "1,5", "abc",
+		"10000000000",   // More whole digits than numeric(20,10) holds
+		"1.00000000001", // More decimals than RateScale
+	} {
+		if _, err := ParseRate(in); !errors.Is(err, ErrInvalidRate) {
+			t.Errorf("ParseRate(%q) = %v, want ErrInvalidRate", in, err)
+		}
+	}
+}
+
+func TestRateConvert(t *testing.T) {
+	tests := []struct {
+		rate string
+		in   Money
+		want Money
+		err  error
+	}{
+		{"1.0845", 10000, 10845, nil},
+		{"1.5", 101, 152, nil}, // 151.5 rounds away from zero
+		{"1.5", -101, -152, nil},
+		{"1.5", 100, 150, nil},
+		{"0.5", 1, 1, nil},
+		{"0.5", -1, -1, nil},
+		{"0.4", 1, 0, nil},
+		{"162.5", 0, 0, nil},
+		{"2", Max / 2, Max - 1, nil},
+		{"2", Max/2 + 1, 0, ErrOutOfRange},
+		{"1000", -Max, 0, ErrOutOfRange},
+		{"9999999999", Max, 0, ErrOutOfRange}, // Past int64, not just decimal(15,2)
+	}
+	for _, tt := range tests {
+		got, err := mustParseRate(t, tt.rate).Convert(tt.in)
+		if !errors.Is(err, tt.err) || got != tt.want {
+			t.Errorf("%s.Convert(%v) = %v, %v; want %v, %v", tt.rate, tt.in, got, err, tt.want, tt.err)
+		}
+	}
+}
+
+func TestChainedRatesRoundOnce(t *testing.T) {
+	// GBP→EUR→USD through two rates converts like the exact product rate
+	gbpEUR := mustParseRate(t, "1.1712")
+	eurUSD := mustParseRate(t, "1.0845")
+	chained, err := gbpEUR.Mul(eurUSD).Convert(12345)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 123.45 × 1.1712 × 1.0845 = 156.8017...
+	if chained != 15680 {
+		t.Errorf("chained conversion = %v, want 156.80", chained)
+	}
+
+	if got := gbpEUR.Mul(gbpEUR.Inverse()); got.String() != "1" {
+		t.Errorf("rate times its inverse = %v, want 1", got)
+	}
+}
+
+func TestConvertByOneIsIdentity(t *testing.T) {
+	property := func(a amount) bool {
+		got, err := One.Convert(Money(a))
+		return err == nil && got == Money(a)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestConvertIsSymmetric(t *testing.T) {
+	// Rounding half away from zero treats credits and debits alike
+	property := func(r rate, a amount) bool {
+		positive, errPositive := Rate(r).Convert(Money(a))
+		negative, errNegative := Rate(r).Convert(-Money(a))
+		if errPositive != nil || errNegative != nil {
+			return errors.Is(errPositive, ErrOutOfRange) && errors.Is(errNegative, ErrOutOfRange)
+		}
+		return positive == -negative
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
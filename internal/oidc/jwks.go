@@ -0,0 +1,170 @@
+// internal/oidc/jwks.go
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+)
+
+// Unknown key IDs trigger a refetch (providers rotate keys), but not more often than this
+const minKeyRefreshInterval = time.Minute
+
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Alg string `json:"alg"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type keyCache struct {
+	url     string
+	getJSON func(ctx context.Context, url string, v interface{}) error
+
+	mu        sync.Mutex
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+func newKeyCache(url string, getJSON func(ctx context.Context, url string, v interface{}) error) *keyCache {
+	return &keyCache{url: url, getJSON: getJSON}
+}
+
+// lookup returns the public key for kid, refetching the key set if kid is unknown
+func (kc *keyCache) lookup(ctx context.Context, kid, alg string) (crypto.PublicKey, error) {
+	kc.mu.Lock()
+	defer kc.mu.Unlock()
+
+	if key, ok := kc.find(kid); ok {
+		return checkKeyType(key, alg)
+	}
+
+	if time.Since(kc.fetchedAt) < minKeyRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if err := kc.refresh(ctx); err != nil {
+		return nil, err
+	}
+
+	key, ok := kc.find(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return checkKeyType(key, alg)
+}
+
+// find matches kid, or the only key when the token has no kid
+func (kc *keyCache) find(kid string) (crypto.PublicKey, bool) {
+	if kid == "" && len(kc.keys) == 1 {
+		for _, key := range kc.keys {
+			return key, true
+		}
+	}
+	key, ok := kc.keys[kid]
+	return key, ok
+}
+
+func (kc *keyCache) refresh(ctx context.Context) error {
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err := kc.getJSON(ctx, kc.url, &set); err != nil {
+		return fmt.Errorf("failed to fetch signing keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey, len(set.Keys))
+	for _, k := range set.Keys {
+		if k.Use != "" && k.Use != "sig" {
+			continue
+		}
+		key, err := k.publicKey()
+		if err != nil {
+			continue // Skip key types we don't support rather than failing every login
+		}
+		keys[k.Kid] = key
+	}
+
+	kc.keys = keys
+	kc.fetchedAt = time.Now()
+	return nil
+}
+
+func (k jwk) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, fmt.Errorf("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// checkKeyType makes sure the token's algorithm matches the key, preventing algorithm confusion
+func checkKeyType(key crypto.PublicKey, alg string) (crypto.PublicKey, error) {
+	var ok bool
+	switch alg[:2] {
+	case "RS":
+		_, ok = key.(*rsa.PublicKey)
+	case "ES":
+		_, ok = key.(*ecdsa.PublicKey)
+	case "Ed":
+		_, ok = key.(ed25519.PublicKey)
+	}
+	if !ok {
+		return nil, fmt.Errorf("signing key does not match algorithm %s", alg)
+	}
+	return key, nil
+}
@@ -0,0 +1,92 @@
+// internal/scheduler/schedule_test.go
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRejectsInvalidSpecs(t *testing.T) {
+	specs := []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * 32 * *",
+		"* * * 0 *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"*/x * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"1-x * * * *",
+		"* * * FOO *",
+		"* * * * MON-FOO",
+		"@every",
+		"@every 500ms",
+		"@every soon",
+		"@fortnightly",
+	}
+	for _, spec := range specs {
+		if _, err := Parse(spec); err == nil {
+			t.Errorf("Parse(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	at := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		spec string
+		from time.Time
+		want time.Time
+	}{
+		{"every minute", "* * * * *", time.Date(2026, 10, 16, 10, 7, 30, 0, time.UTC), at(2026, 10, 16, 10, 8)},
+		{"strictly after", "0 * * * *", at(2026, 10, 16, 10, 0), at(2026, 10, 16, 11, 0)},
+		{"step", "*/15 * * * *", at(2026, 10, 16, 10, 7), at(2026, 10, 16, 10, 15)},
+		{"step into next hour", "*/15 * * * *", at(2026, 10, 16, 10, 45), at(2026, 10, 16, 11, 0)},
+		{"step from start", "5/20 * * * *", at(2026, 10, 16, 10, 6), at(2026, 10, 16, 10, 25)},
+		{"range with step", "1-10/3 * * * *", at(2026, 10, 16, 10, 8), at(2026, 10, 16, 10, 10)},
+		{"range with step wraps", "1-10/3 * * * *", at(2026, 10, 16, 10, 10), at(2026, 10, 16, 11, 1)},
+		{"list", "0,30 9 * * *", at(2026, 10, 16, 9, 0), at(2026, 10, 16, 9, 30)},
+		{"hour range", "0 9-17 * * *", at(2026, 10, 16, 17, 0), at(2026, 10, 17, 9, 0)},
+		{"weekdays by name", "0 3 * * MON-FRI", at(2026, 10, 16, 4, 0), at(2026, 10, 19, 3, 0)},
+		{"sunday as 7", "0 0 * * 7", at(2026, 10, 16, 0, 0), at(2026, 10, 18, 0, 0)},
+		{"sunday as 0", "0 0 * * 0", at(2026, 10, 16, 0, 0), at(2026, 10, 18, 0, 0)},
+		{"day of month only", "0 0 20 * *", at(2026, 10, 16, 0, 0), at(2026, 10, 20, 0, 0)},
+		{"day of month or week", "30 9 1-7 * MON", at(2026, 10, 8, 0, 0), at(2026, 10, 12, 9, 30)},
+		{"day of week or month", "30 9 1-7 * MON", at(2026, 10, 26, 10, 0), at(2026, 11, 1, 9, 30)},
+		{"month rollover skips short months", "0 0 31 * *", at(2026, 4, 1, 0, 0), at(2026, 5, 31, 0, 0)},
+		{"year rollover", "0 0 * * *", at(2026, 12, 31, 23, 59), at(2027, 1, 1, 0, 0)},
+		{"months by name", "0 12 * JAN,JUL *", at(2026, 7, 31, 12, 0), at(2027, 1, 1, 12, 0)},
+		{"leap day", "0 0 29 2 *", at(2026, 3, 1, 0, 0), at(2028, 2, 29, 0, 0)},
+		{"impossible date", "0 0 30 2 *", at(2026, 1, 1, 0, 0), time.Time{}},
+		{"hourly", "@hourly", at(2026, 10, 16, 10, 0), at(2026, 10, 16, 11, 0)},
+		{"daily", "@daily", at(2026, 10, 16, 10, 0), at(2026, 10, 17, 0, 0)},
+		{"weekly", "@weekly", at(2026, 10, 16, 10, 0), at(2026, 10, 18, 0, 0)},
+		{"monthly", "@monthly", at(2026, 10, 16, 10, 0), at(2026, 11, 1, 0, 0)},
+		{"yearly", "@yearly", at(2026, 10, 16, 10, 0), at(2027, 1, 1, 0, 0)},
+		{"evaluated in UTC", "0 3 * * *", time.Date(2026, 10, 16, 4, 0, 0, 0, time.FixedZone("UTC+2", 2*60*60)), at(2026, 10, 16, 3, 0)},
+		{"every", "@every 10m", time.Date(2026, 10, 16, 10, 7, 30, 0, time.UTC), at(2026, 10, 16, 10, 10)},
+		{"every strictly after", "@every 10m", at(2026, 10, 16, 10, 10), at(2026, 10, 16, 10, 20)},
+		{"every hour", "@every 1h", at(2026, 10, 16, 23, 30), at(2026, 10, 17, 0, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schedule, err := Parse(tt.spec)
+			if err != nil {
+				t.Fatalf("Parse(%q): %v", tt.spec, err)
+			}
+			if got := schedule.Next(tt.from); !got.Equal(tt.want) {
+				t.Errorf("Next(%v) = %v, want %v", tt.from, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,222 @@
+// internal/scheduler/scheduler.go
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"log"
+	"math/rand/v2"
+	"os"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+// DefaultJobTimeout limits a run when the job sets no Timeout
+const DefaultJobTimeout = 10 * time.Minute
+
+// Job is a background task run on a schedule
+type Job struct {
+	Name     string        // Unique name, also used for the advisory lock
+	Schedule string        // Cron expression or descriptor, see Parse
+	Jitter   time.Duration // Up to this much random delay is added to each run, spreading load
+	Timeout  time.Duration // Cancels the run's context after this long (default DefaultJobTimeout)
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler runs jobs on every instance, but each scheduled run executes on only
+// one of them: the instance holding the job's Postgres advisory lock, provided no
+// other instance has already claimed that run in the scheduled_jobs table.
+type Scheduler struct {
+	db       *gorm.DB
+	instance string
+	jobs     []*job
+
+	cancel  context.CancelFunc
+	running sync.WaitGroup
+}
+
+type job struct {
+	Job
+	schedule Schedule
+	lockKey  int64
+}
+
+// New creates a scheduler that coordinates through db
+func New(db *gorm.DB) *Scheduler {
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = "unknown"
+	}
+	return &Scheduler{db: db, instance: fmt.Sprintf("%s/%d", instance, os.Getpid())}
+}
+
+// Add registers a job. Jobs must be added before Start.
+func (s *Scheduler) Add(j Job) error {
+	if j.Name == "" || j.Run == nil {
+		return fmt.Errorf("job needs a name and a run function")
+	}
+	for _, existing := range s.jobs {
+		if existing.Name == j.Name {
+			return fmt.Errorf("job %q is already registered", j.Name)
+		}
+	}
+
+	schedule, err := Parse(j.Schedule)
+	if err != nil {
+		return fmt.Errorf("job %q: %w", j.Name, err)
+	}
+	if j.Timeout <= 0 {
+		j.Timeout = DefaultJobTimeout
+	}
+
+	s.jobs = append(s.jobs, &job{Job: j, schedule: schedule, lockKey: lockKey(j.Name)})
+	return nil
+}
+
+// Start runs every job on its schedule until ctx is done or Stop is called
+func (s *Scheduler) Start(ctx context.Context) {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	for _, j := range s.jobs {
+		if err := s.register(ctx, j); err != nil {
+			log.Printf("scheduler: failed to register job %s: %v", j.Name, err)
+		}
+
+		s.running.Add(1)
+		go func(j *job) {
+			defer s.running.Done()
+			s.loop(ctx, j)
+		}(j)
+	}
+}
+
+// Stop cancels pending runs and waits for running jobs to return
+func (s *Scheduler) Stop() {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.running.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, j *job) {
+	for {
+		due := j.schedule.Next(time.Now())
+		if due.IsZero() {
+			log.Printf("scheduler: job %s has no future runs", j.Name)
+			return
+		}
+		s.db.WithContext(ctx).Model(&models.ScheduledJob{}).Where("name = ?", j.Name).Update("next_run_at", due)
+
+		delay := time.Until(due)
+		if j.Jitter > 0 {
+			delay += rand.N(j.Jitter)
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		if err := s.runOnce(ctx, j, due); err != nil {
+			log.Printf("scheduler: job %s: %v", j.Name, err)
+		}
+	}
+}
+
+// runOnce runs the job for the run scheduled at due, unless another instance
+// holds the job's lock or has already run it
+func (s *Scheduler) runOnce(ctx context.Context, j *job, due time.Time) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+
+	// Session-level advisory locks belong to a connection, so hold one for the whole run
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", j.lockKey).Scan(&locked); err != nil {
+		return err
+	}
+	if !locked {
+		return nil // Another instance is running it
+	}
+	defer conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", j.lockKey)
+
+	// Claim this run; with jitter, another instance may already have run it and released the lock
+	started := time.Now()
+	claim := s.db.WithContext(ctx).Model(&models.ScheduledJob{}).
+		Where("name = ? AND (last_due_at IS NULL OR last_due_at < ?)", j.Name, due).
+		Updates(map[string]interface{}{
+			"last_due_at":     due,
+			"last_started_at": started,
+			"last_status":     models.JobStatusRunning,
+			"last_run_by":     s.instance,
+		})
+	if claim.Error != nil {
+		return claim.Error
+	}
+	if claim.RowsAffected == 0 {
+		return nil
+	}
+
+	runErr := s.run(ctx, j)
+
+	updates := map[string]interface{}{
+		"last_finished_at": time.Now(),
+		"last_status":      models.JobStatusSucceeded,
+		"last_error":       "",
+		"run_count":        gorm.Expr("run_count + 1"),
+	}
+	if runErr != nil {
+		updates["last_status"] = models.JobStatusFailed
+		updates["last_error"] = runErr.Error()
+		updates["failure_count"] = gorm.Expr("failure_count + 1")
+		log.Printf("scheduler: job %s failed after %s: %v", j.Name, time.Since(started).Round(time.Millisecond), runErr)
+	}
+
+	// Record the result even when shutting down
+	return s.db.Model(&models.ScheduledJob{}).Where("name = ?", j.Name).Updates(updates).Error
+}
+
+// run calls the job, turning a panic into an error so one bad job can't stop the scheduler
+func (s *Scheduler) run(ctx context.Context, j *job) (err error) {
+	ctx, cancel := context.WithTimeout(ctx, j.Timeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return j.Run(ctx)
+}
+
+// register creates the job's row, or updates its schedule if it changed
+func (s *Scheduler) register(ctx context.Context, j *job) error {
+	return s.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "name"}},
+		DoUpdates: clause.AssignmentColumns([]string{"schedule", "updated_at"}),
+	}).Create(&models.ScheduledJob{Name: j.Name, Schedule: j.Schedule}).Error
+}
+
+// lockKey derives a stable advisory lock key from a job name
+func lockKey(name string) int64 {
+	h := fnv.New64a()
+	h.Write([]byte("kaizen-scheduler:" + name))
+	return int64(h.Sum64())
+}
@@ -0,0 +1,182 @@
+// internal/scheduler/scheduler_test.go
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/jedi116/kaizen-api/internal/models"
+)
+
+// testDB connects to TEST_DATABASE_URL (a migrated database), skipping the test without one
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// testJob registers a job with a unique name on s, deleting its row when the test ends
+func testJob(t *testing.T, s *Scheduler, name string, run func(ctx context.Context) error) *job {
+	t.Helper()
+	if err := s.Add(Job{Name: name, Schedule: "@hourly", Run: run}); err != nil {
+		t.Fatal(err)
+	}
+	j := s.jobs[len(s.jobs)-1]
+	if err := s.register(context.Background(), j); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.db.Delete(&models.ScheduledJob{}, "name = ?", name) })
+	return j
+}
+
+func jobState(t *testing.T, db *gorm.DB, name string) models.ScheduledJob {
+	t.Helper()
+	var state models.ScheduledJob
+	if err := db.First(&state, "name = ?", name).Error; err != nil {
+		t.Fatal(err)
+	}
+	return state
+}
+
+func TestRunOnceClaimsEachRunOnce(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	name := fmt.Sprintf("test_claim_%d", time.Now().UnixNano())
+
+	runs := 0
+	count := func(context.Context) error {
+		runs++
+		return nil
+	}
+	first, second := New(db), New(db)
+	first.instance, second.instance = "first", "second"
+	firstJob := testJob(t, first, name, count)
+	if err := second.Add(Job{Name: name, Schedule: "@hourly", Run: count}); err != nil {
+		t.Fatal(err)
+	}
+	secondJob := second.jobs[0]
+
+	due := time.Now().Truncate(time.Hour)
+	if err := first.runOnce(ctx, firstJob, due); err != nil {
+		t.Fatal(err)
+	}
+	// Another instance whose timer fires later for the same run skips it
+	if err := second.runOnce(ctx, secondJob, due); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 1 {
+		t.Fatalf("job ran %d times for one scheduled run, want 1", runs)
+	}
+	state := jobState(t, db, name)
+	if state.LastRunBy != "first" || state.LastStatus != models.JobStatusSucceeded || state.RunCount != 1 {
+		t.Errorf("after the first run: run by %q with status %q and %d runs", state.LastRunBy, state.LastStatus, state.RunCount)
+	}
+
+	// An earlier run that was missed isn't run after a later one
+	if err := second.runOnce(ctx, secondJob, due.Add(-time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 1 {
+		t.Errorf("job ran for a run older than the last one claimed")
+	}
+
+	// The next run goes to whichever instance claims it first
+	if err := second.runOnce(ctx, secondJob, due.Add(time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	state = jobState(t, db, name)
+	if runs != 2 || state.LastRunBy != "second" || state.RunCount != 2 {
+		t.Errorf("after the next run: %d runs, run by %q with %d counted", runs, state.LastRunBy, state.RunCount)
+	}
+}
+
+func TestRunOnceSkipsWhileAnotherInstanceHoldsTheLock(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	s := New(db)
+	runs := 0
+	j := testJob(t, s, fmt.Sprintf("test_lock_%d", time.Now().UnixNano()), func(context.Context) error {
+		runs++
+		return nil
+	})
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", j.lockKey).Scan(&locked); err != nil || !locked {
+		t.Fatalf("taking the job's lock: %v, %v", locked, err)
+	}
+
+	due := time.Now().Truncate(time.Hour)
+	if err := s.runOnce(ctx, j, due); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 0 {
+		t.Fatal("job ran while another instance held its lock")
+	}
+
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", j.lockKey); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.runOnce(ctx, j, due); err != nil {
+		t.Fatal(err)
+	}
+	if runs != 1 {
+		t.Errorf("job ran %d times once the lock was free, want 1", runs)
+	}
+}
+
+func TestRunOnceRecordsFailures(t *testing.T) {
+	db := testDB(t)
+	ctx := context.Background()
+	s := New(db)
+	suffix := time.Now().UnixNano()
+
+	failing := testJob(t, s, fmt.Sprintf("test_fail_%d", suffix), func(context.Context) error {
+		return errors.New("database unavailable")
+	})
+	panicking := testJob(t, s, fmt.Sprintf("test_panic_%d", suffix), func(context.Context) error {
+		panic("nil map")
+	})
+
+	due := time.Now().Truncate(time.Hour)
+	for _, tc := range []struct {
+		job  *job
+		want string
+	}{
+		{failing, "database unavailable"},
+		{panicking, "panic: nil map"},
+	} {
+		if err := s.runOnce(ctx, tc.job, due); err != nil {
+			t.Fatal(err)
+		}
+		state := jobState(t, db, tc.job.Name)
+		if state.LastStatus != models.JobStatusFailed || state.LastError != tc.want || state.FailureCount != 1 || state.RunCount != 1 {
+			t.Errorf("%s: status %q, error %q, %d failures in %d runs; want failed with %q, 1 in 1",
+				tc.job.Name, state.LastStatus, state.LastError, state.FailureCount, state.RunCount, tc.want)
+		}
+	}
+}
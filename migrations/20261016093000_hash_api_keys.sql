@@ -0,0 +1,10 @@
+-- Modify "api_keys" table
+ALTER TABLE "public"."api_keys" ADD COLUMN "prefix" character varying(16) NULL, ADD COLUMN "key_hash" character varying(64) NULL;
+-- Backfill "prefix" and "key_hash" from the plaintext keys
+UPDATE "public"."api_keys" SET "prefix" = left("key", 12), "key_hash" = encode(sha256(convert_to("key", 'UTF8')), 'hex');
+-- Drop index "idx_api_keys_key" from table: "api_keys"
+DROP INDEX "public"."idx_api_keys_key";
+-- Modify "api_keys" table
+ALTER TABLE "public"."api_keys" ALTER COLUMN "prefix" SET NOT NULL, ALTER COLUMN "key_hash" SET NOT NULL, DROP COLUMN "key";
+-- Create index "idx_api_keys_prefix" to table: "api_keys"
+CREATE UNIQUE INDEX "idx_api_keys_prefix" ON "public"."api_keys" ("prefix");
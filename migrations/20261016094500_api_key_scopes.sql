@@ -0,0 +1,2 @@
+-- Modify "api_keys" table
+ALTER TABLE "public"."api_keys" ADD COLUMN "scopes" character varying(255) NOT NULL DEFAULT '';
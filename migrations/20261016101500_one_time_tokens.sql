@@ -0,0 +1,18 @@
+-- Create "one_time_tokens" table
+CREATE TABLE "public"."one_time_tokens" (
+  "id" bigserial NOT NULL,
+  "user_id" bigint NOT NULL,
+  "purpose" character varying(30) NOT NULL,
+  "token_hash" character varying(64) NOT NULL,
+  "expires_at" timestamptz NOT NULL,
+  "used_at" timestamptz NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_users_one_time_tokens" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_one_time_tokens_expires_at" to table: "one_time_tokens"
+CREATE INDEX "idx_one_time_tokens_expires_at" ON "public"."one_time_tokens" ("expires_at");
+-- Create index "idx_one_time_tokens_token_hash" to table: "one_time_tokens"
+CREATE UNIQUE INDEX "idx_one_time_tokens_token_hash" ON "public"."one_time_tokens" ("token_hash");
+-- Create index "idx_one_time_tokens_user_id" to table: "one_time_tokens"
+CREATE INDEX "idx_one_time_tokens_user_id" ON "public"."one_time_tokens" ("user_id");
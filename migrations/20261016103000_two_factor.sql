@@ -0,0 +1,16 @@
+-- Modify "one_time_tokens" table
+ALTER TABLE "public"."one_time_tokens" ADD COLUMN "attempts" bigint NOT NULL DEFAULT 0;
+-- Modify "users" table
+ALTER TABLE "public"."users" ADD COLUMN "totp_secret" character varying(255) NULL, ADD COLUMN "totp_enabled" boolean NULL DEFAULT false, ADD COLUMN "totp_last_step" bigint NULL DEFAULT 0;
+-- Create "recovery_codes" table
+CREATE TABLE "public"."recovery_codes" (
+  "id" bigserial NOT NULL,
+  "user_id" bigint NOT NULL,
+  "code_hash" character varying(64) NOT NULL,
+  "used_at" timestamptz NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_users_recovery_codes" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_recovery_codes_user_id" to table: "recovery_codes"
+CREATE INDEX "idx_recovery_codes_user_id" ON "public"."recovery_codes" ("user_id");
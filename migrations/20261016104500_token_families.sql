@@ -0,0 +1,24 @@
+-- Modify "tokens" table
+ALTER TABLE "public"."tokens" ADD COLUMN "family_id" character varying(100) NULL, ADD COLUMN "rotated_at" timestamptz NULL;
+-- Existing tokens each start their own family
+UPDATE "public"."tokens" SET "family_id" = "id";
+-- Modify "tokens" table
+ALTER TABLE "public"."tokens" ALTER COLUMN "family_id" SET NOT NULL;
+-- Create index "idx_tokens_family_id" to table: "tokens"
+CREATE INDEX "idx_tokens_family_id" ON "public"."tokens" ("family_id");
+-- Create "security_events" table
+CREATE TABLE "public"."security_events" (
+  "id" bigserial NOT NULL,
+  "user_id" bigint NOT NULL,
+  "type" character varying(50) NOT NULL,
+  "ip_address" character varying(45) NULL,
+  "user_agent" character varying(255) NULL,
+  "details" text NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_users_security_events" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_security_events_type" to table: "security_events"
+CREATE INDEX "idx_security_events_type" ON "public"."security_events" ("type");
+-- Create index "idx_security_events_user_id" to table: "security_events"
+CREATE INDEX "idx_security_events_user_id" ON "public"."security_events" ("user_id");
@@ -0,0 +1,21 @@
+-- Create "sessions" table
+CREATE TABLE "public"."sessions" (
+  "id" character varying(100) NOT NULL,
+  "user_id" bigint NOT NULL,
+  "user_agent" character varying(255) NULL,
+  "ip_address" character varying(45) NULL,
+  "created_at" timestamptz NULL,
+  "last_seen_at" timestamptz NULL,
+  "expires_at" timestamptz NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_users_sessions" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_sessions_expires_at" to table: "sessions"
+CREATE INDEX "idx_sessions_expires_at" ON "public"."sessions" ("expires_at");
+-- Create index "idx_sessions_user_id" to table: "sessions"
+CREATE INDEX "idx_sessions_user_id" ON "public"."sessions" ("user_id");
+-- Backfill a session for every existing token family
+INSERT INTO "public"."sessions" ("id", "user_id", "created_at", "last_seen_at", "expires_at")
+SELECT "family_id", "user_id", min("created_at"), max("created_at"), max("expires_at")
+FROM "public"."tokens"
+GROUP BY "family_id", "user_id";
@@ -0,0 +1,11 @@
+-- Create "login_throttles" table
+CREATE TABLE "public"."login_throttles" (
+  "key" character varying(300) NOT NULL,
+  "failures" bigint NOT NULL DEFAULT 0,
+  "last_failure_at" timestamptz NOT NULL,
+  "locked_until" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("key")
+);
+-- Create index "idx_login_throttles_updated_at" to table: "login_throttles"
+CREATE INDEX "idx_login_throttles_updated_at" ON "public"."login_throttles" ("updated_at");
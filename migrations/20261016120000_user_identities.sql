@@ -0,0 +1,15 @@
+-- Create "user_identities" table
+CREATE TABLE "public"."user_identities" (
+  "id" bigserial NOT NULL,
+  "user_id" bigint NOT NULL,
+  "provider" character varying(50) NOT NULL,
+  "subject" character varying(255) NOT NULL,
+  "email" character varying(255) NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_users_identities" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION
+);
+-- Create index "idx_user_identities_provider_subject" to table: "user_identities"
+CREATE UNIQUE INDEX "idx_user_identities_provider_subject" ON "public"."user_identities" ("provider", "subject");
+-- Create index "idx_user_identities_user_id" to table: "user_identities"
+CREATE INDEX "idx_user_identities_user_id" ON "public"."user_identities" ("user_id");
@@ -0,0 +1,2 @@
+-- Modify "one_time_tokens" table
+ALTER TABLE "public"."one_time_tokens" ADD COLUMN "payload" character varying(255) NULL;
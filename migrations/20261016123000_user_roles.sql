@@ -0,0 +1,2 @@
+-- Modify "users" table
+ALTER TABLE "public"."users" ADD COLUMN "role" character varying(20) NOT NULL DEFAULT 'user', ADD COLUMN "disabled_at" timestamptz NULL;
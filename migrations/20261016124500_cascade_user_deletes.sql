@@ -0,0 +1,18 @@
+-- Modify "api_keys" table
+ALTER TABLE "public"."api_keys" DROP CONSTRAINT "fk_users_api_keys", ADD CONSTRAINT "fk_users_api_keys" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
+-- Modify "finance_categories" table
+ALTER TABLE "public"."finance_categories" DROP CONSTRAINT "fk_users_categories", ADD CONSTRAINT "fk_users_categories" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
+-- Modify "finance_journals" table
+ALTER TABLE "public"."finance_journals" DROP CONSTRAINT "fk_users_journals", ADD CONSTRAINT "fk_users_journals" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
+-- Modify "one_time_tokens" table
+ALTER TABLE "public"."one_time_tokens" DROP CONSTRAINT "fk_users_one_time_tokens", ADD CONSTRAINT "fk_users_one_time_tokens" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
+-- Modify "recovery_codes" table
+ALTER TABLE "public"."recovery_codes" DROP CONSTRAINT "fk_users_recovery_codes", ADD CONSTRAINT "fk_users_recovery_codes" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
+-- Modify "security_events" table
+ALTER TABLE "public"."security_events" DROP CONSTRAINT "fk_users_security_events", ADD CONSTRAINT "fk_users_security_events" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
+-- Modify "sessions" table
+ALTER TABLE "public"."sessions" DROP CONSTRAINT "fk_users_sessions", ADD CONSTRAINT "fk_users_sessions" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
+-- Modify "tokens" table
+ALTER TABLE "public"."tokens" DROP CONSTRAINT "fk_users_tokens", ADD CONSTRAINT "fk_users_tokens" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
+-- Modify "user_identities" table
+ALTER TABLE "public"."user_identities" DROP CONSTRAINT "fk_users_identities", ADD CONSTRAINT "fk_users_identities" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE;
@@ -0,0 +1,16 @@
+-- Create "scheduled_jobs" table
+CREATE TABLE "public"."scheduled_jobs" (
+  "name" character varying(100) NOT NULL,
+  "schedule" character varying(100) NOT NULL,
+  "last_due_at" timestamptz NULL,
+  "last_started_at" timestamptz NULL,
+  "last_finished_at" timestamptz NULL,
+  "last_status" character varying(20) NULL,
+  "last_error" text NULL,
+  "last_run_by" character varying(255) NULL,
+  "next_run_at" timestamptz NULL,
+  "run_count" bigint NOT NULL DEFAULT 0,
+  "failure_count" bigint NOT NULL DEFAULT 0,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("name")
+);
@@ -0,0 +1,33 @@
+-- Create "audit_events" table
+CREATE TABLE "public"."audit_events" (
+  "id" bigserial NOT NULL,
+  "user_id" bigint NULL,
+  "actor_id" bigint NULL,
+  "auth_method" character varying(20) NULL,
+  "email" character varying(255) NULL,
+  "type" character varying(50) NOT NULL,
+  "outcome" character varying(20) NOT NULL,
+  "ip_address" character varying(45) NULL,
+  "user_agent" character varying(255) NULL,
+  "details" text NULL,
+  "created_at" timestamptz NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_users_audit_events" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_audit_events_actor_id" to table: "audit_events"
+CREATE INDEX "idx_audit_events_actor_id" ON "public"."audit_events" ("actor_id");
+-- Create index "idx_audit_events_created_at" to table: "audit_events"
+CREATE INDEX "idx_audit_events_created_at" ON "public"."audit_events" ("created_at");
+-- Create index "idx_audit_events_email" to table: "audit_events"
+CREATE INDEX "idx_audit_events_email" ON "public"."audit_events" ("email");
+-- Create index "idx_audit_events_ip_address" to table: "audit_events"
+CREATE INDEX "idx_audit_events_ip_address" ON "public"."audit_events" ("ip_address");
+-- Create index "idx_audit_events_type" to table: "audit_events"
+CREATE INDEX "idx_audit_events_type" ON "public"."audit_events" ("type");
+-- Create index "idx_audit_events_user_id" to table: "audit_events"
+CREATE INDEX "idx_audit_events_user_id" ON "public"."audit_events" ("user_id");
+-- Keep recorded refresh token reuse as failed refreshes in the audit log
+INSERT INTO "public"."audit_events" ("user_id", "type", "outcome", "ip_address", "user_agent", "details", "created_at")
+SELECT "user_id", "type", 'failure', "ip_address", "user_agent", "details", "created_at" FROM "public"."security_events";
+-- Drop "security_events" table
+DROP TABLE "public"."security_events";
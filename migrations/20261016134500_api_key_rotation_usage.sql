@@ -0,0 +1,12 @@
+-- Modify "api_keys" table
+ALTER TABLE "public"."api_keys" ADD COLUMN "previous_prefix" character varying(16) NULL, ADD COLUMN "previous_key_hash" character varying(64) NULL, ADD COLUMN "previous_expires_at" timestamptz NULL, ADD COLUMN "rotated_at" timestamptz NULL, ADD COLUMN "allowed_cidrs" text NOT NULL DEFAULT '';
+-- Create index "idx_api_keys_previous_prefix" to table: "api_keys"
+CREATE INDEX "idx_api_keys_previous_prefix" ON "public"."api_keys" ("previous_prefix");
+-- Create "api_key_usages" table
+CREATE TABLE "public"."api_key_usages" (
+  "api_key_id" bigint NOT NULL,
+  "day" date NOT NULL,
+  "request_count" bigint NOT NULL DEFAULT 0,
+  PRIMARY KEY ("api_key_id", "day"),
+  CONSTRAINT "fk_api_keys_usages" FOREIGN KEY ("api_key_id") REFERENCES "public"."api_keys" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
@@ -0,0 +1,14 @@
+-- Modify "users" table
+ALTER TABLE "public"."users" ADD COLUMN "base_currency" character varying(3) NOT NULL DEFAULT 'USD';
+-- Modify "finance_journals" table
+ALTER TABLE "public"."finance_journals" ADD COLUMN "currency" character varying(3) NOT NULL DEFAULT 'USD';
+-- Create "exchange_rates" table
+CREATE TABLE "public"."exchange_rates" (
+  "base" character varying(3) NOT NULL,
+  "quote" character varying(3) NOT NULL,
+  "date" date NOT NULL,
+  "rate" numeric(20,10) NOT NULL,
+  "source" character varying(100) NULL,
+  "updated_at" timestamptz NULL,
+  PRIMARY KEY ("base", "quote", "date")
+);
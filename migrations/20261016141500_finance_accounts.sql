@@ -0,0 +1,33 @@
+-- Create "finance_accounts" table
+CREATE TABLE "public"."finance_accounts" (
+  "id" bigserial NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  "deleted_at" timestamptz NULL,
+  "user_id" bigint NOT NULL,
+  "name" character varying(100) NOT NULL,
+  "type" character varying(20) NOT NULL,
+  "currency" character varying(3) NOT NULL,
+  "opening_balance" numeric(15,2) NOT NULL DEFAULT 0,
+  "is_active" boolean NULL DEFAULT true,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_users_accounts" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_finance_accounts_deleted_at" to table: "finance_accounts"
+CREATE INDEX "idx_finance_accounts_deleted_at" ON "public"."finance_accounts" ("deleted_at");
+-- Create index "idx_finance_accounts_user_id" to table: "finance_accounts"
+CREATE INDEX "idx_finance_accounts_user_id" ON "public"."finance_accounts" ("user_id");
+-- Modify "finance_journals" table
+ALTER TABLE "public"."finance_journals" ADD COLUMN "account_id" bigint NULL;
+-- Give every user with journals a "Main account" per currency they used, and move their journals into it
+INSERT INTO "public"."finance_accounts" ("created_at", "updated_at", "user_id", "name", "type", "currency")
+SELECT NOW(), NOW(), "user_id", 'Main account (' || "currency" || ')', 'checking', "currency"
+FROM "public"."finance_journals"
+GROUP BY "user_id", "currency";
+UPDATE "public"."finance_journals" AS "j"
+SET "account_id" = "a"."id"
+FROM "public"."finance_accounts" AS "a"
+WHERE "a"."user_id" = "j"."user_id" AND "a"."currency" = "j"."currency";
+ALTER TABLE "public"."finance_journals" ALTER COLUMN "account_id" SET NOT NULL, ADD CONSTRAINT "fk_finance_accounts_journals" FOREIGN KEY ("account_id") REFERENCES "public"."finance_accounts" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION;
+-- Create index "idx_finance_journals_account_id" to table: "finance_journals"
+CREATE INDEX "idx_finance_journals_account_id" ON "public"."finance_journals" ("account_id");
@@ -0,0 +1,33 @@
+-- Create "finance_transfers" table
+CREATE TABLE "public"."finance_transfers" (
+  "id" bigserial NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  "deleted_at" timestamptz NULL,
+  "user_id" bigint NOT NULL,
+  "from_account_id" bigint NOT NULL,
+  "to_account_id" bigint NOT NULL,
+  "amount" numeric(15,2) NOT NULL,
+  "to_amount" numeric(15,2) NOT NULL,
+  "title" character varying(255) NOT NULL,
+  "description" text NULL,
+  "date" date NOT NULL,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_finance_transfers_from_account" FOREIGN KEY ("from_account_id") REFERENCES "public"."finance_accounts" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION,
+  CONSTRAINT "fk_finance_transfers_to_account" FOREIGN KEY ("to_account_id") REFERENCES "public"."finance_accounts" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION,
+  CONSTRAINT "fk_users_transfers" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_finance_transfers_date" to table: "finance_transfers"
+CREATE INDEX "idx_finance_transfers_date" ON "public"."finance_transfers" ("date");
+-- Create index "idx_finance_transfers_deleted_at" to table: "finance_transfers"
+CREATE INDEX "idx_finance_transfers_deleted_at" ON "public"."finance_transfers" ("deleted_at");
+-- Create index "idx_finance_transfers_from_account_id" to table: "finance_transfers"
+CREATE INDEX "idx_finance_transfers_from_account_id" ON "public"."finance_transfers" ("from_account_id");
+-- Create index "idx_finance_transfers_to_account_id" to table: "finance_transfers"
+CREATE INDEX "idx_finance_transfers_to_account_id" ON "public"."finance_transfers" ("to_account_id");
+-- Create index "idx_finance_transfers_user_id" to table: "finance_transfers"
+CREATE INDEX "idx_finance_transfers_user_id" ON "public"."finance_transfers" ("user_id");
+-- Modify "finance_journals" table
+ALTER TABLE "public"."finance_journals" ALTER COLUMN "category_id" DROP NOT NULL, ADD COLUMN "transfer_id" bigint NULL, ADD CONSTRAINT "fk_finance_transfers_journals" FOREIGN KEY ("transfer_id") REFERENCES "public"."finance_transfers" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION;
+-- Create index "idx_finance_journals_transfer_id" to table: "finance_journals"
+CREATE INDEX "idx_finance_journals_transfer_id" ON "public"."finance_journals" ("transfer_id");
@@ -0,0 +1,25 @@
+-- Create "finance_budgets" table
+CREATE TABLE "public"."finance_budgets" (
+  "id" bigserial NOT NULL,
+  "created_at" timestamptz NULL,
+  "updated_at" timestamptz NULL,
+  "deleted_at" timestamptz NULL,
+  "user_id" bigint NOT NULL,
+  "category_id" bigint NOT NULL,
+  "amount" numeric(15,2) NOT NULL,
+  "currency" character varying(3) NOT NULL,
+  "start_month" date NOT NULL,
+  "end_month" date NULL,
+  "rollover" boolean NULL DEFAULT false,
+  PRIMARY KEY ("id"),
+  CONSTRAINT "fk_finance_budgets_category" FOREIGN KEY ("category_id") REFERENCES "public"."finance_categories" ("id") ON UPDATE NO ACTION ON DELETE NO ACTION,
+  CONSTRAINT "fk_users_budgets" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE CASCADE
+);
+-- Create index "idx_finance_budgets_category_id" to table: "finance_budgets"
+CREATE INDEX "idx_finance_budgets_category_id" ON "public"."finance_budgets" ("category_id");
+-- Create index "idx_finance_budgets_deleted_at" to table: "finance_budgets"
+CREATE INDEX "idx_finance_budgets_deleted_at" ON "public"."finance_budgets" ("deleted_at");
+-- Create index "idx_finance_budgets_start_month" to table: "finance_budgets"
+CREATE INDEX "idx_finance_budgets_start_month" ON "public"."finance_budgets" ("start_month");
+-- Create index "idx_finance_budgets_user_id" to table: "finance_budgets"
+CREATE INDEX "idx_finance_budgets_user_id" ON "public"."finance_budgets" ("user_id");
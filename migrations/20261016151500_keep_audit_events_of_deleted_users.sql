@@ -0,0 +1,2 @@
+-- Modify "audit_events" table
+ALTER TABLE "public"."audit_events" DROP CONSTRAINT "fk_users_audit_events", ADD CONSTRAINT "fk_users_audit_events" FOREIGN KEY ("user_id") REFERENCES "public"."users" ("id") ON UPDATE NO ACTION ON DELETE SET NULL;